@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/3WDeveloper-GM/library_app/backend/client"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+var authorHeader = []string{"ID", "NAME", "BOOKS"}
+
+func authorRow(author client.Author) []string {
+	return []string{
+		strconv.FormatInt(author.ID, 10),
+		author.Name,
+		strconv.Itoa(int(author.BooksAuthored)),
+	}
+}
+
+func authorsList(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "authors list")
+
+	var list client.ListOptions
+	var all bool
+	fs.IntVar(&list.Page, "page", 1, "page to list")
+	fs.IntVar(&list.PageSize, "page-size", 20, "authors per page")
+	fs.StringVar(&list.Sort, "sort", "name", "sort order (id|name, prefixed with - for descending)")
+	fs.BoolVar(&all, "all", false, "list every page from -page on")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	cat, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	authors := []client.Author{}
+	var metadata client.Metadata
+
+	for {
+		page, meta, err := cat.ListAuthors(ctx, list)
+		if err != nil {
+			return err
+		}
+		authors = append(authors, page...)
+		metadata = meta
+
+		if !all || len(page) == 0 || meta.CurrentPage >= meta.LastPage {
+			break
+		}
+		list.Page++
+	}
+
+	rows := make([][]string, len(authors))
+	for i, author := range authors {
+		rows[i] = authorRow(author)
+	}
+
+	return opts.printer(os.Stdout).print(struct {
+		Authors  []client.Author `json:"authors"`
+		Metadata client.Metadata `json:"metadata"`
+	}{authors, metadata}, authorHeader, rows)
+}
+
+// authorsMerge folds a duplicate author into another, against the database only.
+func authorsMerge(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "authors merge")
+
+	var into, from int64
+	fs.Int64Var(&into, "into", 0, "id of the author that is kept")
+	fs.Int64Var(&from, "from", 0, "id of the duplicate author, its books move to -into")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	switch {
+	case into < 1 || from < 1:
+		return errors.New("libraryctl authors merge: -into and -from must be positive author ids")
+	case into == from:
+		return errors.New("libraryctl authors merge: -into and -from must be different authors")
+	case opts.server != "":
+		return errors.New("libraryctl authors merge: only available against the database, drop -server")
+	}
+
+	_, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	var author *books.ReadAuthor
+	err = app.Tx.Run(ctx, "merge", app.Models.Authors.BeginTx, func(ctx context.Context, tx books.Tx) (err error) {
+		author, err = app.Models.Authors.MergeAuthors(ctx, tx, into, from)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, books.ErrNotFound) {
+			return fmt.Errorf("libraryctl authors merge: author %d or %d could not be found", into, from)
+		}
+		return err
+	}
+
+	merged := client.Author{ID: author.ID, Name: author.Name, Identifier: author.Identifier, BooksAuthored: author.Books_authored}
+
+	return opts.printer(os.Stdout).print(merged, authorHeader, [][]string{authorRow(merged)})
+}
@@ -0,0 +1,262 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/3WDeveloper-GM/library_app/backend/client"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+)
+
+var bookHeader = []string{"ID", "TITLE", "AUTHORS", "PUBLISHER", "YEAR", "PAGES", "GENRES", "ISBN", "VERSION"}
+
+func bookRow(entry client.Entry) []string {
+	authors := make([]string, len(entry.Authors))
+	for i, author := range entry.Authors {
+		authors[i] = author.Name
+	}
+
+	return []string{
+		strconv.FormatInt(entry.Book.ID, 10),
+		entry.Book.Title,
+		strings.Join(authors, "; "),
+		entry.Book.Publisher,
+		strconv.Itoa(int(entry.Book.Year)),
+		strconv.Itoa(int(entry.Book.PageCount)),
+		strings.Join(entry.Book.Genres, ", "),
+		entry.Book.ISBN,
+		strconv.Itoa(int(entry.Book.Version)),
+	}
+}
+
+func printEntry(p *printer, entry *client.Entry) error {
+	return p.print(entry, bookHeader, [][]string{bookRow(*entry)})
+}
+
+func requireID(fs *flag.FlagSet, id int64) error {
+	if id < 1 {
+		return errors.New("libraryctl " + fs.Name() + ": -id must be a positive book id")
+	}
+	return nil
+}
+
+func booksGet(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "books get")
+
+	var id int64
+	fs.Int64Var(&id, "id", 0, "id of the book")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	if err := requireID(fs, id); err != nil {
+		return err
+	}
+
+	cat, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	entry, err := cat.GetBook(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return printEntry(opts.printer(os.Stdout), entry)
+}
+
+func booksList(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "books list")
+
+	var q client.BookQuery
+	var all bool
+	fs.StringVar(&q.Query, "q", "", "match titles, publishers and author names")
+	fs.StringVar(&q.Genre, "genre", "", "only list books with this genre")
+	fs.Int64Var(&q.AuthorID, "author-id", 0, "only list books by this author")
+	fs.IntVar(&q.Page, "page", 1, "page to list")
+	fs.IntVar(&q.PageSize, "page-size", 20, "books per page")
+	fs.StringVar(&q.Sort, "sort", "id", "sort order (id|title|year|created_at, prefixed with - for descending)")
+	fs.BoolVar(&all, "all", false, "list every page from -page on")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	cat, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	entries := []client.Entry{}
+	var metadata client.Metadata
+
+	for {
+		page, meta, err := cat.ListBooks(ctx, q)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, page...)
+		metadata = meta
+
+		if !all || len(page) == 0 || meta.CurrentPage >= meta.LastPage {
+			break
+		}
+		q.Page++
+	}
+
+	rows := make([][]string, len(entries))
+	for i, entry := range entries {
+		rows[i] = bookRow(entry)
+	}
+
+	return opts.printer(os.Stdout).print(struct {
+		Books    []client.Entry  `json:"books"`
+		Metadata client.Metadata `json:"metadata"`
+	}{entries, metadata}, bookHeader, rows)
+}
+
+func booksCreate(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "books create")
+
+	var book client.NewBook
+	var year, pages int
+	var genres, authors stringList
+	fs.StringVar(&book.Title, "title", "", "title of the book")
+	fs.StringVar(&book.Publisher, "publisher", "", "publisher of the book")
+	fs.IntVar(&year, "year", 0, "publication year")
+	fs.IntVar(&pages, "pages", 0, "page count")
+	fs.StringVar(&book.ISBN, "isbn", "", "ISBN-10 or ISBN-13")
+	fs.Var(&genres, "genre", "genre of the book, repeat for several")
+	fs.Var(&authors, "author", "author of the book, repeat for several")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	book.Year = int32(year)
+	book.PageCount = int32(pages)
+	book.Genres = genres
+	book.Authors = authors
+
+	cat, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	entry, err := cat.CreateBook(ctx, book)
+	if err != nil {
+		return err
+	}
+
+	return printEntry(opts.printer(os.Stdout), entry)
+}
+
+func booksUpdate(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "books update")
+
+	var id int64
+	var title, publisher, isbn string
+	var year, pages, version int
+	var genres, authors stringList
+	fs.Int64Var(&id, "id", 0, "id of the book")
+	fs.StringVar(&title, "title", "", "new title")
+	fs.StringVar(&publisher, "publisher", "", "new publisher")
+	fs.IntVar(&year, "year", 0, "new publication year")
+	fs.IntVar(&pages, "pages", 0, "new page count")
+	fs.StringVar(&isbn, "isbn", "", "new ISBN")
+	fs.Var(&genres, "genre", "replaces the genres, repeat for several")
+	fs.Var(&authors, "author", "replaces the authors, repeat for several")
+	fs.IntVar(&version, "version", 0, "version the book is at, the update fails when it moved on (required against a server)")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	if err := requireID(fs, id); err != nil {
+		return err
+	}
+
+	//only the flags given
+	var update client.BookUpdate
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "title":
+			update.Title = &title
+		case "publisher":
+			update.Publisher = &publisher
+		case "year":
+			y := int32(year)
+			update.Year = &y
+		case "pages":
+			p := int32(pages)
+			update.PageCount = &p
+		case "isbn":
+			update.ISBN = &isbn
+		case "genre":
+			update.Genres = genres
+		case "author":
+			update.Authors = authors
+		case "version":
+			v := int32(version)
+			update.Version = &v
+		}
+	})
+
+	cat, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	entry, err := cat.UpdateBook(ctx, id, update)
+	if err != nil {
+		return err
+	}
+
+	return printEntry(opts.printer(os.Stdout), entry)
+}
+
+func booksDelete(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "books delete")
+
+	var id int64
+	fs.Int64Var(&id, "id", 0, "id of the book")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	if err := requireID(fs, id); err != nil {
+		return err
+	}
+
+	cat, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	if err := cat.DeleteBook(ctx, id); err != nil {
+		return err
+	}
+
+	return opts.printer(os.Stdout).message("book %d moved to the trash", id)
+}
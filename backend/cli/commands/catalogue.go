@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/3WDeveloper-GM/library_app/backend/client"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+var (
+	bookSortSafeList   = []string{"id", "title", "year", "created_at", "-id", "-title", "-year", "-created_at"}
+	authorSortSafeList = []string{"id", "name", "-id", "-name"}
+)
+
+// catalogue is the database or a server, for the books and authors commands.
+type catalogue interface {
+	GetBook(ctx context.Context, id int64) (*client.Entry, error)
+	ListBooks(ctx context.Context, q client.BookQuery) ([]client.Entry, client.Metadata, error)
+	CreateBook(ctx context.Context, book client.NewBook) (*client.Entry, error)
+	UpdateBook(ctx context.Context, id int64, update client.BookUpdate) (*client.Entry, error)
+	DeleteBook(ctx context.Context, id int64) error
+	ListAuthors(ctx context.Context, opts client.ListOptions) ([]client.Author, client.Metadata, error)
+}
+
+type remoteCatalogue struct {
+	*client.Client
+}
+
+type dbCatalogue struct {
+	app *config.App
+}
+
+func notFound(kind string, id int64) error {
+	return fmt.Errorf("%s %d could not be found", kind, id)
+}
+
+func (d *dbCatalogue) getEntry(ctx context.Context, id int64) (*books.ReadEntry, error) {
+	entry := &books.ReadEntry{Book: books.ReadBook{ID: id}}
+
+	err := d.app.Models.Read.Get(ctx, nil, entry)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFound("book", id)
+		}
+		return nil, err
+	}
+
+	entry.Authors = make([]books.ReadAuthor, len(entry.List.Name))
+	entry.Convert()
+
+	return entry, nil
+}
+
+func (d *dbCatalogue) GetBook(ctx context.Context, id int64) (*client.Entry, error) {
+	entry, err := d.getEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientEntry(entry), nil
+}
+
+func (d *dbCatalogue) ListBooks(ctx context.Context, q client.BookQuery) ([]client.Entry, client.Metadata, error) {
+	filters := listFilters(q.ListOptions, "id", bookSortSafeList)
+
+	v := validator.NewValidator()
+	if !filters.ValidateFilters(v) {
+		return nil, client.Metadata{}, fieldErrors(v.Errors)
+	}
+
+	filter := books.ListFilter{Query: q.Query, Genre: q.Genre, AuthorID: q.AuthorID}
+
+	entries, metadata, err := d.app.Models.Read.List(ctx, filter, filters)
+	if err != nil {
+		return nil, client.Metadata{}, err
+	}
+
+	out := make([]client.Entry, len(entries))
+	for i, entry := range entries {
+		out[i] = *clientEntry(entry)
+	}
+
+	return out, client.Metadata(metadata), nil
+}
+
+func (d *dbCatalogue) CreateBook(ctx context.Context, book client.NewBook) (*client.Entry, error) {
+	entry := &books.CreateBookEntry{
+		Book: &books.Book{
+			Title:     book.Title,
+			Publisher: book.Publisher,
+			Year:      book.Year,
+			PageCount: book.PageCount,
+			Genres:    book.Genres,
+			ISBN:      books.NormalizeISBN(book.ISBN),
+		},
+		Authors: &books.Authors{
+			List: book.Authors,
+		},
+	}
+
+	v := validator.NewValidator()
+	if !entry.ValidateEntry(v) {
+		return nil, fieldErrors(v.Errors)
+	}
+
+	entry.Normalize()
+
+	read := &books.ReadEntry{}
+	err := d.app.Tx.Retry(ctx, "create", func(ctx context.Context) error {
+		return d.app.Models.Create.Save(ctx, entry, read)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clientEntry(read), nil
+}
+
+func (d *dbCatalogue) UpdateBook(ctx context.Context, id int64, update client.BookUpdate) (*client.Entry, error) {
+	old, err := d.getEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Version != nil && *update.Version != old.Book.Version {
+		return nil, fmt.Errorf("book %d is at version %d, not %d", id, old.Book.Version, *update.Version)
+	}
+
+	patch := books.BookPatch{
+		Title:     update.Title,
+		Publisher: update.Publisher,
+		Year:      update.Year,
+		PageCount: update.PageCount,
+		ISBN:      update.ISBN,
+	}
+	if update.Genres != nil {
+		patch.Genres = &update.Genres
+	}
+	if update.Authors != nil {
+		patch.Authors = &update.Authors
+	}
+
+	entry := patch.Apply(old)
+
+	v := validator.NewValidator()
+	if !entry.ValidateEntry(v) {
+		return nil, fieldErrors(v.Errors)
+	}
+
+	err = d.app.Tx.Retry(ctx, "update", func(ctx context.Context) error {
+		attempt := *old
+		return d.app.Models.Update.Save(ctx, entry, &attempt)
+	})
+	if err != nil {
+		if errors.Is(err, books.ErrEditConflict) {
+			return nil, fmt.Errorf("book %d was modified concurrently, please try again", id)
+		}
+		return nil, err
+	}
+
+	return d.GetBook(ctx, id)
+}
+
+func (d *dbCatalogue) DeleteBook(ctx context.Context, id int64) error {
+	err := d.app.Tx.Run(ctx, "delete", d.app.Models.Delete.BeginTx, func(ctx context.Context, tx books.Tx) error {
+		return d.app.Models.Delete.DeleteBook(ctx, tx, &books.DeleteID{ID: id})
+	})
+	if errors.Is(err, books.ErrNotFound) {
+		return notFound("book", id)
+	}
+
+	return err
+}
+
+func (d *dbCatalogue) ListAuthors(ctx context.Context, opts client.ListOptions) ([]client.Author, client.Metadata, error) {
+	filters := listFilters(opts, "name", authorSortSafeList)
+
+	v := validator.NewValidator()
+	if !filters.ValidateFilters(v) {
+		return nil, client.Metadata{}, fieldErrors(v.Errors)
+	}
+
+	summaries, metadata, err := d.app.Models.Authors.ListAuthors(ctx, filters)
+	if err != nil {
+		return nil, client.Metadata{}, err
+	}
+
+	authors := make([]client.Author, len(summaries))
+	for i, summary := range summaries {
+		authors[i] = client.Author{ID: summary.ID, Name: summary.Name, BooksAuthored: summary.BooksAuthored}
+	}
+
+	return authors, client.Metadata(metadata), nil
+}
+
+func listFilters(opts client.ListOptions, sort string, safeList []string) internal.Filters {
+	filters := internal.Filters{
+		Page:         opts.Page,
+		PageSize:     opts.PageSize,
+		Sort:         opts.Sort,
+		SortSafeList: safeList,
+	}
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.PageSize == 0 {
+		filters.PageSize = 20
+	}
+	if filters.Sort == "" {
+		filters.Sort = sort
+	}
+	return filters
+}
+
+func clientEntry(entry *books.ReadEntry) *client.Entry {
+	out := &client.Entry{
+		Book:    client.Book(entry.Book),
+		Authors: make([]client.Author, len(entry.Authors)),
+	}
+
+	for i, author := range entry.Authors {
+		out.Authors[i] = client.Author{
+			ID:            author.ID,
+			Name:          author.Name,
+			Identifier:    author.Identifier,
+			BooksAuthored: author.Books_authored,
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/client"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+const ctlUsage = `usage: libraryctl <command> [flags]
+
+commands:
+  books get|list|create|update|delete   manage the catalogue
+  authors list|merge                     list authors, fold a duplicate into another
+  import                                 load a csv or MARC file into the database
+  export                                 dump the catalogue
+  migrate up|down|status|goto            apply or roll back the embedded migrations (database only)
+  users create                           add a cataloguer and print their api token
+  verify                                 check the catalogue for inconsistencies
+
+The books and authors commands work against the database given by -dsn-db,
+or against a running server with -server. Run a command with -h for its flags.
+`
+
+// Ctl runs a libraryctl command.
+func Ctl(app *config.App, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, ctlUsage)
+		return errors.New("libraryctl: missing command")
+	}
+
+	name, args := args[0], args[1:]
+
+	switch name {
+	case "books":
+		return subcommand(app, "books", args, map[string]func(*config.App, []string) error{
+			"get":    booksGet,
+			"list":   booksList,
+			"create": booksCreate,
+			"update": booksUpdate,
+			"delete": booksDelete,
+		})
+	case "authors":
+		return subcommand(app, "authors", args, map[string]func(*config.App, []string) error{
+			"list":  authorsList,
+			"merge": authorsMerge,
+		})
+	case "users":
+		return subcommand(app, "users", args, map[string]func(*config.App, []string) error{
+			"create": usersCreate,
+		})
+	case "import":
+		return Import(app, args)
+	case "export":
+		return Export(app, args)
+	case "migrate":
+		return Migrate(app, args)
+	case "verify":
+		return Verify(app, args)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, ctlUsage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, ctlUsage)
+		return fmt.Errorf("libraryctl: unknown command %q", name)
+	}
+}
+
+func subcommand(app *config.App, group string, args []string, commands map[string]func(*config.App, []string) error) error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(args) == 0 {
+		return fmt.Errorf("libraryctl %s: expected one of %s", group, strings.Join(names, ", "))
+	}
+
+	run, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("libraryctl %s: unknown command %q, expected one of %s", group, args[0], strings.Join(names, ", "))
+	}
+
+	return run(app, args[1:])
+}
+
+// ctlFlags are shared by the commands that can run remotely.
+type ctlFlags struct {
+	server  string
+	token   string
+	actor   string
+	output  string
+	timeout time.Duration
+}
+
+func newCtlFlagSet(app *config.App, name string) (*flag.FlagSet, *ctlFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	app.BindFlags(fs)
+
+	opts := &ctlFlags{}
+	fs.StringVar(&opts.server, "server", os.Getenv("LIBRARY_SERVER"), "base url of a running server, the database is used directly when empty")
+	fs.StringVar(&opts.token, "token", os.Getenv("LIBRARY_ADMIN_TOKEN"), "admin or user token sent to the server")
+	fs.StringVar(&opts.actor, "actor", "", "actor recorded in the audit log for the changes, a server records it as unverified")
+	fs.StringVar(&opts.output, "output", "table", "output format (table|json)")
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "maximum duration of the command")
+
+	return fs, opts
+}
+
+// open returns the catalogue the command works on and a func releasing it.
+func (opts *ctlFlags) open(app *config.App) (catalogue, func(), error) {
+	if opts.output != "table" && opts.output != "json" {
+		return nil, nil, fmt.Errorf("libraryctl: -output must be table or json, got %q", opts.output)
+	}
+
+	if opts.server != "" {
+		c, err := client.New(opts.server)
+		if err != nil {
+			return nil, nil, err
+		}
+		c.Token = opts.token
+		c.Actor = opts.actor
+
+		return remoteCatalogue{c}, func() {}, nil
+	}
+
+	if err := openDB(app); err != nil {
+		return nil, nil, err
+	}
+
+	return &dbCatalogue{app: app}, app.CloseDB, nil
+}
+
+// openDB connects the models, the way the server does at startup.
+func openDB(app *config.App) error {
+	app.SetLogger()
+	if err := app.SetDB(); err != nil {
+		return fmt.Errorf("libraryctl: %w", err)
+	}
+	app.SetModels()
+	return nil
+}
+
+func (opts *ctlFlags) context() (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	if opts.actor != "" {
+		ctx = audit.NewContext(ctx, audit.Meta{Actor: opts.actor})
+	}
+	return context.WithTimeout(ctx, opts.timeout)
+}
+
+func (opts *ctlFlags) printer(w io.Writer) *printer {
+	return &printer{w: w, json: opts.output == "json"}
+}
+
+// stringList collects a repeated flag, e.g. -author A -author B.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// fieldErrors are the validation errors of the models.
+type fieldErrors map[string]string
+
+func (e fieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field, message := range e {
+		fields = append(fields, field+": "+message)
+	}
+	sort.Strings(fields)
+
+	return "invalid input: " + strings.Join(fields, "; ")
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/export"
+)
+
+// Export dumps the catalogue into a file, or stdout when -out is not set.
+func Export(app *config.App, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	app.BindFlags(fs)
+
+	var formatName, out, modifiedSince string
+	var filter books.ExportFilter
+	var yearFrom, yearTo int
+	var timeout time.Duration
+
+	fs.StringVar(&formatName, "format", "ndjson", "output format (csv|json|ndjson)")
+	fs.StringVar(&out, "out", "", "file to write the export to, defaults to stdout")
+	fs.StringVar(&filter.Genre, "genre", "", "only export books with this genre")
+	fs.StringVar(&filter.Publisher, "publisher", "", "only export books from this publisher")
+	fs.IntVar(&yearFrom, "year-from", 0, "only export books published in or after this year")
+	fs.IntVar(&yearTo, "year-to", 0, "only export books published in or before this year")
+	fs.StringVar(&modifiedSince, "modified-since", "", "only export books modified since this RFC 3339 timestamp")
+	fs.DurationVar(&timeout, "timeout", 2*time.Hour, "maximum duration of the export")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	format, ok := export.Lookup(formatName)
+	if !ok {
+		return export.UnknownFormatError(formatName)
+	}
+
+	filter.YearFrom = int32(yearFrom)
+	filter.YearTo = int32(yearTo)
+
+	if modifiedSince != "" {
+		t, err := time.Parse(time.RFC3339, modifiedSince)
+		if err != nil {
+			return errors.New("export: -modified-since must be an RFC 3339 timestamp")
+		}
+		filter.ModifiedSince = t
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	app.SetLogger()
+	if err := app.SetDB(); err != nil {
+		return err
+	}
+	defer app.CloseDB()
+	app.SetModels()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	count, err := export.Run(ctx, app.Models.Read, format, filter, w, nil)
+	if err != nil {
+		return err
+	}
+
+	//stdout may be carrying the export itself
+	fmt.Fprintf(os.Stderr, "exported %d books as %s\n", count, format.Name)
+	return nil
+}
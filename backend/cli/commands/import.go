@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/importer"
+)
+
+// Import loads a CSV or MARC catalogue into the database and prints the report.
+func Import(app *config.App, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	app.BindFlags(fs)
+
+	var file, format, mappingFlag string
+	var dryRun bool
+	var batchSize int
+	var timeout time.Duration
+
+	fs.StringVar(&file, "file", "", "file to import")
+	fs.StringVar(&format, "format", "csv", "format of the file: csv, marc or marcxml")
+	fs.StringVar(&mappingFlag, "mapping", "", "csv column mapping overrides, e.g. title=Book Title,authors=Written by")
+	fs.BoolVar(&dryRun, "dry-run", false, "validate the file without inserting anything")
+	fs.IntVar(&batchSize, "batch-size", 100, "rows inserted per transaction")
+	fs.DurationVar(&timeout, "timeout", 30*time.Minute, "maximum duration of the import")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	if file == "" {
+		return errors.New("import: -file is required")
+	}
+
+	mapping, err := importer.ParseMapping(mappingFlag)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var rows []*importer.Row
+
+	switch format {
+	case "csv":
+		rows, err = importer.ReadCSV(f, mapping)
+	case "marc", "marcxml":
+		rows, err = importer.ReadMARC(f, format == "marcxml")
+	default:
+		err = fmt.Errorf("import: unsupported format %q, expected csv, marc or marcxml", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	app.SetLogger()
+	if err := app.SetDB(); err != nil {
+		return err
+	}
+	defer app.CloseDB()
+	app.SetModels()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	imp := &importer.Importer{Create: app.Models.Create, Tx: app.Tx}
+
+	report, err := imp.Run(ctx, rows, importer.Options{
+		DryRun:    dryRun,
+		BatchSize: batchSize,
+		OnError: func(line int, err error) {
+			app.Log.Error().Err(err).Int("line", line).Msg("import row failed")
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	return enc.Encode(report)
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/migrate"
+)
+
+// Migrate runs migrate up, down, status or goto with the embedded migrations.
+func Migrate(app *config.App, args []string) error {
+	return subcommand(app, "migrate", args, map[string]func(*config.App, []string) error{
+		"up":     migrateUp,
+		"down":   migrateDown,
+		"status": migrateStatus,
+		"goto":   migrateGoto,
+	})
+}
+
+func openMigrator(app *config.App, opts *ctlFlags) (*migrate.Migrator, func(), error) {
+	if opts.server != "" {
+		return nil, nil, errors.New("libraryctl migrate: only available against the database, drop -server")
+	}
+
+	app.ConfigFlags.AutoMigrate = false
+
+	_, done, err := opts.open(app)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m, err := app.Migrator()
+	if err != nil {
+		done()
+		return nil, nil, err
+	}
+
+	return m, done, nil
+}
+
+func printMigrations(opts *ctlFlags, migrations []migrate.Migration, direction func(migrate.Migration) string) error {
+	p := opts.printer(os.Stdout)
+
+	if len(migrations) == 0 {
+		return p.message("nothing to migrate")
+	}
+
+	type result struct {
+		Version   int64  `json:"version"`
+		Name      string `json:"name"`
+		Direction string `json:"direction"`
+	}
+
+	results := make([]result, len(migrations))
+	rows := make([][]string, len(migrations))
+	for i, m := range migrations {
+		results[i] = result{m.Version, m.Name, direction(m)}
+		rows[i] = []string{strconv.FormatInt(m.Version, 10), m.Name, results[i].Direction}
+	}
+
+	return p.print(results, []string{"VERSION", "NAME", "DIRECTION"}, rows)
+}
+
+func migrateUp(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "migrate up")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	m, done, err := openMigrator(app, opts)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		return err
+	}
+
+	return printMigrations(opts, applied, func(migrate.Migration) string { return migrate.DirectionUp })
+}
+
+func migrateDown(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "migrate down")
+
+	var steps int
+	fs.IntVar(&steps, "steps", 1, "number of migrations to roll back")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	if steps < 1 {
+		return errors.New("libraryctl migrate down: -steps must be at least 1")
+	}
+
+	m, done, err := openMigrator(app, opts)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	rolledBack, err := m.Down(ctx, steps)
+	if err != nil {
+		return err
+	}
+
+	return printMigrations(opts, rolledBack, func(migrate.Migration) string { return migrate.DirectionDown })
+}
+
+func migrateGoto(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "migrate goto")
+
+	var version int64
+	fs.Int64Var(&version, "version", -1, "version to move to, 0 rolls every migration back")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	if version < 0 {
+		return errors.New("libraryctl migrate goto: -version is required")
+	}
+
+	m, done, err := openMigrator(app, opts)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	moved, err := m.Goto(ctx, version)
+	if err != nil {
+		return err
+	}
+
+	return printMigrations(opts, moved, func(m migrate.Migration) string {
+		if m.Version > version {
+			return migrate.DirectionDown
+		}
+		return migrate.DirectionUp
+	})
+}
+
+func migrateStatus(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "migrate status")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	m, done, err := openMigrator(app, opts)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	type status struct {
+		Version   int64      `json:"version"`
+		Name      string     `json:"name"`
+		Applied   bool       `json:"applied"`
+		AppliedAt *time.Time `json:"applied_at,omitempty"`
+		Modified  bool       `json:"modified"`
+		Checksum  string     `json:"checksum"`
+	}
+
+	results := make([]status, len(statuses))
+	rows := make([][]string, len(statuses))
+	modified := 0
+
+	for i, s := range statuses {
+		results[i] = status{Version: s.Version, Name: s.Name, Applied: s.Applied, Modified: s.Modified, Checksum: s.Checksum}
+
+		state := "pending"
+		if s.Applied {
+			appliedAt := s.AppliedAt
+			results[i].AppliedAt = &appliedAt
+			state = "applied " + appliedAt.Format(time.RFC3339)
+		}
+		if s.Modified {
+			state += " (modified since)"
+			modified++
+		}
+
+		rows[i] = []string{strconv.FormatInt(s.Version, 10), s.Name, state}
+	}
+
+	if err := opts.printer(os.Stdout).print(results, []string{"VERSION", "NAME", "STATE"}, rows); err != nil {
+		return err
+	}
+
+	if modified > 0 {
+		return fmt.Errorf("libraryctl migrate status: %d applied migrations were modified since", modified)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer writes a result as a table or as json.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func (p *printer) print(value interface{}, header []string, rows [][]string) error {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (p *printer) message(format string, args ...interface{}) error {
+	if p.json {
+		return p.print(map[string]string{"message": fmt.Sprintf(format, args...)}, nil, nil)
+	}
+
+	_, err := fmt.Fprintf(p.w, format+"\n", args...)
+	return err
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/users"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+// usersCreate adds a cataloguer and prints their token, which is shown once.
+func usersCreate(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "users create")
+
+	var name string
+	fs.StringVar(&name, "name", "", "name recorded in the audit log for the changes of the user")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	v := validator.NewValidator()
+	if users.ValidateName(v, name); !v.Valid() {
+		return fmt.Errorf("libraryctl users create: %w", fieldErrors(v.Errors))
+	}
+
+	switch {
+	case opts.server != "":
+		return errors.New("libraryctl users create: only available against the database, drop -server")
+	case app.ConfigFlags.Storage == config.StorageMemory:
+		return errors.New("libraryctl users create: the in-memory storage would lose the user on exit")
+	}
+
+	_, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	token, hash, err := users.NewToken()
+	if err != nil {
+		return err
+	}
+
+	user := &users.User{Name: name}
+	if err := app.Models.Users.Create(ctx, user, hash); err != nil {
+		if errors.Is(err, users.ErrDuplicateName) {
+			return fmt.Errorf("libraryctl users create: there is a user named %q already", name)
+		}
+		return err
+	}
+
+	return opts.printer(os.Stdout).print(struct {
+		User  *users.User `json:"user"`
+		Token string      `json:"token"`
+	}{user, token}, []string{"ID", "NAME", "TOKEN"}, [][]string{
+		{strconv.FormatInt(user.ID, 10), user.Name, token},
+	})
+}
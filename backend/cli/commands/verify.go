@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+)
+
+// Verify fails when a consistency check finds a problem.
+func Verify(app *config.App, args []string) error {
+	fs, opts := newCtlFlagSet(app, "verify")
+	if err := app.Load(fs, args); err != nil {
+		return err
+	}
+
+	if opts.server != "" {
+		return fmt.Errorf("libraryctl verify: only available against the database, drop -server")
+	}
+
+	_, done, err := opts.open(app)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	problems, err := app.Models.Read.Verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(problems))
+	for i, problem := range problems {
+		rows[i] = []string{problem.Check, problem.Entity, strconv.FormatInt(problem.ID, 10), problem.Detail}
+	}
+
+	p := opts.printer(os.Stdout)
+	if len(problems) == 0 && !p.json {
+		return p.message("no problems found")
+	}
+
+	if err := p.print(problems, []string{"CHECK", "ENTITY", "ID", "DETAIL"}, rows); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("libraryctl verify: %d problems found", len(problems))
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/grpcapi"
+)
+
+// StartGRPCServer serves the grpc api in the background, the returned func stops it.
+func StartGRPCServer(app *config.App) (func(), error) {
+	if app.ConfigFlags.GRPCPort == 0 {
+		return func() {}, nil
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", app.ConfigFlags.GRPCPort))
+	if err != nil {
+		return nil, err
+	}
+
+	server := grpcapi.NewGRPCServer(&grpcapi.Server{
+		Read:    app.Models.Read,
+		Create:  app.Models.Create,
+		Update:  app.Models.Update,
+		Delete:  app.Models.Delete,
+		Authors: app.Models.Authors,
+		Tx:      app.Tx,
+		OnError: func(err error) {
+			app.Log.Error().Err(err).Msg("grpc call failed")
+		},
+	}, app.Log, app.Admin.Token)
+
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			app.Log.Error().Err(err).Msg("grpc server stopped")
+		}
+	}()
+
+	app.Log.Info().Int("port", app.ConfigFlags.GRPCPort).Msg("started grpc server")
+
+	return func() {
+		server.GracefulStop()
+		app.Log.Info().Msg("grpc server stopped.")
+	}, nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+func ListAuditHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		v := validator.NewValidator()
+		qs := r.URL.Query()
+
+		filter := audit.Filter{
+			Actor:     app.ReadString(qs, "actor", ""),
+			Entity:    app.ReadString(qs, "entity", ""),
+			EntityID:  int64(app.ReadInt(qs, "entity_id", 0, v)),
+			Action:    app.ReadString(qs, "action", ""),
+			RequestID: app.ReadString(qs, "request_id", ""),
+			Since:     readTime(qs, "since", v),
+			Until:     readTime(qs, "until", v),
+		}
+
+		filters := internal.Filters{
+			Page:         app.ReadInt(qs, "page", 1, v),
+			PageSize:     app.ReadInt(qs, "page_size", 20, v),
+			Sort:         app.ReadString(qs, "sort", "-created_at"),
+			SortSafeList: []string{"id", "created_at", "-id", "-created_at"},
+		}
+
+		var actions = []string{
+			audit.ActionInsert,
+			audit.ActionUpdate,
+			audit.ActionDelete,
+			audit.ActionRestore,
+			audit.ActionPurge,
+		}
+
+		if filter.Action != "" {
+			v.Check(v.In(filter.Action, actions), "action", "invalid action parameter")
+		}
+
+		if !filters.ValidateFilters(v) {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		ctx := r.Context()
+
+		entries, metadata, err := app.Models.Audit.List(ctx, filter, filters)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+			"entries":  entries,
+			"metadata": metadata,
+		}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
+
+func readTime(qs url.Values, key string, v *validator.Validator) time.Time {
+	s := qs.Get(key)
+
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		v.AddError(key, "must be an RFC 3339 timestamp")
+		return time.Time{}
+	}
+
+	return t
+}
@@ -2,9 +2,9 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/3WDeveloper-GM/library_app/backend/config"
 	"github.com/3WDeveloper-GM/library_app/backend/internal"
@@ -12,12 +12,44 @@ import (
 	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
 )
 
+// InsertBookInput is the body of POST /v1/insert/book.
+type InsertBookInput struct {
+	Book struct {
+		ID        int64    `json:"id"`
+		Title     string   `json:"title"`
+		Publisher string   `json:"publisher"`
+		Year      int32    `json:"year"`
+		PageCount int32    `json:"page_count"`
+		Genres    []string `json:"genres"`
+		ISBN      string   `json:"isbn"`
+	} `json:"book"`
+	Authors struct {
+		List []string `json:"names"`
+	} `json:"authors"`
+}
+
+// UpdateBookInput is the body of PATCH /v1/update/book/{id}.
+type UpdateBookInput struct {
+	Book struct {
+		Title     *string  `json:"title"`
+		Publisher *string  `json:"publisher"`
+		Year      *int32   `json:"year"`
+		PageCount *int32   `json:"page_count"`
+		Genres    []string `json:"genres"`
+		ISBN      *string  `json:"isbn"`
+		Version   *int32   `json:"version"`
+	} `json:"book"`
+	Authors struct {
+		Name []string `json:"names"`
+	} `json:"authors"`
+}
+
 func DeleteEntryHandlerDelete(app *config.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
 		n, err := app.ReadIDParams(r)
 		if err != nil {
-			app.ServerErrorResponse(w, r, err)
+			app.NotFoundResponse(w, r)
 			return
 		}
 
@@ -25,19 +57,15 @@ func DeleteEntryHandlerDelete(app *config.App) http.HandlerFunc {
 			ID: n,
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
-
-		tx, err := app.Models.Create.DB.BeginTx(ctx, nil)
-		if err != nil {
-			app.ServerErrorResponse(w, r, err)
-			return
-		}
-		defer tx.Rollback()
+		ctx := r.Context()
 
-		err = app.Models.Delete.DeleteBook(ctx, tx, deleteID)
+		err = app.Tx.Run(ctx, "delete", app.Models.Delete.BeginTx, func(ctx context.Context, tx books.Tx) error {
+			return app.Models.Delete.DeleteBook(ctx, tx, deleteID)
+		})
 		if err != nil {
 			switch {
+			case errors.Is(err, books.ErrNotFound):
+				app.NotFoundResponse(w, r)
 			case errors.Is(err, books.ErrEditConflict):
 				app.EditConflictResponse(w, r)
 			default:
@@ -46,12 +74,7 @@ func DeleteEntryHandlerDelete(app *config.App) http.HandlerFunc {
 			return
 		}
 
-		if err := tx.Commit(); err != nil {
-			app.ServerErrorResponse(w, r, err)
-			return
-		}
-
-		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{"message": "entry deleted succesfully"}, nil)
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{"message": "entry moved to the trash"}, nil)
 		if err != nil {
 			app.ServerErrorResponse(w, r, err)
 		}
@@ -63,23 +86,11 @@ func InsertEntryHandlerPost(app *config.App) http.HandlerFunc {
 
 		read := &books.ReadEntry{}
 
-		var input struct {
-			Book struct {
-				ID        int64    `json:"id"`
-				Title     string   `json:"title"`
-				Publisher string   `json:"publisher"`
-				Year      int32    `json:"year"`
-				PageCount int32    `json:"page_count"`
-				Genres    []string `json:"genres"`
-			} `json:"book"`
-			Authors struct {
-				List []string `json:"names"`
-			} `json:"authors"`
-		}
+		var input InsertBookInput
 
 		err := app.ReadJSON(w, r, &input)
 		if err != nil {
-			app.ServerErrorResponse(w, r, err)
+			app.BadRequestResponse(w, r, err)
 			return
 		}
 
@@ -89,6 +100,7 @@ func InsertEntryHandlerPost(app *config.App) http.HandlerFunc {
 			Year:      input.Book.Year,
 			PageCount: input.Book.PageCount,
 			Genres:    input.Book.Genres,
+			ISBN:      books.NormalizeISBN(input.Book.ISBN),
 		}
 
 		authors := &books.Authors{
@@ -100,47 +112,32 @@ func InsertEntryHandlerPost(app *config.App) http.HandlerFunc {
 			Authors: authors,
 		}
 
-		read.Authors = make([]books.ReadAuthor, len(authors.List))
-
 		v := validator.NewValidator()
 		if !inputEntry.ValidateEntry(v) {
 			app.FailedValidationResponse(w, r, v.Errors)
 			return
 		}
 
-		//sanitize author names
-		for index, value := range inputEntry.Authors.List {
-			inputEntry.Authors.List[index] = app.ToUpper(value)
-		}
-
-		books.HashEntries(inputEntry.Book, inputEntry.Authors)
+		inputEntry.Normalize()
 
 		app.Log.Info().Interface("entry", inputEntry).Send()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
-		defer cancel()
+		ctx := r.Context()
 
-		tx, err := app.Models.Create.DB.BeginTx(ctx, nil)
+		err = app.Tx.Retry(ctx, "create", func(ctx context.Context) error {
+			return app.Models.Create.Save(ctx, inputEntry, read)
+		})
 		if err != nil {
-			app.ServerErrorResponse(w, r, err)
-			return
-		}
-
-		defer tx.Rollback()
-
-		err = app.Models.Create.Insert(ctx, tx, inputEntry, read)
-		if err != nil {
-			app.ServerErrorResponse(w, r, err)
-			return
-		}
-
-		if err := tx.Commit(); err != nil {
-			app.ServerErrorResponse(w, r, err)
+			var dup *books.DuplicateError
+			switch {
+			case errors.As(err, &dup):
+				app.DuplicateBookResponse(w, r, dup)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
 			return
 		}
 
-		read.Convert()
-
 		err = app.WriteJson(w, r, http.StatusCreated, config.Envelope{
 			"message": "entry created!",
 			"entry":   read,
@@ -170,23 +167,38 @@ func FetchAuthorEntryHandlerGet(app *config.App) http.HandlerFunc {
 			BookDisplay: []books.ReadBook{},
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
+		ctx := r.Context()
 
-		err = app.Models.Read.AuthorGet(ctx, nil, readAuthorEntry)
+		err = app.Models.Authors.AuthorGet(ctx, nil, readAuthorEntry)
 		if err != nil {
-			app.ServerErrorResponse(w, r, err)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				app.NotFoundResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
 			return
 		}
 
 		readAuthorEntry.BookDisplay = make([]books.ReadBook, len(readAuthorEntry.BookList.Hash))
 		readAuthorEntry.Convert()
 
-		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+		env := config.Envelope{
 			"message": "found entry!",
 			"entry":   readAuthorEntry,
-		}, nil)
+		}
+
+		enc, ok := app.Negotiate(w, r, env)
+		if !ok {
+			app.NotAcceptableResponse(w, r, config.MediaTypes(env))
+			return
+		}
 
+		if app.SetValidators(w, r, enc.Tag(readAuthorEntry.ETag()), readAuthorEntry.LastModified) {
+			return
+		}
+
+		err = app.WriteNegotiated(w, r, enc, http.StatusOK, env, nil)
 		if err != nil {
 			app.ServerErrorResponse(w, r, err)
 		}
@@ -209,22 +221,38 @@ func FetchEntryHandlerGet(app *config.App) http.HandlerFunc {
 			Authors: []books.ReadAuthor{},
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
+		ctx := r.Context()
 
 		err = app.Models.Read.Get(ctx, nil, readEntry)
 		if err != nil {
-			app.ServerErrorResponse(w, r, err)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				app.NotFoundResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
 			return
 		}
 
 		readEntry.Authors = make([]books.ReadAuthor, len(readEntry.List.Name))
 		readEntry.Convert()
 
-		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+		env := config.Envelope{
 			"entry":   readEntry,
 			"message": "entry found",
-		}, nil)
+		}
+
+		enc, ok := app.Negotiate(w, r, env)
+		if !ok {
+			app.NotAcceptableResponse(w, r, config.MediaTypes(env))
+			return
+		}
+
+		if app.SetValidators(w, r, enc.Tag(readEntry.ETag()), readEntry.LastModified) {
+			return
+		}
+
+		err = app.WriteNegotiated(w, r, enc, http.StatusOK, env, nil)
 		if err != nil {
 			app.ServerErrorResponse(w, r, err)
 		}
@@ -240,8 +268,7 @@ func UpdateEntriesHandlerPatch(app *config.App) http.HandlerFunc {
 			return
 		}
 
-		ctx1, cancel := context.WithTimeout(context.Background(), 1200*time.Millisecond)
-		defer cancel()
+		ctx := r.Context()
 
 		old_entry := &books.ReadEntry{
 			Book: books.ReadBook{
@@ -249,114 +276,165 @@ func UpdateEntriesHandlerPatch(app *config.App) http.HandlerFunc {
 			},
 			Authors: []books.ReadAuthor{},
 		}
-		err = app.Models.Read.Get(ctx1, nil, old_entry)
+		err = app.Models.Read.Get(ctx, nil, old_entry)
 		if err != nil {
-			app.ServerErrorResponse(w, r, err)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				app.NotFoundResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
 			return
 		}
 
-		var input struct {
-			Book struct {
-				Title     *string  `json:"title"`
-				Publisher *string  `json:"publisher"`
-				Year      *int32   `json:"year"`
-				PageCount *int32   `json:"page_count"`
-				Genres    []string `json:"genres"`
-			} `json:"book"`
-			Authors struct {
-				Name []string `json:"names"`
-			} `json:"authors"`
-		}
+		var input UpdateBookInput
 
 		err = app.ReadJSON(w, r, &input)
 		if err != nil {
-			app.ServerErrorResponse(w, r, err)
+			app.BadRequestResponse(w, r, err)
 			return
 		}
 
-		for index, value := range input.Authors.Name {
-			input.Authors.Name[index] = app.ToUpper(value)
+		ifMatch, err := app.ReadIfMatchVersion(r)
+		if err != nil {
+			app.BadRequestResponse(w, r, err)
+			return
 		}
 
-		new_entry := &books.UpdateEntry{
-			Book: books.UpdateBook{
-				ID: &n,
-			},
-			Author: books.UpdateAuthors{},
+		//expected version, from the body or If-Match
+		var expected *int32
+		switch {
+		case ifMatch == nil && input.Book.Version == nil:
+			app.PreconditionRequiredResponse(w, r)
+			return
+		case ifMatch != nil && input.Book.Version != nil && *ifMatch != *input.Book.Version:
+			app.BadRequestResponse(w, r, errors.New("book version does not match the If-Match header"))
+			return
+		case ifMatch != nil:
+			expected = ifMatch
+		case input.Book.Version != nil:
+			expected = input.Book.Version
 		}
 
-		if input.Book.Title == nil {
-			new_entry.Book.Title = &old_entry.Book.Title
-		} else {
-			new_entry.Book.Title = input.Book.Title
-		}
-		if input.Book.Publisher == nil {
-			new_entry.Book.Publisher = &old_entry.Book.Publisher
-		} else {
-			new_entry.Book.Publisher = input.Book.Publisher
-		}
-		if input.Book.Year == nil {
-			new_entry.Book.Year = &old_entry.Book.Year
-		} else {
-			new_entry.Book.Year = input.Book.Year
+		if *expected != old_entry.Book.Version {
+			app.VersionConflictResponse(w, r, old_entry.Book.Version)
+			return
 		}
-		if input.Book.PageCount == nil {
-			new_entry.Book.PageCount = &old_entry.Book.PageCount
-		} else {
-			new_entry.Book.PageCount = input.Book.PageCount
+
+		patch := books.BookPatch{
+			Title:     input.Book.Title,
+			Publisher: input.Book.Publisher,
+			Year:      input.Book.Year,
+			PageCount: input.Book.PageCount,
+			ISBN:      input.Book.ISBN,
 		}
-		if input.Book.Genres == nil {
-			new_entry.Book.Genres = old_entry.Book.Genres
-		} else {
-			new_entry.Book.Genres = input.Book.Genres
+		if input.Book.Genres != nil {
+			patch.Genres = &input.Book.Genres
 		}
-		if input.Authors.Name == nil {
-			new_entry.Author.Name = old_entry.List.Name
-		} else {
-			new_entry.Author.Name = input.Authors.Name
+		if input.Authors.Name != nil {
+			patch.Authors = &input.Authors.Name
 		}
 
-		v := validator.NewValidator()
-		if !new_entry.ValidateEntry(v) {
-			app.FailedValidationResponse(w, r, v.Errors)
+		new_entry := patch.Apply(old_entry)
+
+		if !applyUpdate(app, w, r, new_entry, old_entry) {
 			return
 		}
 
-		_, exclusiveOld, exclusiveNew := internal.DiffArrays(old_entry.List.Name, new_entry.Author.Name)
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+			"entry":   old_entry,
+			"message": "succesfully updated",
+		}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
 
-		hashOld, hashNew := internal.HashArrays(exclusiveOld, exclusiveNew)
+	}
+}
 
-		new_entry.HashEntries()
+// applyUpdate returns false once it has sent an error response.
+func applyUpdate(app *config.App, w http.ResponseWriter, r *http.Request, new_entry *books.UpdateEntry, old_entry *books.ReadEntry) bool {
+	v := validator.NewValidator()
+	if !new_entry.ValidateEntry(v) {
+		app.FailedValidationResponse(w, r, v.Errors)
+		return false
+	}
 
-		ctx, cancel2 := context.WithTimeout(context.Background(), 4*time.Second)
-		defer cancel2()
+	ctx := r.Context()
+
+	//every attempt starts over from old_entry
+	var updated books.ReadEntry
+	err := app.Tx.Retry(ctx, "update", func(ctx context.Context) error {
+		updated = *old_entry
+		return app.Models.Update.Save(ctx, new_entry, &updated)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, books.ErrAuthorsChanged):
+			app.EditConflictResponse(w, r)
+		case errors.Is(err, books.ErrEditConflict):
+			current := &books.ReadEntry{
+				Book: books.ReadBook{
+					ID: old_entry.Book.ID,
+				},
+			}
 
-		tx, err := app.Models.Update.DB.BeginTx(ctx, nil)
-		if err != nil {
+			if err := app.Models.Read.Get(ctx, nil, current); err != nil {
+				app.EditConflictResponse(w, r)
+				return false
+			}
+			app.VersionConflictResponse(w, r, current.Book.Version)
+		default:
 			app.ServerErrorResponse(w, r, err)
-			return
 		}
+		return false
+	}
 
-		defer tx.Rollback()
+	*old_entry = updated
 
-		err = app.Models.Update.Update(ctx, tx, new_entry, old_entry, hashOld, exclusiveNew, hashNew)
-		if err != nil {
-			app.ServerErrorResponse(w, r, err)
+	return true
+}
+
+var bookSortSafeList = []string{"id", "title", "year", "created_at", "-id", "-title", "-year", "-created_at"}
+
+func ListEntriesHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		v := validator.NewValidator()
+		qs := r.URL.Query()
+
+		filter := books.ListFilter{
+			Query:    app.ReadString(qs, "q", ""),
+			Genre:    app.ReadString(qs, "genre", ""),
+			AuthorID: int64(app.ReadInt(qs, "author_id", 0, v)),
+		}
+
+		filters := internal.Filters{
+			Page:         app.ReadInt(qs, "page", 1, v),
+			PageSize:     app.ReadInt(qs, "page_size", 20, v),
+			Sort:         app.ReadString(qs, "sort", "id"),
+			SortSafeList: bookSortSafeList,
+		}
+
+		if !filters.ValidateFilters(v) {
+			app.FailedValidationResponse(w, r, v.Errors)
 			return
 		}
 
-		if err := tx.Commit(); err != nil {
+		ctx := r.Context()
+
+		entries, metadata, err := app.Models.Read.List(ctx, filter, filters)
+		if err != nil {
 			app.ServerErrorResponse(w, r, err)
 			return
 		}
 
 		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
-			"entry":   old_entry,
-			"message": "succesfully updated",
+			"books":    entries,
+			"metadata": metadata,
 		}, nil)
 		if err != nil {
 			app.ServerErrorResponse(w, r, err)
 		}
-
 	}
 }
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/cite"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+const maxCiteIDs = 100
+
+func CiteEntryHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		n, err := app.ReadIDParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		v := validator.NewValidator()
+
+		format, ok := cite.Lookup(app.ReadString(r.URL.Query(), "format", "bibtex"))
+		if !ok {
+			v.AddError("format", cite.UnknownFormatError(r.URL.Query().Get("format")).Error())
+		}
+
+		if !v.Valid() {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		ctx := r.Context()
+
+		entries, err := app.Models.Read.GetMany(ctx, []int64{n})
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		if len(entries) == 0 {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		writeCitations(app, w, r, format, entries)
+	}
+}
+
+// CiteEntriesHandlerGet cites ?ids=1,2,3 in the order given.
+func CiteEntriesHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		v := validator.NewValidator()
+		qs := r.URL.Query()
+
+		format, ok := cite.Lookup(app.ReadString(qs, "format", "bibtex"))
+		if !ok {
+			v.AddError("format", cite.UnknownFormatError(qs.Get("format")).Error())
+		}
+
+		ids := []int64{}
+		seen := make(map[int64]bool)
+
+		for _, raw := range app.ReadCSV(qs, "ids", []string{}) {
+			id, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+			if err != nil || id < 1 {
+				v.AddError("ids", "must be a comma separated list of book ids")
+				break
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+
+		v.Check(len(ids) > 0, "ids", "must be provided")
+		v.Check(len(ids) <= maxCiteIDs, "ids", fmt.Sprintf("must not hold more than %d ids", maxCiteIDs))
+
+		if !v.Valid() {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		ctx := r.Context()
+
+		entries, err := app.Models.Read.GetMany(ctx, ids)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		if len(entries) != len(ids) {
+			found := make(map[int64]bool, len(entries))
+			for _, entry := range entries {
+				found[entry.Book.ID] = true
+			}
+
+			missing := []string{}
+			for _, id := range ids {
+				if !found[id] {
+					missing = append(missing, strconv.FormatInt(id, 10))
+				}
+			}
+
+			v.AddError("ids", "unknown book ids: "+strings.Join(missing, ", "))
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		writeCitations(app, w, r, format, entries)
+	}
+}
+
+func writeCitations(app *config.App, w http.ResponseWriter, r *http.Request, format cite.Format, entries []*books.ReadEntry) {
+	works := make([]*cite.Work, len(entries))
+	for i, entry := range entries {
+		works[i] = cite.FromEntry(entry)
+	}
+
+	out, err := format.Render(works)
+	if err != nil {
+		app.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(out)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/export"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+func ExportHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		v := validator.NewValidator()
+		qs := r.URL.Query()
+
+		format, ok := export.Lookup(app.ReadString(qs, "format", "ndjson"))
+		if !ok {
+			v.AddError("format", export.UnknownFormatError(qs.Get("format")).Error())
+		}
+
+		filter := books.ExportFilter{
+			Genre:         app.ReadString(qs, "genre", ""),
+			Publisher:     app.ReadString(qs, "publisher", ""),
+			YearFrom:      int32(app.ReadInt(qs, "year_from", 0, v)),
+			YearTo:        int32(app.ReadInt(qs, "year_to", 0, v)),
+			ModifiedSince: readTime(qs, "modified_since", v),
+		}
+
+		if !v.Valid() {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		//no write deadline for the dump
+		rc := http.NewResponseController(w)
+		rc.SetWriteDeadline(time.Time{})
+
+		ctx := r.Context()
+
+		filename := fmt.Sprintf("catalogue-%s.%s", time.Now().UTC().Format("20060102"), format.Extension)
+
+		w.Header().Set("Content-Type", format.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.WriteHeader(http.StatusOK)
+
+		count, err := export.Run(ctx, app.Models.Read, format, filter, w, rc.Flush)
+		if err != nil {
+			app.Log.Error().Err(err).Int("exported", count).Msg("export aborted")
+			//the 200 is out, cut the connection so the dump is not taken as complete
+			panic(http.ErrAbortHandler)
+		}
+
+		app.Log.Info().Int("exported", count).Str("format", format.Name).Msg("export finished")
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/gql"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+// GraphQLHandlerPost answers 200 to resolver errors too.
+func GraphQLHandlerPost(app *config.App) http.HandlerFunc {
+	service, err := gql.NewService(&gql.Resolver{
+		Read:        app.Models.Read,
+		Create:      app.Models.Create,
+		Update:      app.Models.Update,
+		Delete:      app.Models.Delete,
+		AuthorStore: app.Models.Authors,
+		Tx:          app.Tx,
+		OnError: func(err error) {
+			app.Log.Error().Err(err).Msg("graphql resolver failed")
+		},
+	})
+	if err != nil {
+		panic("graphql: invalid schema: " + err.Error())
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var input gql.Request
+
+		err := app.ReadJSON(w, r, &input)
+		if err != nil {
+			app.BadRequestResponse(w, r, err)
+			return
+		}
+
+		v := validator.NewValidator()
+		v.Check(input.Query != "", "query", "must be provided")
+
+		if !v.Valid() {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		ctx := r.Context()
+
+		response := service.Exec(ctx, input)
+
+		js, err := json.Marshal(response)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(append(js, '\n'))
+	}
+}
@@ -0,0 +1,451 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/cli/routes"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/users"
+	"github.com/rs/zerolog"
+)
+
+const adminToken = "s3cret"
+
+// backends are the stores the handlers are tested against, the SQLite one
+// lives in a file of its own for every test.
+var backends = []struct {
+	name string
+	app  func(t *testing.T) *config.App
+}{
+	{"memory", memoryApp},
+	{"sqlite", sqliteApp},
+}
+
+func newApp() *config.App {
+	app := config.NewAppObject()
+	nop := zerolog.Nop()
+	app.Log = &nop
+	app.Admin.Token = adminToken
+	return app
+}
+
+func memoryApp(t *testing.T) *config.App {
+	app := newApp()
+	app.ConfigFlags.Storage = config.StorageMemory
+	app.SetModels()
+	return app
+}
+
+func sqliteApp(t *testing.T) *config.App {
+	t.Helper()
+
+	app := newApp()
+	app.ConfigFlags.Storage = config.StorageDatabase
+	app.Database.DSN = "sqlite:" + filepath.Join(t.TempDir(), "library.db")
+
+	db, err := app.OpenDB(app.Database.DSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Database.DB = db
+	t.Cleanup(app.CloseDB)
+
+	if err := app.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.SetModels()
+	return app
+}
+
+// seed inserts Dune, which gets the id 1 as does its author.
+func seed(t *testing.T, app *config.App) {
+	t.Helper()
+
+	entry := &books.CreateBookEntry{
+		Book: &books.Book{
+			Title:     "Dune",
+			Publisher: "Ace",
+			Year:      1965,
+			PageCount: 412,
+			Genres:    []string{"science fiction"},
+		},
+		Authors: &books.Authors{List: []string{"Frank Herbert"}},
+	}
+	entry.Normalize()
+
+	if err := app.Models.Create.Save(context.Background(), entry, &books.ReadEntry{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type request struct {
+	method string
+	path   string
+	body   string
+	header map[string]string
+}
+
+func serve(app *config.App, req request) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+	if req.body == "" {
+		r.Body = http.NoBody
+	}
+	for key, value := range req.header {
+		r.Header.Set(key, value)
+	}
+
+	w := httptest.NewRecorder()
+	routes.API(app).ServeHTTP(w, r)
+	return w
+}
+
+func TestEndpoints(t *testing.T) {
+	admin := map[string]string{"Authorization": "Bearer " + adminToken}
+
+	tests := []struct {
+		name   string
+		req    request
+		status int
+		// contains is a piece of the body, errors are checked on it
+		contains string
+	}{
+		{"fetch book", request{method: "GET", path: "/v1/fetch/book/1"}, http.StatusOK, `"title":"Dune"`},
+		{"fetch missing book", request{method: "GET", path: "/v1/fetch/book/42"}, http.StatusNotFound, "could not be found"},
+		{"fetch book with a bad id", request{method: "GET", path: "/v1/fetch/book/abc"}, http.StatusNotFound, "could not be found"},
+		{"fetch book as png", request{method: "GET", path: "/v1/fetch/book/1", header: map[string]string{"Accept": "image/png"}}, http.StatusNotAcceptable, "can only be represented"},
+		{"fetch author", request{method: "GET", path: "/v1/fetch/author/1"}, http.StatusOK, "FRANK HERBERT"},
+		{"fetch missing author", request{method: "GET", path: "/v1/fetch/author/42"}, http.StatusNotFound, "could not be found"},
+		{"list books", request{method: "GET", path: "/v1/books?q=dune"}, http.StatusOK, `"total_records":1`},
+		{"list books with a bad sort", request{method: "GET", path: "/v1/books?sort=pages"}, http.StatusUnprocessableEntity, `"sort"`},
+		{"list books with a bad page", request{method: "GET", path: "/v1/books?page=0"}, http.StatusUnprocessableEntity, `"page"`},
+
+		{"insert book", request{method: "POST", path: "/v1/insert/book", body: `{"book": {"title": "Hyperion", "publisher": "Doubleday", "year": 1989, "page_count": 482, "genres": ["science fiction"]}, "authors": {"names": ["Dan Simmons"]}}`}, http.StatusCreated, "entry created"},
+		{"insert a duplicate book", request{method: "POST", path: "/v1/insert/book", body: `{"book": {"title": "Dune", "publisher": "Ace", "year": 1965, "page_count": 412, "genres": ["science fiction"]}, "authors": {"names": ["Frank Herbert"]}}`}, http.StatusConflict, `"book_id":1`},
+		{"insert book without authors", request{method: "POST", path: "/v1/insert/book", body: `{"book": {"title": "Hyperion", "publisher": "Doubleday", "year": 1989, "page_count": 482, "genres": ["science fiction"]}}`}, http.StatusUnprocessableEntity, `"authors"`},
+		{"insert book with a wrong type", request{method: "POST", path: "/v1/insert/book", body: `{"book": {"year": "1989"}}`}, http.StatusUnprocessableEntity, "year"},
+		{"insert badly-formed json", request{method: "POST", path: "/v1/insert/book", body: `{"book": {"title": "Hyperion",}}`}, http.StatusBadRequest, "badly-formed JSON (at character"},
+		{"insert truncated json", request{method: "POST", path: "/v1/insert/book", body: `{"book": {"title": "Hyperion"`}, http.StatusBadRequest, "badly-formed JSON"},
+		{"insert empty body", request{method: "POST", path: "/v1/insert/book"}, http.StatusBadRequest, "must not be empty"},
+		{"insert with the wrong method", request{method: "PUT", path: "/v1/insert/book"}, http.StatusMethodNotAllowed, "PUT method is not supported"},
+
+		{"update book", request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books", "version": 1}}`}, http.StatusOK, "Chilton Books"},
+		{"update book with if-match", request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books"}}`, header: map[string]string{"If-Match": `"1"`}}, http.StatusOK, "Chilton Books"},
+		{"update stale version", request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books", "version": 7}}`}, http.StatusConflict, `"current_version":1`},
+		{"update with a bad if-match", request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {}}`, header: map[string]string{"If-Match": "nope"}}, http.StatusBadRequest, "If-Match"},
+		{"update with clashing versions", request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"version": 2}}`, header: map[string]string{"If-Match": `"1"`}}, http.StatusBadRequest, "does not match"},
+		{"update without a version", request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books"}}`}, http.StatusPreconditionRequired, "If-Match"},
+		{"update with an empty title", request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"title": "", "version": 1}}`}, http.StatusUnprocessableEntity, `"title"`},
+		{"update badly-formed json", request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": `}, http.StatusBadRequest, "badly-formed JSON"},
+		{"update missing book", request{method: "PATCH", path: "/v1/update/book/42", body: `{"book": {}}`}, http.StatusNotFound, "could not be found"},
+
+		{"delete book", request{method: "DELETE", path: "/v1/delete/book/1"}, http.StatusOK, "moved to the trash"},
+		{"delete missing book", request{method: "DELETE", path: "/v1/delete/book/42"}, http.StatusNotFound, "could not be found"},
+		{"delete book with a bad id", request{method: "DELETE", path: "/v1/delete/book/0"}, http.StatusNotFound, "could not be found"},
+		{"restore a live book", request{method: "POST", path: "/v1/books/1/restore"}, http.StatusNotFound, "could not be found"},
+		{"purge without a token", request{method: "DELETE", path: "/v1/trash/1"}, http.StatusUnauthorized, "authentication token"},
+		{"purge a live book", request{method: "DELETE", path: "/v1/trash/1", header: admin}, http.StatusNotFound, "could not be found"},
+		{"list the trash", request{method: "GET", path: "/v1/trash"}, http.StatusOK, `"books":[]`},
+		{"list the audit log", request{method: "GET", path: "/v1/audit?entity=book", header: admin}, http.StatusOK, `"action":"insert"`},
+		{"metrics", request{method: "GET", path: "/v1/metrics", header: admin}, http.StatusOK, `"transactions":{`},
+		{"metrics without a token", request{method: "GET", path: "/v1/metrics"}, http.StatusUnauthorized, "authentication token"},
+
+		{"list revisions", request{method: "GET", path: "/v1/books/1/revisions"}, http.StatusOK, `"version":1`},
+		{"diff a missing revision", request{method: "GET", path: "/v1/books/1/revisions/9/diff"}, http.StatusNotFound, "could not be found"},
+		{"diff the first revision", request{method: "GET", path: "/v1/books/1/revisions/1/diff"}, http.StatusOK, `"from_version":0`},
+		{"revert", request{method: "POST", path: "/v1/books/1/revisions/1/revert", header: map[string]string{"If-Match": `"1"`}}, http.StatusOK, "reverted"},
+		{"revert without a version", request{method: "POST", path: "/v1/books/1/revisions/1/revert"}, http.StatusPreconditionRequired, "If-Match"},
+
+		{"opds root", request{method: "GET", path: "/v1/opds"}, http.StatusOK, `<title>By genre</title>`},
+		{"opds newest", request{method: "GET", path: "/v1/opds/new"}, http.StatusOK, `<title>Dune</title>`},
+		{"opds genre", request{method: "GET", path: "/v1/opds/genres/science%20fiction"}, http.StatusOK, `<title>Dune</title>`},
+		{"opds author", request{method: "GET", path: "/v1/opds/authors/1"}, http.StatusOK, `<title>FRANK HERBERT</title>`},
+		{"opds author with a bad id", request{method: "GET", path: "/v1/opds/authors/abc"}, http.StatusNotFound, "could not be found"},
+		{"opds search", request{method: "GET", path: "/v1/opds/search?q=dune&page=0"}, http.StatusUnprocessableEntity, `"page"`},
+		{"opensearch description", request{method: "GET", path: "/v1/opds/opensearch.xml"}, http.StatusOK, "/v1/opds/search?q={searchTerms}"},
+		{"opds2 genres", request{method: "GET", path: "/v1/opds2/genres"}, http.StatusOK, `"numberOfItems": 1`},
+		{"opds2 search", request{method: "GET", path: "/v1/opds2/search?q=dune"}, http.StatusOK, `"title": "Dune"`},
+
+		{"graphql badly-formed json", request{method: "POST", path: "/graphql", body: `{"query": `}, http.StatusBadRequest, "badly-formed JSON"},
+		{"unknown route", request{method: "GET", path: "/v1/nowhere"}, http.StatusNotFound, "could not be found"},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					app := backend.app(t)
+					seed(t, app)
+
+					w := serve(app, tt.req)
+					if w.Code != tt.status {
+						t.Fatalf("got status %d, want %d: %s", w.Code, tt.status, w.Body)
+					}
+					if !strings.Contains(w.Body.String(), tt.contains) {
+						t.Errorf("the body does not contain %q: %s", tt.contains, w.Body)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestConditionalFetch(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			app := backend.app(t)
+			seed(t, app)
+
+			w := serve(app, request{method: "GET", path: "/v1/fetch/book/1"})
+			etag := w.Header().Get("ETag")
+			if w.Code != http.StatusOK || etag == "" {
+				t.Fatalf("got status %d and etag %q", w.Code, etag)
+			}
+
+			w = serve(app, request{method: "GET", path: "/v1/fetch/book/1", header: map[string]string{"If-None-Match": etag}})
+			if w.Code != http.StatusNotModified {
+				t.Errorf("got status %d, want 304", w.Code)
+			}
+
+			serve(app, request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books", "version": 1}}`})
+
+			w = serve(app, request{method: "GET", path: "/v1/fetch/book/1", header: map[string]string{"If-None-Match": etag}})
+			if w.Code != http.StatusOK {
+				t.Errorf("got status %d after an update, want 200", w.Code)
+			}
+		})
+	}
+}
+
+func TestTrashRoundTrip(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			app := backend.app(t)
+			seed(t, app)
+
+			steps := []struct {
+				req    request
+				status int
+			}{
+				{request{method: "DELETE", path: "/v1/delete/book/1"}, http.StatusOK},
+				{request{method: "GET", path: "/v1/fetch/book/1"}, http.StatusNotFound},
+				{request{method: "DELETE", path: "/v1/delete/book/1"}, http.StatusNotFound},
+				{request{method: "POST", path: "/v1/books/1/restore"}, http.StatusOK},
+				{request{method: "GET", path: "/v1/fetch/book/1"}, http.StatusOK},
+				{request{method: "DELETE", path: "/v1/delete/book/1"}, http.StatusOK},
+				{request{method: "DELETE", path: "/v1/trash/1", header: map[string]string{"Authorization": "Bearer " + adminToken}}, http.StatusOK},
+				{request{method: "POST", path: "/v1/books/1/restore"}, http.StatusNotFound},
+			}
+
+			for _, step := range steps {
+				w := serve(app, step.req)
+				if w.Code != step.status {
+					t.Fatalf("%s %s: got status %d, want %d: %s", step.req.method, step.req.path, w.Code, step.status, w.Body)
+				}
+			}
+		})
+	}
+}
+
+// failingStream hands out one book and then fails, as a dropped connection
+// to the database would.
+type failingStream struct {
+	books.ReadRepository
+}
+
+func (f failingStream) Stream(ctx context.Context, filter books.ExportFilter, fn func(*books.ReadEntry) error) error {
+	return f.ReadRepository.Stream(ctx, filter, func(entry *books.ReadEntry) error {
+		if err := fn(entry); err != nil {
+			return err
+		}
+		return errors.New("connection reset by peer")
+	})
+}
+
+func TestExportAbortsMidStream(t *testing.T) {
+	app := memoryApp(t)
+	seed(t, app)
+	app.Models.Read = failingStream{app.Models.Read}
+
+	defer func() {
+		if got := recover(); got != http.ErrAbortHandler {
+			t.Errorf("got panic %v, want http.ErrAbortHandler", got)
+		}
+	}()
+
+	w := serve(app, request{method: "GET", path: "/v1/export"})
+	t.Errorf("the export finished with status %d: %s", w.Code, w.Body)
+}
+
+// The backfill of 000005 leaves a single revision at the current version,
+// diffing it compares against the empty book.
+func TestDiffBackfilledRevision(t *testing.T) {
+	app := sqliteApp(t)
+	seed(t, app)
+
+	serve(app, request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books", "version": 1}}`})
+	if _, err := app.Database.DB.Exec(`DELETE FROM book_revisions WHERE version = 1`); err != nil {
+		t.Fatal(err)
+	}
+
+	w := serve(app, request{method: "GET", path: "/v1/books/1/revisions/2/diff"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200: %s", w.Code, w.Body)
+	}
+	for _, want := range []string{`"from_version":0`, `"to_version":2`, `"to":"Chilton Books"`} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("the body does not contain %s: %s", want, w.Body)
+		}
+	}
+}
+
+// A database that went away has every handler answer with a 500 that does
+// not leak the error.
+func TestServerErrors(t *testing.T) {
+	app := sqliteApp(t)
+	seed(t, app)
+	app.Database.DB.Close()
+
+	tests := []request{
+		{method: "GET", path: "/v1/fetch/book/1"},
+		{method: "GET", path: "/v1/fetch/author/1"},
+		{method: "GET", path: "/v1/books"},
+		{method: "POST", path: "/v1/insert/book", body: `{"book": {"title": "Hyperion", "publisher": "Doubleday", "year": 1989, "page_count": 482, "genres": ["science fiction"]}, "authors": {"names": ["Dan Simmons"]}}`},
+		{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books", "version": 1}}`},
+		{method: "DELETE", path: "/v1/delete/book/1"},
+		{method: "GET", path: "/v1/trash"},
+	}
+
+	for _, req := range tests {
+		w := serve(app, req)
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("%s %s: got status %d, want 500", req.method, req.path, w.Code)
+			continue
+		}
+
+		var body struct{ Error string }
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(body.Error, "the server encountered a problem") {
+			t.Errorf("%s %s: got error %q", req.method, req.path, body.Error)
+		}
+	}
+}
+
+// Running out of time answers 503 and a client that went away 499, both
+// with the request context handed down to the store.
+func TestRequestDeadlines(t *testing.T) {
+	tests := []request{
+		{method: "GET", path: "/v1/fetch/book/1"},
+		{method: "GET", path: "/v1/books"},
+		{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books", "version": 1}}`},
+		{method: "DELETE", path: "/v1/delete/book/1"},
+	}
+
+	app := sqliteApp(t)
+	seed(t, app)
+	app.ConfigFlags.Timeouts.Read = time.Nanosecond
+	app.ConfigFlags.Timeouts.Write = time.Nanosecond
+
+	for _, req := range tests {
+		w := serve(app, req)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s past its deadline: got status %d, want 503", req.method, req.path, w.Code)
+		}
+	}
+
+	app = sqliteApp(t)
+	seed(t, app)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, req := range tests {
+		r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body)).WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		routes.API(app).ServeHTTP(w, r)
+		if w.Code != config.StatusClientClosedRequest {
+			t.Errorf("%s %s cancelled: got status %d, want 499", req.method, req.path, w.Code)
+		}
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	app := memoryApp(t)
+	seed(t, app)
+	app.ConfigFlags.Server.MaxBodyBytes = 64
+
+	w := serve(app, request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "Chilton Books", "version": 1}}`})
+	if w.Code != http.StatusOK {
+		t.Errorf("body under the limit: got status %d, want 200: %s", w.Code, w.Body)
+	}
+
+	w = serve(app, request{method: "PATCH", path: "/v1/update/book/1", body: `{"book": {"publisher": "` + strings.Repeat("x", 64) + `", "version": 2}}`})
+	if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), "64 bytes") {
+		t.Errorf("body over the limit: got status %d, want 413: %s", w.Code, w.Body)
+	}
+}
+
+// The changes made with a user token are recorded under the user's name.
+func TestUserTokens(t *testing.T) {
+	admin := map[string]string{"Authorization": "Bearer " + adminToken}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			app := backend.app(t)
+
+			token, hash, err := users.NewToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := app.Models.Users.Create(context.Background(), &users.User{Name: "alice"}, hash); err != nil {
+				t.Fatal(err)
+			}
+			if err := app.Models.Users.Create(context.Background(), &users.User{Name: "alice"}, "other"); !errors.Is(err, users.ErrDuplicateName) {
+				t.Errorf("got %v creating alice twice, want ErrDuplicateName", err)
+			}
+
+			insert := request{
+				method: "POST",
+				path:   "/v1/insert/book",
+				body:   `{"book": {"title": "Hyperion", "publisher": "Doubleday", "year": 1989, "page_count": 482, "genres": ["science fiction"]}, "authors": {"names": ["Dan Simmons"]}}`,
+				header: map[string]string{"Authorization": "Bearer " + token},
+			}
+			if w := serve(app, insert); w.Code != http.StatusCreated {
+				t.Fatalf("insert with a user token: got status %d: %s", w.Code, w.Body)
+			}
+
+			w := serve(app, request{method: "GET", path: "/v1/audit?actor=alice", header: admin})
+			if !strings.Contains(w.Body.String(), `"action":"insert"`) {
+				t.Errorf("the insert is not recorded under alice: %s", w.Body)
+			}
+
+			insert.header = map[string]string{"X-Actor": "bob"}
+			insert.body = strings.Replace(insert.body, "Hyperion", "Endymion", 1)
+			if w := serve(app, insert); w.Code != http.StatusCreated {
+				t.Fatalf("insert with X-Actor: got status %d: %s", w.Code, w.Body)
+			}
+
+			w = serve(app, request{method: "GET", path: "/v1/audit?actor=" + users.UnverifiedPrefix + "bob", header: admin})
+			if !strings.Contains(w.Body.String(), `"action":"insert"`) {
+				t.Errorf("the X-Actor insert is not recorded as unverified: %s", w.Body)
+			}
+
+			insert.header = map[string]string{"Authorization": "Bearer not-a-token"}
+			if w := serve(app, insert); w.Code != http.StatusUnauthorized {
+				t.Errorf("insert with an unknown token: got status %d, want 401", w.Code)
+			}
+		})
+	}
+}
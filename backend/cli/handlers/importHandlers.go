@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/importer"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+func ImportBooksHandlerPost(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		v := validator.NewValidator()
+
+		mapping, err := importer.ParseMapping(r.URL.Query().Get("mapping"))
+		if err != nil {
+			v.AddError("mapping", err.Error())
+		}
+
+		runImport(app, w, r, v, func(body io.Reader) ([]*importer.Row, error) {
+			return importer.ReadCSV(body, mapping)
+		})
+	}
+}
+
+func ImportMARCHandlerPost(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		v := validator.NewValidator()
+
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		defaultFormat := "marc"
+		switch mediaType {
+		case "application/marcxml+xml", "application/xml", "text/xml":
+			defaultFormat = "marcxml"
+		}
+
+		format := app.ReadString(r.URL.Query(), "format", defaultFormat)
+		v.Check(v.In(format, []string{"marc", "marcxml"}), "format", "must be marc or marcxml")
+
+		runImport(app, w, r, v, func(body io.Reader) ([]*importer.Row, error) {
+			return importer.ReadMARC(body, format == "marcxml")
+		})
+	}
+}
+
+// importDeadlineMargin is added to the connection deadlines of an import.
+const importDeadlineMargin = 30 * time.Second
+
+// runImport reads the file from the raw body or the "file" form field.
+func runImport(app *config.App, w http.ResponseWriter, r *http.Request, v *validator.Validator, read func(io.Reader) ([]*importer.Row, error)) {
+
+	var maxUploadBytes int64 = 32 << 20
+	var maxBatchSize = 1000
+
+	qs := r.URL.Query()
+
+	dryRun := app.ReadBool(qs, "dry_run", false, v)
+	batchSize := app.ReadInt(qs, "batch_size", 100, v)
+	v.Check(batchSize > 0 && batchSize <= maxBatchSize, "batch_size", "must be between 1 and 1000")
+
+	if !v.Valid() {
+		app.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	//the margin leaves time to answer past the import deadline
+	if timeout := app.RouteTimeout(config.RouteImport); timeout > 0 {
+		deadline := time.Now().Add(timeout + importDeadlineMargin)
+		rc := http.NewResponseController(w)
+		rc.SetReadDeadline(deadline)
+		rc.SetWriteDeadline(deadline)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+
+	var body io.Reader = r.Body
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			app.BadRequestResponse(w, r, err)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	rows, err := read(body)
+	if err != nil {
+		app.BadRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	imp := &importer.Importer{Create: app.Models.Create, Tx: app.Tx}
+
+	report, err := imp.Run(ctx, rows, importer.Options{
+		DryRun:    dryRun,
+		BatchSize: batchSize,
+		OnError: func(line int, err error) {
+			app.Log.Error().Err(err).Int("line", line).Msg("import row failed")
+		},
+	})
+	if err != nil {
+		app.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+		"report": report,
+	}, nil)
+	if err != nil {
+		app.ServerErrorResponse(w, r, err)
+	}
+}
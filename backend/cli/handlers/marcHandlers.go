@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/marc"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+func FetchMARCHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		n, err := app.ReadIDParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		v := validator.NewValidator()
+		format := app.ReadString(r.URL.Query(), "format", "marcxml")
+		v.Check(v.In(format, []string{"marc", "marcxml"}), "format", "must be marc or marcxml")
+
+		if !v.Valid() {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		readEntry := &books.ReadEntry{
+			Book: books.ReadBook{
+				ID: n,
+			},
+		}
+
+		ctx := r.Context()
+
+		err = app.Models.Read.Get(ctx, nil, readEntry)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				app.NotFoundResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		if app.SetValidators(w, r, readEntry.ETag(), readEntry.LastModified) {
+			return
+		}
+
+		rec := marc.FromEntry(readEntry)
+
+		if format == "marc" {
+			data, err := marc.Marshal(rec)
+			if err != nil {
+				app.ServerErrorResponse(w, r, err)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/marc")
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/marcxml+xml")
+		w.WriteHeader(http.StatusOK)
+
+		xw := marc.NewXMLWriter(w)
+		if err := xw.Write(rec); err != nil {
+			app.Log.Error().Err(err).Msg("marcxml response failed")
+			return
+		}
+		if err := xw.Close(); err != nil {
+			app.Log.Error().Err(err).Msg("marcxml response failed")
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+)
+
+// MetricsHandlerGet reports the counters the server publishes with expvar.
+func MetricsHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		err := app.WriteJson(w, r, http.StatusOK, config.Envelope{
+			"metrics": config.Envelope{
+				"transactions": json.RawMessage(config.TxMetrics().String()),
+			},
+		}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
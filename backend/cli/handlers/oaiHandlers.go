@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/oai"
+)
+
+// OAIHandler takes the arguments from the query string or a urlencoded body.
+func OAIHandler(app *config.App) http.HandlerFunc {
+	provider := &oai.Provider{
+		Read:                 app.Models.Read,
+		RepositoryName:       app.OAI.RepositoryName,
+		RepositoryIdentifier: app.OAI.RepositoryIdentifier,
+		AdminEmail:           app.OAI.AdminEmail,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if err := r.ParseForm(); err != nil {
+			app.BadRequestResponse(w, r, err)
+			return
+		}
+
+		baseURL := app.OAI.BaseURL
+		if baseURL == "" {
+			baseURL = requestBaseURL(r)
+		}
+
+		ctx := r.Context()
+
+		res, err := provider.Handle(ctx, baseURL, r.Form)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		out, err := xml.MarshalIndent(res, "", "  ")
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(xml.Header))
+		w.Write(out)
+	}
+}
+
+func requestBaseURL(r *http.Request) string {
+	return config.RequestOrigin(r) + r.URL.Path
+}
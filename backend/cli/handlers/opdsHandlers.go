@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/opds"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+// OPDSVersion serves the catalogue as OPDS 1.2 or 2.0 under its prefix.
+type OPDSVersion struct {
+	Prefix      string
+	write       func(w io.Writer, f *opds.Feed) error
+	contentType func(kind string) string
+}
+
+var OPDS1 = OPDSVersion{
+	Prefix: "/v1/opds",
+	write:  opds.WriteAtom,
+	contentType: func(kind string) string {
+		if kind == opds.AcquisitionKind {
+			return opds.AtomAcquisitionType
+		}
+		return opds.AtomNavigationType
+	},
+}
+
+var OPDS2 = OPDSVersion{
+	Prefix: "/v1/opds2",
+	write:  opds.WriteJSON,
+	contentType: func(string) string {
+		return opds.JSONType
+	},
+}
+
+const opdsPageSize = 20
+
+func OPDSRootHandlerGet(app *config.App, version OPDSVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		origin := config.RequestOrigin(r) + version.Prefix
+
+		feed := opds.NewFeed("urn:library:opds:root", app.Catalogue.Name, opds.NavigationKind)
+		feed.Links = opdsCommonLinks(origin, version, "", opds.NavigationKind)
+		feed.Navigation = []opds.Navigation{
+			{ID: "urn:library:opds:new", Title: "Newest additions", Href: origin + "/new", Kind: opds.AcquisitionKind, Rel: opds.RelNew},
+			{ID: "urn:library:opds:genres", Title: "By genre", Href: origin + "/genres", Kind: opds.NavigationKind},
+			{ID: "urn:library:opds:authors", Title: "By author", Href: origin + "/authors", Kind: opds.NavigationKind},
+		}
+
+		writeOPDS(app, w, r, version, feed)
+	}
+}
+
+func OPDSNewHandlerGet(app *config.App, version OPDSVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serveAcquisition(app, w, r, version, "urn:library:opds:new", "Newest additions", "/new", books.ListFilter{}, "-created_at")
+	}
+}
+
+func OPDSSearchHandlerGet(app *config.App, version OPDSVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := app.ReadString(r.URL.Query(), "q", "")
+		title := fmt.Sprintf("Search results for %q", q)
+		serveAcquisition(app, w, r, version, "urn:library:opds:search:"+url.QueryEscape(q), title, "/search", books.ListFilter{Query: q}, "title")
+	}
+}
+
+func OPDSGenresHandlerGet(app *config.App, version OPDSVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx := r.Context()
+
+		genres, err := app.Models.Read.GenreCounts(ctx)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		origin := config.RequestOrigin(r) + version.Prefix
+
+		feed := opds.NewFeed("urn:library:opds:genres", "By genre", opds.NavigationKind)
+		feed.Links = opdsCommonLinks(origin, version, "/genres", opds.NavigationKind)
+
+		for _, genre := range genres {
+			feed.Navigation = append(feed.Navigation, opds.Navigation{
+				ID:    "urn:library:opds:genre:" + url.PathEscape(genre.Genre),
+				Title: genre.Genre,
+				Href:  origin + "/genres/" + url.PathEscape(genre.Genre),
+				Kind:  opds.AcquisitionKind,
+				Count: genre.Books,
+			})
+		}
+
+		writeOPDS(app, w, r, version, feed)
+	}
+}
+
+func OPDSGenreHandlerGet(app *config.App, version OPDSVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		genre := chi.URLParam(r, "genre")
+		if unescaped, err := url.PathUnescape(genre); err == nil {
+			genre = unescaped
+		}
+
+		escaped := url.PathEscape(genre)
+		serveAcquisition(app, w, r, version, "urn:library:opds:genre:"+escaped, genre, "/genres/"+escaped, books.ListFilter{Genre: genre}, "title")
+	}
+}
+
+func OPDSAuthorsHandlerGet(app *config.App, version OPDSVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		v := validator.NewValidator()
+
+		filters := internal.Filters{
+			Page:         app.ReadInt(r.URL.Query(), "page", 1, v),
+			PageSize:     opdsPageSize,
+			Sort:         "name",
+			SortSafeList: []string{"name"},
+		}
+
+		if !filters.ValidateFilters(v) {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		ctx := r.Context()
+
+		authors, metadata, err := app.Models.Authors.ListAuthors(ctx, filters)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		origin := config.RequestOrigin(r) + version.Prefix
+
+		feed := opds.NewFeed("urn:library:opds:authors", "By author", opds.NavigationKind)
+		feed.Links = append(
+			opdsCommonLinks(origin, version, "/authors", opds.NavigationKind),
+			opdsPageLinks(origin+"/authors", r.URL.Query(), metadata, opds.NavigationKind)...,
+		)
+
+		for _, author := range authors {
+			feed.Navigation = append(feed.Navigation, opds.Navigation{
+				ID:    "urn:library:opds:author:" + strconv.FormatInt(author.ID, 10),
+				Title: author.Name,
+				Href:  origin + "/authors/" + strconv.FormatInt(author.ID, 10),
+				Kind:  opds.AcquisitionKind,
+				Count: int(author.BooksAuthored),
+			})
+		}
+
+		writeOPDS(app, w, r, version, feed)
+	}
+}
+
+func OPDSAuthorHandlerGet(app *config.App, version OPDSVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		n, err := app.ReadIDParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		id := strconv.FormatInt(n, 10)
+		serveAcquisition(app, w, r, version, "urn:library:opds:author:"+id, "Books by author "+id, "/authors/"+id, books.ListFilter{AuthorID: n}, "title")
+	}
+}
+
+// OPDSOpenSearchHandlerGet describes the search of the Atom catalogue.
+func OPDSOpenSearchHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		template := config.RequestOrigin(r) + OPDS1.Prefix + "/search?q={searchTerms}&page={startPage?}"
+
+		w.Header().Set("Content-Type", opds.OpenSearchType)
+		w.WriteHeader(http.StatusOK)
+
+		if err := opds.WriteOpenSearch(w, app.Catalogue.Name, template); err != nil {
+			app.Log.Error().Err(err).Msg("opensearch description failed")
+		}
+	}
+}
+
+func serveAcquisition(app *config.App, w http.ResponseWriter, r *http.Request, version OPDSVersion, id, title, path string, filter books.ListFilter, sort string) {
+
+	v := validator.NewValidator()
+	qs := r.URL.Query()
+
+	filters := internal.Filters{
+		Page:         app.ReadInt(qs, "page", 1, v),
+		PageSize:     opdsPageSize,
+		Sort:         sort,
+		SortSafeList: bookSortSafeList,
+	}
+
+	if !filters.ValidateFilters(v) {
+		app.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	ctx := r.Context()
+
+	entries, metadata, err := app.Models.Read.List(ctx, filter, filters)
+	if err != nil {
+		app.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	if filter.AuthorID != 0 && len(entries) > 0 {
+		for _, author := range entries[0].Authors {
+			if author.ID == filter.AuthorID {
+				title = author.Name
+			}
+		}
+	}
+
+	origin := config.RequestOrigin(r) + version.Prefix
+
+	feed := opds.NewFeed(id, title, opds.AcquisitionKind)
+	feed.Links = append(
+		opdsCommonLinks(origin, version, path, opds.AcquisitionKind),
+		opdsPageLinks(origin+path, qs, metadata, opds.AcquisitionKind)...,
+	)
+	feed.AddPublications(entries)
+	feed.TotalItems = metadata.TotalRecords
+	feed.ItemsPerPage = filters.PageSize
+	feed.CurrentPage = filters.Page
+
+	apiOrigin := config.RequestOrigin(r)
+	feed.BookLinks = func(entry *books.ReadEntry) []opds.Link {
+		id := strconv.FormatInt(entry.Book.ID, 10)
+		return []opds.Link{
+			{Rel: opds.RelBorrow, Href: apiOrigin + "/v1/fetch/book/" + id, Type: "application/json", Title: "Catalogue record"},
+			{Rel: opds.RelAlternate, Href: apiOrigin + "/v1/books/" + id + "/marc", Type: "application/marcxml+xml", Title: "MARCXML"},
+		}
+	}
+
+	writeOPDS(app, w, r, version, feed)
+}
+
+func opdsCommonLinks(origin string, version OPDSVersion, path, kind string) []opds.Link {
+	links := []opds.Link{
+		{Rel: opds.RelSelf, Href: origin + path, Feed: true, Kind: kind},
+		{Rel: opds.RelStart, Href: origin, Feed: true, Kind: opds.NavigationKind},
+	}
+
+	if path != "" {
+		links = append(links, opds.Link{Rel: opds.RelUp, Href: origin, Feed: true, Kind: opds.NavigationKind})
+	}
+
+	if version.Prefix == OPDS1.Prefix {
+		links = append(links, opds.Link{Rel: opds.RelSearch, Href: origin + "/opensearch.xml", Type: opds.OpenSearchType})
+	} else {
+		links = append(links, opds.Link{Rel: opds.RelSearch, Href: origin + "/search{?q}", Type: opds.JSONType, Templated: true})
+	}
+
+	return links
+}
+
+func opdsPageLinks(base string, qs url.Values, metadata internal.Metadata, kind string) []opds.Link {
+	if metadata.LastPage <= 1 {
+		return nil
+	}
+
+	page := func(n int) string {
+		q := url.Values{}
+		for key, values := range qs {
+			q[key] = values
+		}
+		q.Set("page", strconv.Itoa(n))
+		return base + "?" + q.Encode()
+	}
+
+	links := []opds.Link{
+		{Rel: opds.RelFirst, Href: page(metadata.FirstPage), Feed: true, Kind: kind},
+		{Rel: opds.RelLast, Href: page(metadata.LastPage), Feed: true, Kind: kind},
+	}
+
+	if metadata.CurrentPage > metadata.FirstPage {
+		links = append(links, opds.Link{Rel: opds.RelPrevious, Href: page(metadata.CurrentPage - 1), Feed: true, Kind: kind})
+	}
+	if metadata.CurrentPage < metadata.LastPage {
+		links = append(links, opds.Link{Rel: opds.RelNext, Href: page(metadata.CurrentPage + 1), Feed: true, Kind: kind})
+	}
+
+	return links
+}
+
+func writeOPDS(app *config.App, w http.ResponseWriter, r *http.Request, version OPDSVersion, feed *opds.Feed) {
+	w.Header().Set("Content-Type", version.contentType(feed.Kind))
+	w.WriteHeader(http.StatusOK)
+
+	if err := version.write(w, feed); err != nil {
+		app.Log.Error().Err(err).Str("feed", feed.ID).Msg("opds feed failed")
+	}
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+func ListRevisionsHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		n, err := app.ReadIDParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		ctx := r.Context()
+
+		revisions, err := app.Models.Revisions.List(ctx, n)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		if len(revisions) == 0 {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+			"revisions": revisions,
+		}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
+
+func DiffRevisionHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		n, err := app.ReadIDParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		version, err := app.ReadVersionParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		qs := r.URL.Query()
+
+		v := validator.NewValidator()
+		against := int32(app.ReadInt(qs, "against", 0, v))
+		v.Check(against >= 0, "against", "must not be a negative version")
+
+		if !v.Valid() {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		ctx := r.Context()
+
+		to, err := app.Models.Revisions.Get(ctx, n, version)
+		if err != nil {
+			switch {
+			case errors.Is(err, books.ErrNotFound):
+				app.NotFoundResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		//version 0 is the empty book, the default is the previous revision
+		from := &books.Revision{}
+		switch {
+		case !qs.Has("against"):
+			previous, err := app.Models.Revisions.Previous(ctx, n, version)
+			switch {
+			case err == nil:
+				from = previous
+			case !errors.Is(err, books.ErrNotFound):
+				app.ServerErrorResponse(w, r, err)
+				return
+			}
+		case against > 0:
+			from, err = app.Models.Revisions.Get(ctx, n, against)
+			if err != nil {
+				switch {
+				case errors.Is(err, books.ErrNotFound):
+					app.NotFoundResponse(w, r)
+				default:
+					app.ServerErrorResponse(w, r, err)
+				}
+				return
+			}
+		}
+
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+			"diff": books.DiffSnapshots(from.Snapshot, to.Snapshot),
+		}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
+
+func RevertRevisionHandlerPost(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		n, err := app.ReadIDParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		version, err := app.ReadVersionParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		ctx := r.Context()
+
+		old_entry := &books.ReadEntry{
+			Book: books.ReadBook{
+				ID: n,
+			},
+			Authors: []books.ReadAuthor{},
+		}
+
+		err = app.Models.Read.Get(ctx, nil, old_entry)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				app.NotFoundResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		ifMatch, err := app.ReadIfMatchVersion(r)
+		if err != nil {
+			app.BadRequestResponse(w, r, err)
+			return
+		}
+
+		if ifMatch == nil {
+			app.PreconditionRequiredResponse(w, r)
+			return
+		}
+
+		if *ifMatch != old_entry.Book.Version {
+			app.VersionConflictResponse(w, r, old_entry.Book.Version)
+			return
+		}
+
+		revision, err := app.Models.Revisions.Get(ctx, n, version)
+		if err != nil {
+			switch {
+			case errors.Is(err, books.ErrNotFound):
+				app.NotFoundResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		snapshot := revision.Snapshot
+
+		new_entry := &books.UpdateEntry{
+			Book: books.UpdateBook{
+				ID:        &n,
+				Title:     &snapshot.Title,
+				Publisher: &snapshot.Publisher,
+				Year:      &snapshot.Year,
+				PageCount: &snapshot.PageCount,
+				Genres:    snapshot.Genres,
+				ISBN:      &snapshot.ISBN,
+				Version:   &old_entry.Book.Version,
+			},
+			Author: books.UpdateAuthors{
+				Name: snapshot.Authors,
+			},
+		}
+
+		if !applyUpdate(app, w, r, new_entry, old_entry) {
+			return
+		}
+
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+			"entry":   old_entry,
+			"message": "reverted to the requested revision",
+		}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/sru"
+)
+
+// SRUHandlerGet answers searchRetrieve, or explain without a query.
+func SRUHandlerGet(app *config.App) http.HandlerFunc {
+	service := &sru.Service{
+		Read:          app.Models.Read,
+		DatabaseTitle: app.Catalogue.Name,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx := r.Context()
+
+		res, err := service.Handle(ctx, requestBaseURL(r), r.URL.Query())
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		out, err := xml.MarshalIndent(res, "", "  ")
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/sru+xml; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(xml.Header))
+		w.Write(out)
+	}
+}
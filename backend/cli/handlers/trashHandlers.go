@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+func ListTrashHandlerGet(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		v := validator.NewValidator()
+		qs := r.URL.Query()
+
+		filters := internal.Filters{
+			Page:         app.ReadInt(qs, "page", 1, v),
+			PageSize:     app.ReadInt(qs, "page_size", 20, v),
+			Sort:         app.ReadString(qs, "sort", "-deleted_at"),
+			SortSafeList: []string{"id", "title", "deleted_at", "-id", "-title", "-deleted_at"},
+		}
+
+		if !filters.ValidateFilters(v) {
+			app.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		ctx := r.Context()
+
+		trashed, metadata, err := app.Models.Delete.ListTrash(ctx, filters)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+			"books":    trashed,
+			"metadata": metadata,
+		}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
+
+func RestoreEntryHandlerPost(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		n, err := app.ReadIDParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		restoreID := &books.DeleteID{
+			ID: n,
+		}
+
+		ctx := r.Context()
+
+		err = app.Tx.Run(ctx, "restore", app.Models.Delete.BeginTx, func(ctx context.Context, tx books.Tx) error {
+			return app.Models.Delete.Restore(ctx, tx, restoreID)
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, books.ErrNotFound):
+				app.NotFoundResponse(w, r)
+			case errors.Is(err, books.ErrEditConflict):
+				app.EditConflictResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{"message": "entry restored from the trash"}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
+
+func PurgeEntryHandlerDelete(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		n, err := app.ReadIDParams(r)
+		if err != nil {
+			app.NotFoundResponse(w, r)
+			return
+		}
+
+		purgeID := &books.DeleteID{
+			ID: n,
+		}
+
+		ctx := r.Context()
+
+		err = app.Tx.Run(ctx, "purge", app.Models.Delete.BeginTx, func(ctx context.Context, tx books.Tx) error {
+			return app.Models.Delete.Purge(ctx, tx, purgeID)
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, books.ErrNotFound):
+				app.NotFoundResponse(w, r)
+			default:
+				app.ServerErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{"message": "entry purged"}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
+
+func PurgeTrashHandlerDelete(app *config.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		olderThan, err := time.ParseDuration(app.ReadString(r.URL.Query(), "older_than", "0s"))
+		if err != nil || olderThan < 0 {
+			app.FailedValidationResponse(w, r, map[string]string{
+				"older_than": "must be a positive duration such as 72h",
+			})
+			return
+		}
+
+		ctx := r.Context()
+
+		var purged int64
+		err = app.Tx.Run(ctx, "purge", app.Models.Delete.BeginTx, func(ctx context.Context, tx books.Tx) (err error) {
+			purged, err = app.Models.Delete.PurgeDeletedBefore(ctx, tx, time.Now().Add(-olderThan))
+			return err
+		})
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		err = app.WriteJson(w, r, http.StatusOK, config.Envelope{
+			"message": "trash purged",
+			"purged":  purged,
+		}, nil)
+		if err != nil {
+			app.ServerErrorResponse(w, r, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+/* Background jobs that live as long as the server does */
+
+import (
+	"context"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+func purgeExpiredTrash(app *config.App, stop <-chan struct{}) {
+	interval := app.ConfigFlags.Trash.PurgeInterval
+
+	if interval <= 0 {
+		app.Log.Info().Msg("scheduled trash purge disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		//the retention can be reloaded, 0 pauses the purge
+		retention := app.TrashRetention()
+		if retention <= 0 {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), app.ConfigFlags.Trash.PurgeTimeout)
+
+		purged, err := purgeTrashOnce(ctx, app, time.Now().Add(-retention))
+		if err != nil {
+			app.Log.Error().Err(err).Msg("scheduled trash purge failed")
+		} else if purged > 0 {
+			app.Log.Info().Int64("purged", purged).Msg("purged expired books from the trash")
+		}
+
+		cancel()
+	}
+}
+
+func purgeTrashOnce(ctx context.Context, app *config.App, before time.Time) (int64, error) {
+	var purged int64
+	err := app.Tx.Run(ctx, "purge", app.Models.Delete.BeginTx, func(ctx context.Context, tx books.Tx) (err error) {
+		purged, err = app.Models.Delete.PurgeDeletedBefore(ctx, tx, before)
+		return err
+	})
+
+	return purged, err
+}
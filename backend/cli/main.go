@@ -1,9 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
+	"github.com/3WDeveloper-GM/library_app/backend/cli/commands"
 	"github.com/3WDeveloper-GM/library_app/backend/config"
 )
 
@@ -11,11 +14,28 @@ func main() {
 
 	app := config.NewAppObject()
 
+	//subcommand
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runCommand(app, os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app.SetConfigFlags()
+
+	if app.ConfigFlags.PrintConfig {
+		app.WriteConfig(os.Stdout)
+		return
+	}
+
 	app.SetLogger()
 
-	app.SetDB()
-	defer app.Database.DB.Close()
+	if err := app.SetDB(); err != nil {
+		app.Log.Panic().Err(err).Send()
+	}
+	defer app.CloseDB()
 
 	app.SetModels()
 
@@ -24,9 +44,28 @@ func main() {
 	c.Stdout = os.Stdout
 	c.Run()
 
+	stopGRPC, err := StartGRPCServer(app)
+	if err != nil {
+		app.Log.Panic().Err(err).Send()
+	}
+	defer stopGRPC()
+
 	//starting server.
-	err := StartServer(app)
+	err = StartServer(app)
 	if err != nil {
 		app.Log.Panic().Err(err).Send()
 	}
 }
+
+func runCommand(app *config.App, name string, args []string) error {
+	switch name {
+	case "import":
+		return commands.Import(app, args)
+	case "export":
+		return commands.Export(app, args)
+	case "migrate":
+		return commands.Migrate(app, args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
@@ -0,0 +1,252 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/cli/handlers"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/gql"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/importer"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/openapi"
+	"github.com/go-chi/chi/v5"
+)
+
+// specSchemas ties every component schema describing a Go payload to its
+// type, envelope-only schemas are listed with a nil type.
+var specSchemas = map[string]reflect.Type{
+	"Error":           nil,
+	"VersionConflict": nil,
+	"DuplicateBook":   nil,
+	"Message":         nil,
+	"Metadata":        reflect.TypeOf(internal.Metadata{}),
+	"Book":            reflect.TypeOf(books.ReadBook{}),
+	"Author":          reflect.TypeOf(books.ReadAuthor{}),
+	"BookEntry":       reflect.TypeOf(books.ReadEntry{}),
+	"AuthorEntry":     reflect.TypeOf(books.ReadAuthorEntry{}),
+	"InsertBookInput": reflect.TypeOf(handlers.InsertBookInput{}),
+	"UpdateBookInput": reflect.TypeOf(handlers.UpdateBookInput{}),
+	"TrashedBook":     reflect.TypeOf(books.TrashedBook{}),
+	"AuditEntry":      reflect.TypeOf(audit.Entry{}),
+	"Snapshot":        reflect.TypeOf(books.Snapshot{}),
+	"Revision":        reflect.TypeOf(books.Revision{}),
+	"FieldChange":     reflect.TypeOf(books.FieldChange{}),
+	"RevisionDiff":    reflect.TypeOf(books.RevisionDiff{}),
+	"ImportResult":    reflect.TypeOf(importer.Result{}),
+	"ImportReport":    reflect.TypeOf(importer.Report{}),
+	"GraphQLRequest":  reflect.TypeOf(gql.Request{}),
+}
+
+func TestSpecCoversRoutes(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes := map[string]bool{}
+	err = chi.Walk(API(&config.App{}), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes[strings.ToLower(method)+" "+route] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented := map[string]bool{}
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			documented[method+" "+path] = true
+		}
+	}
+
+	for _, route := range sortedKeys(routes) {
+		if !documented[route] {
+			t.Errorf("route %s is not in the OpenAPI document", route)
+		}
+	}
+	for _, route := range sortedKeys(documented) {
+		if !routes[route] {
+			t.Errorf("the OpenAPI document describes %s, which is not routed", route)
+		}
+	}
+}
+
+func TestSpecMatchesPayloads(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name := range doc.Components.Schemas {
+		if _, ok := specSchemas[name]; !ok {
+			t.Errorf("schema %s is not tied to a Go type in specSchemas", name)
+		}
+	}
+
+	for name, typ := range specSchemas {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is missing from the OpenAPI document", name)
+			continue
+		}
+		if typ != nil {
+			compareSchema(t, doc, name, schema, typ)
+		}
+	}
+}
+
+func TestSpecValidatesRequests(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op, ok := doc.Operation(http.MethodPost, "/v1/insert/book")
+	if !ok {
+		t.Fatal("no operation for POST /v1/insert/book")
+	}
+	schema := op.RequestBody.Content["application/json"].Schema
+
+	tests := []struct {
+		body string
+		errs []string
+	}{
+		{`{"book":{"title":"Dune","year":1965},"authors":{"names":["Frank Herbert"]}}`, nil},
+		{`{"book":{"title":7,"year":"1965"},"authors":{"names":[1]}}`, []string{"authors.names[0]", "book.title", "book.year"}},
+		{`{"book":{"year":1965.5},"authors":{},"extra":true}`, []string{"book.year", "extra"}},
+		{`{"book":{}}`, []string{"authors"}},
+		{`[]`, []string{"body"}},
+	}
+
+	for _, tt := range tests {
+		dec := json.NewDecoder(strings.NewReader(tt.body))
+		dec.UseNumber()
+
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			t.Fatal(err)
+		}
+
+		errs := doc.Validate(schema, value)
+		if got := sortedKeys(errs); !reflect.DeepEqual(got, append([]string{}, tt.errs...)) {
+			t.Errorf("%s: got errors %v, want %v", tt.body, errs, tt.errs)
+		}
+	}
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// compareSchema walks a Go type alongside its schema, both must name the
+// same json fields with compatible types.
+func compareSchema(t *testing.T, doc *openapi.Document, path string, schema *openapi.Schema, typ reflect.Type) {
+	t.Helper()
+
+	schema = doc.Resolve(schema)
+	if schema == nil {
+		t.Errorf("%s: dangling $ref", path)
+		return
+	}
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	want := ""
+	switch {
+	case typ == timeType:
+		if schema.Type != "string" || schema.Format != "date-time" {
+			t.Errorf("%s: time.Time should be a date-time string, the spec says %q", path, schema.Type)
+		}
+		return
+	case typ == rawType || typ.Kind() == reflect.Interface:
+		want = ""
+	case typ.Kind() == reflect.Struct || typ.Kind() == reflect.Map:
+		want = "object"
+	case typ.Kind() == reflect.Slice:
+		want = "array"
+	case typ.Kind() == reflect.String:
+		want = "string"
+	case typ.Kind() == reflect.Bool:
+		want = "boolean"
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		want = "integer"
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		want = "number"
+	}
+
+	if schema.Type != want {
+		t.Errorf("%s: %s should be %q in the spec, found %q", path, typ, want, schema.Type)
+		return
+	}
+
+	switch want {
+	case "array":
+		if schema.Items == nil {
+			t.Errorf("%s: array without items", path)
+			return
+		}
+		compareSchema(t, doc, path+"[]", schema.Items, typ.Elem())
+
+	case "object":
+		if typ.Kind() == reflect.Map {
+			return
+		}
+
+		fields := jsonFields(typ)
+		for name, field := range fields {
+			property, ok := schema.Properties[name]
+			if !ok {
+				t.Errorf("%s: field %q is missing from the spec", path, name)
+				continue
+			}
+			compareSchema(t, doc, path+"."+name, property, field)
+		}
+		for name := range schema.Properties {
+			if _, ok := fields[name]; !ok {
+				t.Errorf("%s: the spec lists %q, which %s does not have", path, name, typ)
+			}
+		}
+	}
+}
+
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"github.com/3WDeveloper-GM/library_app/backend/cli/handlers"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/openapi"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+)
+
+// API builds the router of the HTTP api, the server and the tests share it.
+func API(app *config.App) *chi.Mux {
+	r := chi.NewMux()
+
+	r.Use(cors.Handler(cors.Options{
+		//-cors-origins, reloaded on SIGHUP
+		AllowOriginFunc:  app.AllowOrigin,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Actor", "X-Request-Id"},
+		ExposedHeaders:   []string{"Link", "X-Request-Id"},
+		AllowCredentials: false,
+		MaxAge:           300, // Maximum value not ignored by any of major browsers
+	}))
+	r.Use(middleware.RequestID)
+	r.Use(app.AuditMeta)
+	r.Use(app.VisitedRouteLogger)
+	r.Use(openapi.MustLoad().ValidateRequests(app.ConfigFlags.Server.MaxBodyBytes, app.FailedValidationResponse, app.ContentTooLargeResponse))
+	r.NotFound(app.NotFoundResponse)
+	r.MethodNotAllowed(app.MethodNotAllowedResponse)
+
+	r.Get("/v1/openapi.json", handlers.OpenAPIHandlerGet(app))
+	r.Get("/v1/docs", handlers.DocsHandlerGet(app))
+
+	r.Group(func(r chi.Router) {
+		r.Use(app.Timeout(config.RouteRead))
+
+		r.Get("/v1/books", handlers.ListEntriesHandlerGet(app))
+		opdsRoutes(r, app, handlers.OPDS1)
+		opdsRoutes(r, app, handlers.OPDS2)
+		r.Get(handlers.OPDS1.Prefix+"/opensearch.xml", handlers.OPDSOpenSearchHandlerGet(app))
+		r.Get("/v1/fetch/book/{id}", handlers.FetchEntryHandlerGet(app))
+		r.Get("/v1/fetch/author/{id}", handlers.FetchAuthorEntryHandlerGet(app))
+		r.Get("/v1/trash", handlers.ListTrashHandlerGet(app))
+		r.Get("/v1/books/{id}/marc", handlers.FetchMARCHandlerGet(app))
+		r.Get("/v1/books/{id}/cite", handlers.CiteEntryHandlerGet(app))
+		r.Get("/v1/cite", handlers.CiteEntriesHandlerGet(app))
+		r.Get("/v1/books/{id}/revisions", handlers.ListRevisionsHandlerGet(app))
+		r.Get("/v1/books/{id}/revisions/{version}/diff", handlers.DiffRevisionHandlerGet(app))
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(app.Timeout(config.RouteWrite))
+
+		r.Post("/v1/insert/book", handlers.InsertEntryHandlerPost(app))
+		r.Patch("/v1/update/book/{id}", handlers.UpdateEntriesHandlerPatch(app))
+		r.Delete("/v1/delete/book/{id}", handlers.DeleteEntryHandlerDelete(app))
+		r.Post("/v1/books/{id}/restore", handlers.RestoreEntryHandlerPost(app))
+		r.Post("/v1/books/{id}/revisions/{version}/revert", handlers.RevertRevisionHandlerPost(app))
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(app.Timeout(config.RouteSearch))
+
+		r.Get("/v1/oai", handlers.OAIHandler(app))
+		r.Post("/v1/oai", handlers.OAIHandler(app))
+		r.Get("/v1/sru", handlers.SRUHandlerGet(app))
+		r.Post("/graphql", handlers.GraphQLHandlerPost(app))
+	})
+
+	r.With(app.Timeout(config.RouteImport)).Post("/v1/import/books", handlers.ImportBooksHandlerPost(app))
+	r.With(app.Timeout(config.RouteImport)).Post("/v1/import/marc", handlers.ImportMARCHandlerPost(app))
+	r.With(app.Timeout(config.RouteExport)).Get("/v1/export", handlers.ExportHandlerGet(app))
+
+	r.Group(func(r chi.Router) {
+		r.Use(app.RequireAdmin)
+
+		r.With(app.Timeout(config.RouteWrite)).Delete("/v1/trash", handlers.PurgeTrashHandlerDelete(app))
+		r.With(app.Timeout(config.RouteWrite)).Delete("/v1/trash/{id}", handlers.PurgeEntryHandlerDelete(app))
+
+		r.With(app.Timeout(config.RouteRead)).Get("/v1/audit", handlers.ListAuditHandlerGet(app))
+		r.Get("/v1/metrics", handlers.MetricsHandlerGet(app))
+	})
+
+	return r
+}
+
+func opdsRoutes(r chi.Router, app *config.App, version handlers.OPDSVersion) {
+	r.Get(version.Prefix, handlers.OPDSRootHandlerGet(app, version))
+	r.Get(version.Prefix+"/new", handlers.OPDSNewHandlerGet(app, version))
+	r.Get(version.Prefix+"/search", handlers.OPDSSearchHandlerGet(app, version))
+	r.Get(version.Prefix+"/genres", handlers.OPDSGenresHandlerGet(app, version))
+	r.Get(version.Prefix+"/genres/{genre}", handlers.OPDSGenreHandlerGet(app, version))
+	r.Get(version.Prefix+"/authors", handlers.OPDSAuthorsHandlerGet(app, version))
+	r.Get(version.Prefix+"/authors/{id}", handlers.OPDSAuthorHandlerGet(app, version))
+}
@@ -1,6 +1,6 @@
 package main
 
-/* Server configuration, routing and the like */
+/* Server configuration and the like, the routing lives in cli/routes */
 
 import (
 	"context"
@@ -10,48 +10,19 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"github.com/3WDeveloper-GM/library_app/backend/cli/handlers"
+	"github.com/3WDeveloper-GM/library_app/backend/cli/routes"
 	"github.com/3WDeveloper-GM/library_app/backend/config"
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/cors"
 )
 
-func getApiRoutes(app *config.App) *chi.Mux {
-	r := chi.NewMux()
-
-	r.Use(cors.Handler(cors.Options{
-		// AllowedOrigins:   []string{"https://foo.com"}, // Use this to allow specific origin hosts
-		AllowedOrigins: []string{"https://*", "http://*"},
-		// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: false,
-		MaxAge:           300, // Maximum value not ignored by any of major browsers
-	}))
-	r.Use(app.VisitedRouteLogger)
-	r.NotFound(app.NotFoundResponse)
-	r.MethodNotAllowed(app.MethodNotAllowedResponse)
-
-	r.Post("/v1/insert/book", handlers.InsertEntryHandlerPost(app))
-	r.Get("/v1/fetch/book/{id}", handlers.FetchEntryHandlerGet(app))
-	r.Get("/v1/fetch/author/{id}", handlers.FetchAuthorEntryHandlerGet(app))
-	r.Patch("/v1/update/book/{id}", handlers.UpdateEntriesHandlerPatch(app))
-	r.Delete("/v1/delete/book/{id}", handlers.DeleteEntryHandlerDelete(app))
-
-	return r
-}
-
 func StartServer(app *config.App) error {
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", app.ConfigFlags.Port),
-		Handler:      getApiRoutes(app),
-		IdleTimeout:  time.Minute,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		Handler:      routes.API(app),
+		IdleTimeout:  app.ConfigFlags.Server.IdleTimeout,
+		ReadTimeout:  app.ConfigFlags.Server.ReadTimeout,
+		WriteTimeout: app.ConfigFlags.Server.WriteTimeout,
 	}
 
 	/* Graceful shutdown section */
@@ -67,12 +38,31 @@ func StartServer(app *config.App) error {
 			Str("signal", s.String()).
 			Msg("shutting server down with signal")
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), app.ConfigFlags.Server.ShutdownTimeout)
 		defer cancel()
 
 		shutdownErr <- server.Shutdown(ctx)
 	}()
 
+	/* Reloading the settings on SIGHUP */
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+
+	go func() {
+		for range reload {
+			if err := app.Reload(); err != nil {
+				app.Log.Error().Err(err).Msg("could not reload the settings, keeping the current ones")
+			}
+		}
+	}()
+
+	stopJobs := make(chan struct{})
+	defer close(stopJobs)
+
+	go purgeExpiredTrash(app, stopJobs)
+
 	app.Log.Info().
 		Interface("configuration", app.ConfigFlags).
 		Msg("started server with the configuration")
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+func (c *Client) ListTrash(ctx context.Context, opts ListOptions) ([]TrashedBook, Metadata, error) {
+	return c.listTrash(ctx, opts, opts.Page)
+}
+
+func (c *Client) listTrash(ctx context.Context, opts ListOptions, page int) ([]TrashedBook, Metadata, error) {
+	var env struct {
+		Books    []TrashedBook `json:"books"`
+		Metadata Metadata      `json:"metadata"`
+	}
+
+	err := c.do(ctx, &request{method: http.MethodGet, path: "/v1/trash", query: opts.query(url.Values{}, page)}, &env)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return env.Books, env.Metadata, nil
+}
+
+// Trash iterates over the deleted books, from opts.Page on.
+func (c *Client) Trash(ctx context.Context, opts ListOptions) *Iterator[TrashedBook] {
+	return newIterator(ctx, opts.Page, func(ctx context.Context, page int) ([]TrashedBook, Metadata, error) {
+		return c.listTrash(ctx, opts, page)
+	})
+}
+
+func (c *Client) RestoreBook(ctx context.Context, id int64) error {
+	return c.do(ctx, &request{method: http.MethodPost, path: fmt.Sprintf("/v1/books/%d/restore", id)}, nil)
+}
+
+// PurgeBook deletes a trashed book for good, it needs the admin token.
+func (c *Client) PurgeBook(ctx context.Context, id int64) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: fmt.Sprintf("/v1/trash/%d", id)}, nil)
+}
+
+// PurgeTrash deletes the books trashed more than olderThan ago, all when zero.
+func (c *Client) PurgeTrash(ctx context.Context, olderThan time.Duration) (int64, error) {
+	var env struct {
+		Purged int64 `json:"purged"`
+	}
+
+	query := url.Values{"older_than": {olderThan.String()}}
+
+	err := c.do(ctx, &request{method: http.MethodDelete, path: "/v1/trash", query: query}, &env)
+	if err != nil {
+		return 0, err
+	}
+
+	return env.Purged, nil
+}
+
+func (c *Client) ListRevisions(ctx context.Context, id int64) ([]Revision, error) {
+	var env struct {
+		Revisions []Revision `json:"revisions"`
+	}
+
+	err := c.do(ctx, &request{method: http.MethodGet, path: fmt.Sprintf("/v1/books/%d/revisions", id)}, &env)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Revisions, nil
+}
+
+// DiffRevision compares a revision with the one before it.
+func (c *Client) DiffRevision(ctx context.Context, id int64, version int32) (*RevisionDiff, error) {
+	var env struct {
+		Diff *RevisionDiff `json:"diff"`
+	}
+
+	err := c.do(ctx, &request{method: http.MethodGet, path: fmt.Sprintf("/v1/books/%d/revisions/%d/diff", id, version)}, &env)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Diff, nil
+}
+
+// RevertRevision writes an old revision back over the expected version, if not zero.
+func (c *Client) RevertRevision(ctx context.Context, id int64, version, expected int32) (*Entry, error) {
+	req := &request{method: http.MethodPost, path: fmt.Sprintf("/v1/books/%d/revisions/%d/revert", id, version)}
+	if expected != 0 {
+		req.header = http.Header{"If-Match": {strconv.Quote(strconv.Itoa(int(expected)))}}
+	}
+
+	var env entryEnvelope
+	if err := c.do(ctx, req, &env); err != nil {
+		return nil, err
+	}
+
+	return env.Entry, nil
+}
+
+type AuditQuery struct {
+	Actor     string
+	Entity    string
+	EntityID  int64
+	Action    string
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+	ListOptions
+}
+
+func (q AuditQuery) values(page int) url.Values {
+	values := url.Values{}
+	for key, value := range map[string]string{
+		"actor":      q.Actor,
+		"entity":     q.Entity,
+		"action":     q.Action,
+		"request_id": q.RequestID,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if q.EntityID != 0 {
+		values.Set("entity_id", strconv.FormatInt(q.EntityID, 10))
+	}
+	if !q.Since.IsZero() {
+		values.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		values.Set("until", q.Until.Format(time.RFC3339))
+	}
+	return q.ListOptions.query(values, page)
+}
+
+// ListAudit reads the audit log, it needs the admin token.
+func (c *Client) ListAudit(ctx context.Context, q AuditQuery) ([]AuditEntry, Metadata, error) {
+	return c.listAudit(ctx, q, q.Page)
+}
+
+func (c *Client) listAudit(ctx context.Context, q AuditQuery, page int) ([]AuditEntry, Metadata, error) {
+	var env struct {
+		Entries  []AuditEntry `json:"entries"`
+		Metadata Metadata     `json:"metadata"`
+	}
+
+	err := c.do(ctx, &request{method: http.MethodGet, path: "/v1/audit", query: q.values(page)}, &env)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return env.Entries, env.Metadata, nil
+}
+
+// Audit iterates over the audit log, from q.Page on.
+func (c *Client) Audit(ctx context.Context, q AuditQuery) *Iterator[AuditEntry] {
+	return newIterator(ctx, q.Page, func(ctx context.Context, page int) ([]AuditEntry, Metadata, error) {
+		return c.listAudit(ctx, q, page)
+	})
+}
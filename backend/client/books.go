@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type entryEnvelope struct {
+	Entry *Entry `json:"entry"`
+}
+
+func (c *Client) CreateBook(ctx context.Context, book NewBook) (*Entry, error) {
+	type bookInput struct {
+		Title     string   `json:"title"`
+		Publisher string   `json:"publisher"`
+		Year      int32    `json:"year"`
+		PageCount int32    `json:"page_count"`
+		Genres    []string `json:"genres,omitempty"`
+		ISBN      string   `json:"isbn,omitempty"`
+	}
+
+	input := struct {
+		Book    bookInput `json:"book"`
+		Authors struct {
+			Names []string `json:"names,omitempty"`
+		} `json:"authors"`
+	}{
+		Book: bookInput{
+			Title:     book.Title,
+			Publisher: book.Publisher,
+			Year:      book.Year,
+			PageCount: book.PageCount,
+			Genres:    book.Genres,
+			ISBN:      book.ISBN,
+		},
+	}
+	input.Authors.Names = book.Authors
+
+	var env entryEnvelope
+	err := c.do(ctx, &request{method: http.MethodPost, path: "/v1/insert/book", json: input}, &env)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Entry, nil
+}
+
+func (c *Client) GetBook(ctx context.Context, id int64) (*Entry, error) {
+	var env entryEnvelope
+	err := c.do(ctx, &request{method: http.MethodGet, path: fmt.Sprintf("/v1/fetch/book/%d", id)}, &env)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Entry, nil
+}
+
+func (c *Client) UpdateBook(ctx context.Context, id int64, update BookUpdate) (*Entry, error) {
+	input := struct {
+		Book struct {
+			Title     *string  `json:"title,omitempty"`
+			Publisher *string  `json:"publisher,omitempty"`
+			Year      *int32   `json:"year,omitempty"`
+			PageCount *int32   `json:"page_count,omitempty"`
+			Genres    []string `json:"genres,omitempty"`
+			ISBN      *string  `json:"isbn,omitempty"`
+			Version   *int32   `json:"version,omitempty"`
+		} `json:"book"`
+		Authors struct {
+			Names []string `json:"names,omitempty"`
+		} `json:"authors"`
+	}{}
+
+	input.Book.Title = update.Title
+	input.Book.Publisher = update.Publisher
+	input.Book.Year = update.Year
+	input.Book.PageCount = update.PageCount
+	input.Book.Genres = update.Genres
+	input.Book.ISBN = update.ISBN
+	input.Book.Version = update.Version
+	input.Authors.Names = update.Authors
+
+	var env entryEnvelope
+	err := c.do(ctx, &request{method: http.MethodPatch, path: fmt.Sprintf("/v1/update/book/%d", id), json: input}, &env)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Entry, nil
+}
+
+// DeleteBook moves the book to the trash, RestoreBook brings it back.
+func (c *Client) DeleteBook(ctx context.Context, id int64) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: fmt.Sprintf("/v1/delete/book/%d", id)}, nil)
+}
+
+type BookQuery struct {
+	// Query matches titles, publishers and author names
+	Query    string
+	Genre    string
+	AuthorID int64
+	ListOptions
+}
+
+func (q BookQuery) values(page int) url.Values {
+	values := url.Values{}
+	if q.Query != "" {
+		values.Set("q", q.Query)
+	}
+	if q.Genre != "" {
+		values.Set("genre", q.Genre)
+	}
+	if q.AuthorID != 0 {
+		values.Set("author_id", strconv.FormatInt(q.AuthorID, 10))
+	}
+	return q.ListOptions.query(values, page)
+}
+
+func (c *Client) ListBooks(ctx context.Context, q BookQuery) ([]Entry, Metadata, error) {
+	return c.listBooks(ctx, q, q.Page)
+}
+
+func (c *Client) listBooks(ctx context.Context, q BookQuery, page int) ([]Entry, Metadata, error) {
+	var env struct {
+		Books    []Entry  `json:"books"`
+		Metadata Metadata `json:"metadata"`
+	}
+
+	err := c.do(ctx, &request{method: http.MethodGet, path: "/v1/books", query: q.values(page)}, &env)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return env.Books, env.Metadata, nil
+}
+
+// Books iterates over every book matching q, from q.Page on.
+func (c *Client) Books(ctx context.Context, q BookQuery) *Iterator[Entry] {
+	return newIterator(ctx, q.Page, func(ctx context.Context, page int) ([]Entry, Metadata, error) {
+		return c.listBooks(ctx, q, page)
+	})
+}
+
+func (c *Client) GetAuthor(ctx context.Context, id int64) (*AuthorEntry, error) {
+	var env struct {
+		Entry *AuthorEntry `json:"entry"`
+	}
+
+	err := c.do(ctx, &request{method: http.MethodGet, path: fmt.Sprintf("/v1/fetch/author/%d", id)}, &env)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Entry, nil
+}
+
+const authorsQuery = `query($sort: String, $page: Int, $pageSize: Int) {
+  authors(sort: $sort, page: $page, pageSize: $pageSize) {
+    nodes { id name identifier booksAuthored }
+    metadata { currentPage pageSize firstPage lastPage totalRecords }
+  }
+}`
+
+// ListAuthors pages through the authors of live books, over GraphQL.
+func (c *Client) ListAuthors(ctx context.Context, opts ListOptions) ([]Author, Metadata, error) {
+	return c.listAuthors(ctx, opts, opts.Page)
+}
+
+func (c *Client) listAuthors(ctx context.Context, opts ListOptions, page int) ([]Author, Metadata, error) {
+	variables := map[string]interface{}{}
+	if opts.Sort != "" {
+		variables["sort"] = opts.Sort
+	}
+	if page > 0 {
+		variables["page"] = page
+	}
+	if opts.PageSize > 0 {
+		variables["pageSize"] = opts.PageSize
+	}
+
+	var data struct {
+		Authors struct {
+			Nodes []struct {
+				ID            string `json:"id"`
+				Name          string `json:"name"`
+				Identifier    string `json:"identifier"`
+				BooksAuthored int32  `json:"booksAuthored"`
+			} `json:"nodes"`
+			Metadata struct {
+				CurrentPage  int `json:"currentPage"`
+				PageSize     int `json:"pageSize"`
+				FirstPage    int `json:"firstPage"`
+				LastPage     int `json:"lastPage"`
+				TotalRecords int `json:"totalRecords"`
+			} `json:"metadata"`
+		} `json:"authors"`
+	}
+
+	if err := c.GraphQL(ctx, authorsQuery, variables, &data); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	authors := make([]Author, len(data.Authors.Nodes))
+	for i, node := range data.Authors.Nodes {
+		id, err := strconv.ParseInt(node.ID, 10, 64)
+		if err != nil {
+			return nil, Metadata{}, fmt.Errorf("client: author id %q: %w", node.ID, err)
+		}
+		authors[i] = Author{ID: id, Name: node.Name, Identifier: node.Identifier, BooksAuthored: node.BooksAuthored}
+	}
+
+	return authors, Metadata(data.Authors.Metadata), nil
+}
+
+// Authors iterates over the authors, from opts.Page on.
+func (c *Client) Authors(ctx context.Context, opts ListOptions) *Iterator[Author] {
+	return newIterator(ctx, opts.Page, func(ctx context.Context, page int) ([]Author, Metadata, error) {
+		return c.listAuthors(ctx, opts, page)
+	})
+}
@@ -0,0 +1,225 @@
+// Package client is the Go SDK of the json endpoints of the library api.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy retries GET and DELETE on network errors, 429 and 502-504.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  2 * time.Second,
+}
+
+type Client struct {
+	BaseURL    *url.URL
+	HTTPClient *http.Client
+	// Token is sent as a bearer token, the admin endpoints need it
+	Token string
+	// Actor is recorded in the audit log for the changes made by the client
+	Actor string
+	Retry RetryPolicy
+}
+
+// New returns a client for the api served at baseURL.
+func New(baseURL string) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("client: base url %q must be absolute", baseURL)
+	}
+
+	return &Client{
+		BaseURL:    u,
+		HTTPClient: http.DefaultClient,
+		Retry:      DefaultRetryPolicy,
+	}, nil
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   io.Reader
+	// json is marshalled as the body, it takes precedence over body
+	json interface{}
+}
+
+func (c *Client) newRequest(ctx context.Context, req *request, body io.Reader) (*http.Request, error) {
+	u := *c.BaseURL
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+
+	r, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range req.header {
+		r.Header[key] = values
+	}
+	if r.Header.Get("Accept") == "" {
+		r.Header.Set("Accept", "application/json")
+	}
+	if c.Token != "" {
+		r.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Actor != "" {
+		r.Header.Set("X-Actor", c.Actor)
+	}
+
+	return r, nil
+}
+
+// send returns the response of a 2xx, anything else as *Error.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	var payload []byte
+	if req.json != nil {
+		js, err := json.Marshal(req.json)
+		if err != nil {
+			return nil, err
+		}
+		payload = js
+
+		if req.header == nil {
+			req.header = http.Header{}
+		}
+		req.header.Set("Content-Type", "application/json")
+	}
+
+	attempts := 1
+	if idempotent(req.method) && req.body == nil && c.Retry.MaxAttempts > 1 {
+		attempts = c.Retry.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		body := req.body
+		if payload != nil {
+			body = bytes.NewReader(payload)
+		}
+
+		r, err := c.newRequest(ctx, req, body)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := c.HTTPClient.Do(r)
+		if err == nil && res.StatusCode < 300 {
+			return res, nil
+		}
+
+		var wait time.Duration
+		if err == nil {
+			err = decodeError(res)
+			wait = retryAfter(res)
+			res.Body.Close()
+		}
+
+		if attempt >= attempts || !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		if wait == 0 || wait > c.Retry.MaxBackoff {
+			wait = c.backoff(attempt)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do decodes the json envelope of the response into a non nil out.
+func (c *Client) do(ctx context.Context, req *request, out interface{}) error {
+	res, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, res.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decoding the %s %s response: %w", req.method, req.path, err)
+	}
+
+	return nil
+}
+
+// read sends the request and returns the raw body of the response.
+func (c *Client) read(ctx context.Context, req *request) ([]byte, error) {
+	res, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return io.ReadAll(res.Body)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryable(err error) bool {
+	if e, ok := err.(*Error); ok {
+		switch e.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// anything else never got a response
+	return true
+}
+
+// backoff doubles with every attempt, with full jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	limit := c.Retry.MinBackoff << (attempt - 1)
+	if limit <= 0 || limit > c.Retry.MaxBackoff {
+		limit = c.Retry.MaxBackoff
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(limit)) + 1)
+}
+
+func retryAfter(res *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,253 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/cli/routes"
+	"github.com/3WDeveloper-GM/library_app/backend/client"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+	"github.com/rs/zerolog"
+)
+
+const adminToken = "s3cret"
+
+// newAPI serves the real router over an empty in-memory store.
+func newAPI(t *testing.T, wrap func(http.Handler) http.Handler) *client.Client {
+	t.Helper()
+
+	app := config.NewAppObject()
+	nop := zerolog.Nop()
+	app.Log = &nop
+	app.Admin.Token = adminToken
+	app.ConfigFlags.Storage = config.StorageMemory
+	app.SetModels()
+
+	var handler http.Handler = routes.API(app)
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+
+	return newClient(t, handler)
+}
+
+func newClient(t *testing.T, handler http.Handler) *client.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := client.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Retry.MinBackoff = time.Millisecond
+	c.Retry.MaxBackoff = 5 * time.Millisecond
+
+	return c
+}
+
+func TestValidationErrors(t *testing.T) {
+	c := newAPI(t, nil)
+
+	_, err := c.CreateBook(context.Background(), client.NewBook{Year: 1965, PageCount: 412})
+	if !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("got %v, want a validation error", err)
+	}
+
+	fields, ok := client.ValidationErrors(err)
+	if !ok {
+		t.Fatal("ValidationErrors did not recognise the error")
+	}
+	for _, field := range []string{"title", "publisher", "genres"} {
+		if fields[field] == "" {
+			t.Errorf("no error for %q in %v", field, fields)
+		}
+	}
+
+	_, _, err = c.ListBooks(context.Background(), client.BookQuery{ListOptions: client.ListOptions{PageSize: 1000, Sort: "pages"}})
+	fields, _ = client.ValidationErrors(err)
+	if fields["page_size"] == "" || fields["sort"] == "" {
+		t.Errorf("got %v, want page_size and sort errors", err)
+	}
+}
+
+func TestErrorStatuses(t *testing.T) {
+	c := newAPI(t, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		call   func(*client.Client) error
+		target error
+		status int
+	}{
+		{"invalid book id", func(c *client.Client) error { _, err := c.GetBook(ctx, 0); return err }, client.ErrNotFound, http.StatusNotFound},
+		{"invalid revision", func(c *client.Client) error { _, err := c.DiffRevision(ctx, 1, 0); return err }, client.ErrNotFound, http.StatusNotFound},
+		{"missing token", func(c *client.Client) error { _, err := c.PurgeTrash(ctx, 0); return err }, client.ErrUnauthorized, http.StatusUnauthorized},
+		{"unknown export format", func(c *client.Client) error { _, err := c.Export(ctx, client.ExportQuery{Format: "pdf"}); return err }, client.ErrValidation, http.StatusUnprocessableEntity},
+		{"unknown citation format", func(c *client.Client) error { _, err := c.CiteMany(ctx, "harvard", 1, 2); return err }, client.ErrValidation, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		err := tt.call(c)
+
+		var e *client.Error
+		if !errors.As(err, &e) || e.StatusCode != tt.status || !errors.Is(err, tt.target) {
+			t.Errorf("%s: got %v, want a %d", tt.name, err, tt.status)
+			continue
+		}
+		if e.RequestID == "" {
+			t.Errorf("%s: the request id was not picked up", tt.name)
+		}
+	}
+
+	c.Token = adminToken
+	_, err := c.PurgeTrash(ctx, -time.Hour)
+	if fields, _ := client.ValidationErrors(err); fields["older_than"] == "" {
+		t.Errorf("got %v, want an older_than error with the admin token", err)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	c := newAPI(t, nil)
+
+	var data struct {
+		Book *struct{ Title string }
+	}
+	err := c.GraphQL(context.Background(), `query($id: ID!) { book(id: $id) { title } }`, map[string]interface{}{"id": "abc"}, &data)
+
+	var gqlErrs client.GraphQLErrors
+	if !errors.As(err, &gqlErrs) || len(gqlErrs) != 1 {
+		t.Fatalf("got %v, want one graphql error", err)
+	}
+	if code := gqlErrs[0].Extensions["code"]; code != "VALIDATION_FAILED" {
+		t.Errorf("got code %v, want VALIDATION_FAILED", code)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	c := newAPI(t, nil)
+
+	doc, err := c.OpenAPI(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc) == 0 || doc[0] != '{' {
+		t.Errorf("the document does not look like json: %.40q", doc)
+	}
+}
+
+// flaky answers the first n requests with a 503 before letting them reach
+// the router.
+func flaky(n int32, calls *int32) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(calls, 1) <= n {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int32
+	c := newAPI(t, flaky(2, &calls))
+
+	if _, err := c.OpenAPI(ctx); err != nil {
+		t.Fatalf("got %v after retrying", err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+
+	calls = 0
+	c = newAPI(t, flaky(5, &calls))
+
+	_, err := c.OpenAPI(ctx)
+	var e *client.Error
+	if !errors.As(err, &e) || e.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got %v, want the last 503", err)
+	}
+	if calls != int32(client.DefaultRetryPolicy.MaxAttempts) {
+		t.Errorf("got %d calls, want %d", calls, client.DefaultRetryPolicy.MaxAttempts)
+	}
+
+	// creating a book is not idempotent, it must not be sent twice
+	calls = 0
+	c = newAPI(t, flaky(1, &calls))
+
+	c.CreateBook(ctx, client.NewBook{})
+	if calls != 1 {
+		t.Errorf("got %d calls for a POST, want 1", calls)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error":"unable to update the record, it was modified since the version you provided","current_version":4}`))
+	}))
+
+	version := int32(3)
+	_, err := c.UpdateBook(context.Background(), 1, client.BookUpdate{Version: &version})
+
+	var e *client.Error
+	if !errors.As(err, &e) || !errors.Is(err, client.ErrConflict) {
+		t.Fatalf("got %v, want a conflict", err)
+	}
+	if e.CurrentVersion != 4 {
+		t.Errorf("got current version %d, want 4", e.CurrentVersion)
+	}
+}
+
+func TestIterator(t *testing.T) {
+	const total, pageSize = 7, 3
+
+	var pages []string
+	c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pages = append(pages, r.URL.Query().Get("page"))
+
+		books := `[`
+		for id := (page-1)*pageSize + 1; id <= page*pageSize && id <= total; id++ {
+			if len(books) > 1 {
+				books += ","
+			}
+			books += `{"Book":{"id":` + strconv.Itoa(id) + `},"authors":[]}`
+		}
+		books += `]`
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"books":` + books + `,"metadata":{"current_page":` + strconv.Itoa(page) +
+			`,"page_size":3,"first_page":1,"last_page":3,"total_records":7}}`))
+	}))
+
+	var ids []int64
+	it := c.Books(context.Background(), client.BookQuery{Genre: "fantasy", ListOptions: client.ListOptions{PageSize: pageSize}})
+	for it.Next() {
+		ids = append(ids, it.Value().Book.ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int64{1, 2, 3, 4, 5, 6, 7}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got ids %v, want %v", ids, want)
+	}
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("fetched pages %v, want %v", pages, want)
+	}
+}
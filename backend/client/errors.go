@@ -0,0 +1,98 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNotFound     = errors.New("client: not found")
+	ErrConflict     = errors.New("client: conflict")
+	ErrValidation   = errors.New("client: validation failed")
+	ErrUnauthorized = errors.New("client: unauthorized")
+)
+
+// Error is a non 2xx response, matched by errors.Is against the Err values.
+type Error struct {
+	StatusCode int
+	Message    string
+	// Fields holds the validation errors of a 422, keyed by field
+	Fields map[string]string
+	// CurrentVersion is set when a 409 was caused by a stale version
+	CurrentVersion int32
+	RequestID      string
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return fmt.Sprintf("client: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+
+	fields := make([]string, 0, len(e.Fields))
+	for field, message := range e.Fields {
+		fields = append(fields, field+": "+message)
+	}
+	sort.Strings(fields)
+
+	return fmt.Sprintf("client: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), strings.Join(fields, "; "))
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// ValidationErrors returns the field errors of a failed validation.
+func ValidationErrors(err error) (map[string]string, bool) {
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusUnprocessableEntity {
+		return e.Fields, true
+	}
+	return nil, false
+}
+
+// decodeError reads a message or the field errors out of the envelope.
+func decodeError(res *http.Response) *Error {
+	e := &Error{
+		StatusCode: res.StatusCode,
+		Message:    http.StatusText(res.StatusCode),
+		RequestID:  res.Header.Get("X-Request-Id"),
+	}
+
+	var env struct {
+		Error          json.RawMessage `json:"error"`
+		CurrentVersion int32           `json:"current_version"`
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil || json.Unmarshal(body, &env) != nil || len(env.Error) == 0 {
+		if text := strings.TrimSpace(string(body)); text != "" && err == nil {
+			e.Message = text
+		}
+		return e
+	}
+
+	e.CurrentVersion = env.CurrentVersion
+
+	if json.Unmarshal(env.Error, &e.Message) != nil {
+		if json.Unmarshal(env.Error, &e.Fields) == nil {
+			e.Message = "validation failed"
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions pages through a listing, zero values keep the server defaults.
+type ListOptions struct {
+	Page     int
+	PageSize int
+	Sort     string
+}
+
+// Iterator walks a listing page by page, check Err once Next returns false.
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, page int) ([]T, Metadata, error)
+
+	page  int
+	items []T
+	index int
+	last  bool
+	err   error
+}
+
+func newIterator[T any](ctx context.Context, page int, fetch func(context.Context, int) ([]T, Metadata, error)) *Iterator[T] {
+	if page < 1 {
+		page = 1
+	}
+	return &Iterator[T]{ctx: ctx, fetch: fetch, page: page, index: -1}
+}
+
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	for it.index >= len(it.items) {
+		if it.last {
+			return false
+		}
+
+		items, metadata, err := it.fetch(it.ctx, it.page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.items, it.index = items, 0
+		it.last = len(items) == 0 || metadata.CurrentPage >= metadata.LastPage
+		it.page++
+	}
+
+	return true
+}
+
+// Value is the item Next moved to.
+func (it *Iterator[T]) Value() T {
+	return it.items[it.index]
+}
+
+// Err reports what stopped the iteration, nil when the listing ran out.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// query adds the paging options to q.
+func (o ListOptions) query(q url.Values, page int) url.Values {
+	if page > 0 {
+		q.Set("page", strconv.Itoa(page))
+	}
+	if o.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	return q
+}
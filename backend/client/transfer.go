@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ImportOptions struct {
+	// DryRun validates the rows without creating anything
+	DryRun    bool
+	BatchSize int
+	// Mapping overrides the csv columns, e.g. "title=Book Title"
+	Mapping string
+}
+
+func (o ImportOptions) values() url.Values {
+	values := url.Values{}
+	if o.DryRun {
+		values.Set("dry_run", "true")
+	}
+	if o.BatchSize > 0 {
+		values.Set("batch_size", strconv.Itoa(o.BatchSize))
+	}
+	if o.Mapping != "" {
+		values.Set("mapping", o.Mapping)
+	}
+	return values
+}
+
+// ImportBooks streams a csv catalogue from r, it is never retried.
+func (c *Client) ImportBooks(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	return c.importFile(ctx, "/v1/import/books", "text/csv", r, opts.values())
+}
+
+// ImportMARC uploads binary MARC 21 records, or MARCXML when xml is set.
+func (c *Client) ImportMARC(ctx context.Context, r io.Reader, xml bool, opts ImportOptions) (*ImportReport, error) {
+	contentType, format := "application/marc", "marc"
+	if xml {
+		contentType, format = "application/marcxml+xml", "marcxml"
+	}
+
+	query := opts.values()
+	query.Set("format", format)
+
+	return c.importFile(ctx, "/v1/import/marc", contentType, r, query)
+}
+
+func (c *Client) importFile(ctx context.Context, path, contentType string, r io.Reader, query url.Values) (*ImportReport, error) {
+	req := &request{
+		method: http.MethodPost,
+		path:   path,
+		query:  query,
+		header: http.Header{"Content-Type": {contentType}},
+		body:   r,
+	}
+
+	var env struct {
+		Report *ImportReport `json:"report"`
+	}
+	if err := c.do(ctx, req, &env); err != nil {
+		return nil, err
+	}
+
+	return env.Report, nil
+}
+
+type ExportQuery struct {
+	// Format is one of csv, json, ndjson, marc or marcxml, ndjson by default
+	Format        string
+	Genre         string
+	Publisher     string
+	YearFrom      int32
+	YearTo        int32
+	ModifiedSince time.Time
+}
+
+// Export streams the catalogue dump, the caller has to close it.
+func (c *Client) Export(ctx context.Context, q ExportQuery) (io.ReadCloser, error) {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"format":    q.Format,
+		"genre":     q.Genre,
+		"publisher": q.Publisher,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if q.YearFrom != 0 {
+		query.Set("year_from", strconv.Itoa(int(q.YearFrom)))
+	}
+	if q.YearTo != 0 {
+		query.Set("year_to", strconv.Itoa(int(q.YearTo)))
+	}
+	if !q.ModifiedSince.IsZero() {
+		query.Set("modified_since", q.ModifiedSince.Format(time.RFC3339))
+	}
+
+	res, err := c.send(ctx, &request{method: http.MethodGet, path: "/v1/export", query: query, header: http.Header{"Accept": {"*/*"}}})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+// MARC returns the record of a book as MARCXML or binary MARC 21.
+func (c *Client) MARC(ctx context.Context, id int64, xml bool) ([]byte, error) {
+	format := "marc"
+	if xml {
+		format = "marcxml"
+	}
+
+	return c.read(ctx, &request{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/v1/books/%d/marc", id),
+		query:  url.Values{"format": {format}},
+		header: http.Header{"Accept": {"*/*"}},
+	})
+}
+
+// Cite renders the citation of a book as bibtex, ris, csljson, apa, mla or chicago.
+func (c *Client) Cite(ctx context.Context, id int64, format string) (string, error) {
+	out, err := c.read(ctx, &request{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/v1/books/%d/cite", id),
+		query:  url.Values{"format": {format}},
+		header: http.Header{"Accept": {"*/*"}},
+	})
+	return string(out), err
+}
+
+// CiteMany renders the citations of several books in the order of ids.
+func (c *Client) CiteMany(ctx context.Context, format string, ids ...int64) (string, error) {
+	list := make([]string, len(ids))
+	for i, id := range ids {
+		list[i] = strconv.FormatInt(id, 10)
+	}
+
+	out, err := c.read(ctx, &request{
+		method: http.MethodGet,
+		path:   "/v1/cite",
+		query:  url.Values{"format": {format}, "ids": {strings.Join(list, ",")}},
+		header: http.Header{"Accept": {"*/*"}},
+	})
+	return string(out), err
+}
+
+// OpenAPI returns the OpenAPI 3 document of the api.
+func (c *Client) OpenAPI(ctx context.Context) ([]byte, error) {
+	return c.read(ctx, &request{method: http.MethodGet, path: "/v1/openapi.json"})
+}
+
+// GraphQLError is one entry of the errors list of a GraphQL response.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GraphQLErrors is returned along with the data that did resolve.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return "client: graphql: " + strings.Join(messages, "; ")
+}
+
+// GraphQL runs a query or mutation and decodes its data into out.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	input := struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables,omitempty"`
+	}{query, variables}
+
+	var res struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/graphql", json: input}, &res); err != nil {
+		return err
+	}
+
+	if out != nil && len(res.Data) > 0 && string(res.Data) != "null" {
+		if err := json.Unmarshal(res.Data, out); err != nil {
+			return fmt.Errorf("client: decoding the graphql data: %w", err)
+		}
+	}
+
+	if len(res.Errors) > 0 {
+		return res.Errors
+	}
+
+	return nil
+}
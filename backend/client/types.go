@@ -0,0 +1,147 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Book struct {
+	ID        int64    `json:"id"`
+	Hash      string   `json:"hash"`
+	Title     string   `json:"title"`
+	Publisher string   `json:"publisher"`
+	Year      int32    `json:"year"`
+	PageCount int32    `json:"page_count"`
+	Genres    []string `json:"genres,omitempty"`
+	ISBN      string   `json:"isbn,omitempty"`
+	Version   int32    `json:"version,omitempty"`
+}
+
+type Author struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Identifier    string `json:"identifier"`
+	BooksAuthored int32  `json:"books_authored"`
+}
+
+// Entry is a book with its authors.
+type Entry struct {
+	Book    Book     `json:"Book"`
+	Authors []Author `json:"authors"`
+}
+
+// AuthorEntry is an author with the books they wrote.
+type AuthorEntry struct {
+	Author Author `json:"authors"`
+	Books  []Book `json:"books"`
+}
+
+type Metadata struct {
+	CurrentPage  int `json:"current_page,omitempty"`
+	PageSize     int `json:"page_size,omitempty"`
+	FirstPage    int `json:"first_page,omitempty"`
+	LastPage     int `json:"last_page,omitempty"`
+	TotalRecords int `json:"total_records,omitempty"`
+}
+
+// NewBook is what CreateBook sends.
+type NewBook struct {
+	Title     string
+	Publisher string
+	Year      int32
+	PageCount int32
+	Genres    []string
+	ISBN      string
+	Authors   []string
+}
+
+// BookUpdate changes the fields that are not nil.
+type BookUpdate struct {
+	Title     *string
+	Publisher *string
+	Year      *int32
+	PageCount *int32
+	Genres    []string
+	ISBN      *string
+	Authors   []string
+	Version   *int32
+}
+
+type TrashedBook struct {
+	ID        int64     `json:"id"`
+	Hash      string    `json:"hash"`
+	Title     string    `json:"title"`
+	Publisher string    `json:"publisher"`
+	Year      int32     `json:"year"`
+	PageCount int32     `json:"page_count"`
+	Genres    []string  `json:"genres"`
+	Version   int32     `json:"version"`
+	Authors   []string  `json:"authors"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type Snapshot struct {
+	Title     string   `json:"title"`
+	Publisher string   `json:"publisher"`
+	Year      int32    `json:"year"`
+	PageCount int32    `json:"page_count"`
+	Genres    []string `json:"genres"`
+	ISBN      string   `json:"isbn,omitempty"`
+	Version   int32    `json:"version"`
+	Authors   []string `json:"authors"`
+}
+
+type Revision struct {
+	BookID    int64     `json:"book_id"`
+	Version   int32     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Actor     string    `json:"actor"`
+	Snapshot  Snapshot  `json:"snapshot"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type RevisionDiff struct {
+	From    int32         `json:"from_version"`
+	To      int32         `json:"to_version"`
+	Fields  []FieldChange `json:"fields"`
+	Authors struct {
+		Added   []string `json:"added"`
+		Removed []string `json:"removed"`
+		Kept    []string `json:"kept"`
+	} `json:"authors"`
+}
+
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id"`
+	Entity    string          `json:"entity"`
+	EntityID  int64           `json:"entity_id"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+type ImportResult struct {
+	Line   int               `json:"line"`
+	Status string            `json:"status"`
+	ID     int64             `json:"id,omitempty"`
+	Title  string            `json:"title"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun     bool            `json:"dry_run"`
+	Total      int             `json:"total"`
+	Created    int             `json:"created"`
+	Valid      int             `json:"valid"`
+	Duplicates int             `json:"duplicates"`
+	Rejected   int             `json:"rejected"`
+	Rows       []*ImportResult `json:"rows"`
+}
@@ -0,0 +1,17 @@
+// Command libraryctl runs catalogue operations against the database or a server.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/3WDeveloper-GM/library_app/backend/cli/commands"
+	"github.com/3WDeveloper-GM/library_app/backend/config"
+)
+
+func main() {
+	if err := commands.Ctl(config.NewAppObject(), os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
 
 	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/memory"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/sqlite"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/users"
 	"github.com/3WDeveloper-GM/library_app/backend/logger"
 	"github.com/go-chi/chi/v5"
 
@@ -20,31 +25,130 @@ import (
 type App struct {
 	ConfigFlags struct {
 		Port        int    `json:"port"`
+		GRPCPort    int    `json:"grpc_port"`
 		Environment string `json:"env"`
+		AutoMigrate bool   `json:"auto_migrate"`
+		Storage     string `json:"storage"`
+		LogLevel    string `json:"log_level"`
+		ConfigFile  string `json:"config_file"`
+		PrintConfig bool   `json:"-"`
+		Server      struct {
+			ReadTimeout     time.Duration `json:"read_timeout"`
+			WriteTimeout    time.Duration `json:"write_timeout"`
+			IdleTimeout     time.Duration `json:"idle_timeout"`
+			ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+			MaxBodyBytes    int64         `json:"max_body_bytes"`
+		} `json:"server"`
+		CORS struct {
+			AllowedOrigins []string `json:"allowed_origins"`
+		} `json:"cors"`
+		Trash struct {
+			Retention     time.Duration `json:"retention"`
+			PurgeInterval time.Duration `json:"purge_interval"`
+			PurgeTimeout  time.Duration `json:"purge_timeout"`
+		} `json:"trash"`
+		Transactions struct {
+			MaxAttempts int           `json:"max_attempts"`
+			MinBackoff  time.Duration `json:"min_backoff"`
+			MaxBackoff  time.Duration `json:"max_backoff"`
+		} `json:"transactions"`
+		Timeouts timeouts `json:"timeout"`
+	}
+	Admin struct {
+		Token string
+	}
+	OAI struct {
+		RepositoryName       string
+		RepositoryIdentifier string
+		AdminEmail           string
+		BaseURL              string
+	}
+	Catalogue struct {
+		Name string
 	}
 	Database struct {
-		DSN string
-		DB  *sql.DB
+		DSN          string
+		MaxOpenConns int
+		MaxIdleConns int
+		MaxIdleTime  time.Duration
+		DB           *sql.DB
 	}
 	Models struct {
-		Create books.CreateEntryModel
-		Read   books.ReadEntryModel
-		Update books.UpdateEntryModel
-		Delete books.DeleteEntryModel
+		Create    books.CreateRepository
+		Read      books.ReadRepository
+		Update    books.UpdateRepository
+		Delete    books.DeleteRepository
+		Authors   books.AuthorRepository
+		Links     books.LinkRepository
+		Audit     audit.Repository
+		Revisions books.RevisionRepository
+		Users     users.Repository
 	}
+	// Tx runs the writes, retrying the transactions CockroachDB aborted
+	Tx *books.TxRunner
 	logger.Logger
+
+	settings settings
+	live     live
 }
 
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+)
+
 func NewAppObject() *App {
 	return &App{}
 }
 
+// SetConfigFlags loads the server settings, see Load.
 func (app *App) SetConfigFlags() {
+	app.BindFlags(flag.CommandLine)
+
+	if err := app.Load(flag.CommandLine, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
 
-	flag.IntVar(&app.ConfigFlags.Port, "port", 8080, "Backend server port")
-	flag.StringVar(&app.ConfigFlags.Environment, "env", "development", "environment (development|production|staging)")
-	flag.StringVar(&app.Database.DSN, "dsn-db", os.Getenv("COCKROACHDB_DSN"), "CockroachDB database dsn")
-	flag.Parse()
+// BindFlags registers the flags shared by the server and the cli commands.
+func (app *App) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&app.ConfigFlags.ConfigFile, "config", "", "yaml or toml file with the settings (or LIBRARY_CONFIG)")
+	fs.BoolVar(&app.ConfigFlags.PrintConfig, "print-config", false, "print the effective settings, secrets redacted, and exit")
+	fs.IntVar(&app.ConfigFlags.Port, "port", 8080, "Backend server port")
+	fs.IntVar(&app.ConfigFlags.GRPCPort, "grpc-port", 9090, "gRPC server port (0 disables the gRPC api)")
+	fs.StringVar(&app.ConfigFlags.Environment, "env", "development", "environment (development|production|staging)")
+	fs.StringVar(&app.ConfigFlags.LogLevel, "log-level", "info", "minimum level logged (trace|debug|info|warn|error)")
+	fs.DurationVar(&app.ConfigFlags.Server.ReadTimeout, "read-timeout", 10*time.Second, "maximum duration for reading a request")
+	fs.DurationVar(&app.ConfigFlags.Server.WriteTimeout, "write-timeout", 30*time.Second, "maximum duration for writing a response")
+	fs.DurationVar(&app.ConfigFlags.Server.IdleTimeout, "idle-timeout", time.Minute, "how long keep-alive connections are kept idle")
+	fs.Int64Var(&app.ConfigFlags.Server.MaxBodyBytes, "max-body-bytes", 1<<20, "largest json request body accepted")
+	fs.DurationVar(&app.ConfigFlags.Server.ShutdownTimeout, "shutdown-timeout", 5*time.Second, "how long in-flight requests get on shutdown")
+	app.ConfigFlags.CORS.AllowedOrigins = []string{"https://*", "http://*"}
+	fs.Var((*commaList)(&app.ConfigFlags.CORS.AllowedOrigins), "cors-origins", "comma separated origins allowed by CORS, * matches any part")
+	fs.StringVar(&app.ConfigFlags.Storage, "storage", StorageDatabase, "where the catalogue is kept (database|memory), memory loses everything on exit")
+	fs.StringVar(&app.Database.DSN, "dsn-db", os.Getenv("COCKROACHDB_DSN"), "CockroachDB database dsn, or sqlite:path for a SQLite file")
+	fs.IntVar(&app.Database.MaxOpenConns, "db-max-open-conns", 25, "maximum open database connections (0 is unlimited)")
+	fs.IntVar(&app.Database.MaxIdleConns, "db-max-idle-conns", 25, "maximum idle database connections")
+	fs.DurationVar(&app.Database.MaxIdleTime, "db-max-idle-time", 15*time.Minute, "how long a database connection may stay idle (0 keeps them)")
+	fs.BoolVar(&app.ConfigFlags.AutoMigrate, "auto-migrate", false, "apply the pending database migrations when connecting")
+	fs.IntVar(&app.ConfigFlags.Transactions.MaxAttempts, "tx-max-attempts", 5, "how many times a write is tried when the database asks to retry it")
+	fs.DurationVar(&app.ConfigFlags.Transactions.MinBackoff, "tx-min-backoff", 10*time.Millisecond, "wait before the first retry of a write, doubled for every other one")
+	fs.DurationVar(&app.ConfigFlags.Transactions.MaxBackoff, "tx-max-backoff", 500*time.Millisecond, "longest wait between two retries of a write")
+	fs.DurationVar(&app.ConfigFlags.Timeouts.Read, "timeout-read", 3*time.Second, "how long the read endpoints may take (0 for no limit)")
+	fs.DurationVar(&app.ConfigFlags.Timeouts.Write, "timeout-write", 5*time.Second, "how long the endpoints changing the catalogue may take")
+	fs.DurationVar(&app.ConfigFlags.Timeouts.Search, "timeout-search", 10*time.Second, "how long SRU, OAI-PMH and GraphQL requests may take")
+	fs.DurationVar(&app.ConfigFlags.Timeouts.Import, "timeout-import", 4*time.Minute, "how long a bulk import may take")
+	fs.DurationVar(&app.ConfigFlags.Timeouts.Export, "timeout-export", time.Hour, "how long a catalogue export may stream")
+	fs.StringVar(&app.Admin.Token, "admin-token", "", "Bearer token for the admin endpoints")
+	fs.DurationVar(&app.ConfigFlags.Trash.Retention, "trash-retention", 30*24*time.Hour, "how long deleted books stay in the trash (0 disables the scheduled purge)")
+	fs.DurationVar(&app.ConfigFlags.Trash.PurgeInterval, "trash-purge-interval", time.Hour, "how often the trash is checked for expired books")
+	fs.DurationVar(&app.ConfigFlags.Trash.PurgeTimeout, "trash-purge-timeout", 30*time.Second, "how long a scheduled trash purge may take")
+	fs.StringVar(&app.OAI.RepositoryName, "oai-repository-name", "Library catalogue", "repository name reported by OAI-PMH Identify")
+	fs.StringVar(&app.OAI.RepositoryIdentifier, "oai-repository-identifier", "library.local", "namespace of the OAI-PMH record identifiers")
+	fs.StringVar(&app.OAI.AdminEmail, "oai-admin-email", "admin@library.local", "contact address reported by OAI-PMH Identify")
+	fs.StringVar(&app.OAI.BaseURL, "oai-base-url", "", "public OAI-PMH base url (derived from the request when empty)")
+	fs.StringVar(&app.Catalogue.Name, "catalogue-name", "Library catalogue", "catalogue name shown by SRU explain and the OPDS feeds")
 }
 
 func (app *App) SetLogger() {
@@ -52,25 +156,85 @@ func (app *App) SetLogger() {
 }
 
 func (app *App) SetModels() {
-	app.Models.Create.DB = app.Database.DB
-	app.Models.Read.DB = app.Database.DB
-	app.Models.Update.DB = app.Database.DB
-	app.Models.Delete.DB = app.Database.DB
+	app.Tx = app.newTxRunner()
+
+	if app.ConfigFlags.Storage == StorageMemory {
+		store := memory.New()
+
+		app.Models.Create = store.Create()
+		app.Models.Read = store.Read()
+		app.Models.Update = store.Update()
+		app.Models.Delete = store.Delete()
+		app.Models.Authors = store.Authors()
+		app.Models.Links = store.Links()
+		app.Models.Audit = store.Audit()
+		app.Models.Revisions = store.Revisions()
+		app.Models.Users = store.Users()
+		return
+	}
+
+	if app.UsesSQLite() {
+		store := sqlite.New(app.Database.DB)
+
+		app.Models.Create = store.Create()
+		app.Models.Read = store.Read()
+		app.Models.Update = store.Update()
+		app.Models.Delete = store.Delete()
+		app.Models.Authors = store.Authors()
+		app.Models.Links = store.Links()
+		app.Models.Audit = store.Audit()
+		app.Models.Revisions = store.Revisions()
+		app.Models.Users = store.Users()
+		return
+	}
+
+	app.Models.Create = &books.CreateEntryModel{DB: app.Database.DB}
+	app.Models.Read = &books.ReadEntryModel{DB: app.Database.DB}
+	app.Models.Update = &books.UpdateEntryModel{DB: app.Database.DB}
+	app.Models.Delete = &books.DeleteEntryModel{DB: app.Database.DB}
+	app.Models.Authors = &books.AuthorModel{DB: app.Database.DB}
+	app.Models.Links = &books.LinkModel{DB: app.Database.DB}
+	app.Models.Audit = &audit.AuditModel{DB: app.Database.DB}
+	app.Models.Revisions = &books.RevisionModel{DB: app.Database.DB}
+	app.Models.Users = &users.UserModel{DB: app.Database.DB}
 }
 
 func (app *App) SetDB() error {
 
+	if app.ConfigFlags.Storage == StorageMemory {
+		app.Log.Warn().Msg("using the in-memory storage, nothing is persisted")
+		return nil
+	}
+
 	db, err := app.OpenDB(app.Database.DSN)
 
 	if err != nil {
-		app.Log.Panic().Err(err).Send()
+		return err
 	}
 
 	app.Database.DB = db
 
+	if app.ConfigFlags.AutoMigrate {
+		if err := app.Migrate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// UsesSQLite reports whether the dsn names a SQLite file.
+func (app *App) UsesSQLite() bool {
+	return sqlite.IsDSN(app.Database.DSN)
+}
+
+// CloseDB closes the database, when there is one.
+func (app *App) CloseDB() {
+	if app.Database.DB != nil {
+		app.Database.DB.Close()
+	}
+}
+
 func (app *App) ReadIDParams(r *http.Request) (int64, error) {
 	id := chi.URLParam(r, "id")
 
@@ -83,12 +247,35 @@ func (app *App) ReadIDParams(r *http.Request) (int64, error) {
 	return n, nil
 }
 
+func (app *App) ReadVersionParams(r *http.Request) (int32, error) {
+	version := chi.URLParam(r, "version")
+
+	n, err := strconv.ParseInt(version, 10, 32)
+
+	if err != nil || n < 1 {
+		return 0, errors.New("invalid version parameter")
+	}
+
+	return int32(n), nil
+}
+
 func (app *App) OpenDB(dsn string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", app.Database.DSN)
+	var db *sql.DB
+	var err error
+
+	if sqlite.IsDSN(dsn) {
+		db, err = sqlite.Open(dsn)
+	} else {
+		db, err = sql.Open("postgres", dsn)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	db.SetMaxOpenConns(app.Database.MaxOpenConns)
+	db.SetMaxIdleConns(app.Database.MaxIdleConns)
+	db.SetConnMaxIdleTime(app.Database.MaxIdleTime)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
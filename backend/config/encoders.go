@@ -0,0 +1,222 @@
+package config
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// LinkedData is implemented by resources with a schema.org description.
+type LinkedData interface {
+	LinkedData(base string) interface{}
+}
+
+// CSVRecords is implemented by resources that flatten to a table with a header.
+type CSVRecords interface {
+	CSVRecords() [][]string
+}
+
+func init() {
+	RegisterEncoder(Encoder{
+		MediaType: "application/json",
+		Encode:    encodeJSON,
+	})
+	RegisterEncoder(Encoder{
+		MediaType: "application/ld+json",
+		Suffix:    "ld",
+		Supports: func(data Envelope) bool {
+			_, ok := findResource[LinkedData](data)
+			return ok
+		},
+		Encode: encodeLinkedData,
+	})
+	RegisterEncoder(Encoder{
+		MediaType:   "application/xml",
+		ContentType: "application/xml; charset=utf-8",
+		Suffix:      "xml",
+		Encode:      encodeXML,
+	})
+	RegisterEncoder(Encoder{
+		MediaType:   "text/csv",
+		ContentType: "text/csv; charset=utf-8",
+		Suffix:      "csv",
+		Supports: func(data Envelope) bool {
+			_, ok := findResource[CSVRecords](data)
+			return ok
+		},
+		Encode: encodeCSV,
+	})
+}
+
+// findResource returns the first envelope value, by key, implementing T.
+func findResource[T any](data Envelope) (T, bool) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if resource, ok := data[key].(T); ok {
+			return resource, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+func writeJSONValue(w io.Writer, v interface{}, indent, escapeHTML bool) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(escapeHTML)
+	if indent {
+		enc.SetIndent(" ", "\t")
+	}
+	return enc.Encode(v)
+}
+
+func encodeJSON(w io.Writer, r *http.Request, data Envelope, indent bool) error {
+	return writeJSONValue(w, data, indent, true)
+}
+
+func encodeLinkedData(w io.Writer, r *http.Request, data Envelope, indent bool) error {
+	resource, _ := findResource[LinkedData](data)
+	return writeJSONValue(w, resource.LinkedData(RequestOrigin(r)), indent, false)
+}
+
+func encodeCSV(w io.Writer, r *http.Request, data Envelope, indent bool) error {
+	resource, _ := findResource[CSVRecords](data)
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(resource.CSVRecords()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// encodeXML goes through the json form of the envelope to keep its field names.
+func encodeXML(w io.Writer, r *http.Request, data Envelope, indent bool) error {
+	js, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(js)))
+	dec.UseNumber()
+
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	if indent {
+		enc.Indent("", "  ")
+	}
+
+	if err := encodeXMLValue(enc, "response", tree); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+func encodeXMLValue(enc *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err := encodeXMLValue(enc, key, v[key]); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		for _, item := range v {
+			if err := encodeXMLValue(enc, "item", item); err != nil {
+				return err
+			}
+		}
+
+	case nil:
+
+	case json.Number:
+		if err := enc.EncodeToken(xml.CharData(v.String())); err != nil {
+			return err
+		}
+
+	case bool:
+		text := "false"
+		if v {
+			text = "true"
+		}
+		if err := enc.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+
+	case string:
+		if err := enc.EncodeToken(xml.CharData(v)); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlName turns a json key into a valid element name.
+func xmlName(key string) string {
+	var b strings.Builder
+
+	for i, r := range key {
+		valid := unicode.IsLetter(r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'))
+		if !valid {
+			if i == 0 && unicode.IsDigit(r) {
+				b.WriteByte('_')
+				b.WriteRune(r)
+				continue
+			}
+			b.WriteByte('_')
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	if b.Len() == 0 || strings.HasPrefix(strings.ToLower(b.String()), "xml") {
+		return "_" + b.String()
+	}
+
+	return b.String()
+}
+
+// RequestOrigin is the scheme and host the client used.
+func RequestOrigin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return scheme + "://" + r.Host
+}
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
 )
 
 func (app *App) ErrResponse(w http.ResponseWriter, r *http.Request, status int, message interface{}) {
@@ -17,7 +22,20 @@ func (app *App) ErrResponse(w http.ResponseWriter, r *http.Request, status int,
 	}
 }
 
+// StatusClientClosedRequest is the nginx status for a client that went away.
+const StatusClientClosedRequest = 499
+
+// ServerErrorResponse answers 500, or 499 and 503 when the request context ended.
 func (app *App) ServerErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	//the drivers do not always wrap the context error
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(r.Context().Err(), context.Canceled):
+		app.ClientClosedResponse(w, r, err)
+		return
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(r.Context().Err(), context.DeadlineExceeded):
+		app.TimeoutResponse(w, r, err)
+		return
+	}
 
 	app.Log.Error().Err(err).Send()
 
@@ -25,6 +43,21 @@ func (app *App) ServerErrorResponse(w http.ResponseWriter, r *http.Request, err
 	app.ErrResponse(w, r, http.StatusInternalServerError, message)
 }
 
+func (app *App) TimeoutResponse(w http.ResponseWriter, r *http.Request, err error) {
+	app.Log.Warn().Err(err).Str("path", r.URL.Path).Msg("request timed out")
+
+	message := "the request took too long to process, please try again later"
+	app.ErrResponse(w, r, http.StatusServiceUnavailable, message)
+}
+
+// ClientClosedResponse only records the status, nobody reads the body.
+func (app *App) ClientClosedResponse(w http.ResponseWriter, r *http.Request, err error) {
+	app.Log.Info().Err(err).Str("path", r.URL.Path).Msg("client closed the request")
+
+	message := "the client closed the request"
+	app.ErrResponse(w, r, StatusClientClosedRequest, message)
+}
+
 func (app *App) NotFoundResponse(w http.ResponseWriter, r *http.Request) {
 	message := "the requested resource could not be found"
 	app.ErrResponse(w, r, http.StatusNotFound, message)
@@ -39,11 +72,67 @@ func (app *App) BadRequestResponse(w http.ResponseWriter, r *http.Request, err e
 	app.ErrResponse(w, r, http.StatusBadRequest, err.Error())
 }
 
+func (app *App) ContentTooLargeResponse(w http.ResponseWriter, r *http.Request, limit int64) {
+	message := fmt.Sprintf("the request body must not be larger than %d bytes", limit)
+	app.ErrResponse(w, r, http.StatusRequestEntityTooLarge, message)
+}
+
 func (app *App) FailedValidationResponse(w http.ResponseWriter, r *http.Request, errors map[string]string) {
 	app.ErrResponse(w, r, http.StatusUnprocessableEntity, errors)
 }
 
+func (app *App) UnauthorizedResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	message := "invalid or missing authentication token"
+	app.ErrResponse(w, r, http.StatusUnauthorized, message)
+}
+
+func (app *App) ForbiddenResponse(w http.ResponseWriter, r *http.Request) {
+	message := "your credentials do not allow access to this resource"
+	app.ErrResponse(w, r, http.StatusForbidden, message)
+}
+
 func (app *App) EditConflictResponse(w http.ResponseWriter, r *http.Request) {
 	message := "unable to update the record due to an edit conflict, please try again"
 	app.ErrResponse(w, r, http.StatusConflict, message)
 }
+
+func (app *App) DuplicateBookResponse(w http.ResponseWriter, r *http.Request, dup *books.DuplicateError) {
+	env := Envelope{
+		"error":   "a book with the same title already exists",
+		"book_id": dup.ID,
+		"trashed": dup.Trashed,
+	}
+	if dup.Trashed {
+		env["error"] = "a book with the same title is in the trash, restore it instead"
+	}
+
+	err := app.WriteJson(w, r, http.StatusConflict, env, nil)
+	if err != nil {
+		app.Log.Error().Err(err).Send()
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (app *App) PreconditionRequiredResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the version of the book must be sent, in the body or an If-Match header"
+	app.ErrResponse(w, r, http.StatusPreconditionRequired, message)
+}
+
+func (app *App) VersionConflictResponse(w http.ResponseWriter, r *http.Request, current int32) {
+	env := Envelope{
+		"error":           "unable to update the record, it was modified since the version you provided",
+		"current_version": current,
+	}
+
+	err := app.WriteJson(w, r, http.StatusConflict, env, nil)
+	if err != nil {
+		app.Log.Error().Err(err).Send()
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (app *App) NotAcceptableResponse(w http.ResponseWriter, r *http.Request, available []string) {
+	message := fmt.Sprintf("the resource can only be represented as %s", strings.Join(available, ", "))
+	app.ErrResponse(w, r, http.StatusNotAcceptable, message)
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"errors"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
 	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
 )
 
@@ -46,15 +49,25 @@ func (app *App) ReadInt(qs url.Values, key string, defaultValue int, v *validato
 	return i
 }
 
-func (app *App) ToUpper(str string) string {
-	new_str := []byte(str)
-	for i := 0; i < len(str); i++ {
-		if str[i] >= 'a' && str[i] <= 'z' {
-			chr := uint8(rune(str[i]) - 'a' + 'A')
-			new_str[i] = chr
-		}
+func (app *App) ReadBool(qs url.Values, key string, defaultValue bool, v *validator.Validator) bool {
+
+	s := qs.Get(key)
+
+	if s == "" {
+		return defaultValue
 	}
-	return string(new_str)
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		v.AddError(key, "must be a boolean value")
+		return defaultValue
+	}
+
+	return b
+}
+
+func (app *App) ToUpper(str string) string {
+	return internal.ToUpper(str)
 }
 
 func (app *App) FindDiffOneInTwo(list1, list2 []string) []string {
@@ -72,3 +85,26 @@ func (app *App) FindDiffOneInTwo(list1, list2 []string) []string {
 	}
 	return difference
 }
+
+func (app *App) ReadIfMatchVersion(r *http.Request) (*int32, error) {
+	tag := strings.TrimSpace(r.Header.Get("If-Match"))
+
+	if tag == "" || tag == "*" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(tag, "W/") {
+		return nil, errors.New("If-Match header must contain a strong entity tag")
+	}
+
+	//"<version>.<modified>", only the version counts
+	tag, _, _ = strings.Cut(strings.Trim(tag, `"`), ".")
+
+	n, err := strconv.ParseInt(tag, 10, 32)
+	if err != nil || n < 1 {
+		return nil, errors.New("invalid If-Match header")
+	}
+
+	version := int32(n)
+	return &version, nil
+}
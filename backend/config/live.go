@@ -0,0 +1,142 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// reloadableSettings can change on a SIGHUP.
+var reloadableSettings = map[string]bool{
+	"log-level":       true,
+	"cors-origins":    true,
+	"trash-retention": true,
+	"timeout-read":    true,
+	"timeout-write":   true,
+	"timeout-search":  true,
+	"timeout-import":  true,
+	"timeout-export":  true,
+}
+
+// live holds the reloadable settings.
+type live struct {
+	mu             sync.RWMutex
+	loaded         bool
+	corsOrigins    []string
+	trashRetention time.Duration
+	timeouts       timeouts
+}
+
+// timeouts bound the requests of every route group.
+type timeouts struct {
+	Read   time.Duration `json:"read"`
+	Write  time.Duration `json:"write"`
+	Search time.Duration `json:"search"`
+	Import time.Duration `json:"import"`
+	Export time.Duration `json:"export"`
+}
+
+func (app *App) applyLive() {
+	app.live.mu.Lock()
+	defer app.live.mu.Unlock()
+
+	app.live.loaded = true
+	app.live.corsOrigins = app.ConfigFlags.CORS.AllowedOrigins
+	app.live.trashRetention = app.ConfigFlags.Trash.Retention
+	app.live.timeouts = app.ConfigFlags.Timeouts
+
+	if level, err := zerolog.ParseLevel(app.ConfigFlags.LogLevel); err == nil {
+		zerolog.SetGlobalLevel(level)
+	}
+}
+
+// AllowOrigin reports whether CORS requests from origin are allowed.
+func (app *App) AllowOrigin(r *http.Request, origin string) bool {
+	app.live.mu.RLock()
+	origins := app.live.corsOrigins
+	if !app.live.loaded {
+		origins = app.ConfigFlags.CORS.AllowedOrigins
+	}
+	app.live.mu.RUnlock()
+
+	for _, allowed := range origins {
+		if originMatches(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func originMatches(allowed, origin string) bool {
+	prefix, suffix, wildcard := strings.Cut(allowed, "*")
+	if !wildcard {
+		return strings.EqualFold(allowed, origin)
+	}
+
+	origin = strings.ToLower(origin)
+
+	return len(origin) >= len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, strings.ToLower(prefix)) &&
+		strings.HasSuffix(origin, strings.ToLower(suffix))
+}
+
+// TrashRetention is how long deleted books stay in the trash.
+func (app *App) TrashRetention() time.Duration {
+	app.live.mu.RLock()
+	defer app.live.mu.RUnlock()
+
+	if !app.live.loaded {
+		return app.ConfigFlags.Trash.Retention
+	}
+
+	return app.live.trashRetention
+}
+
+// Reload applies the reloadable settings, the other changes wait for a restart.
+func (app *App) Reload() error {
+	fresh := NewAppObject()
+
+	fs := flag.NewFlagSet("reload", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fresh.BindFlags(fs)
+
+	if err := fresh.Load(fs, app.settings.args); err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(fresh.settings.values) {
+		value := fresh.settings.values[name]
+		if value == app.settings.values[name] {
+			continue
+		}
+
+		if !reloadableSettings[name] {
+			app.Log.Warn().
+				Str("setting", name).
+				Msg("setting changed, it is applied on the next restart")
+			continue
+		}
+
+		app.settings.values[name] = value
+		app.settings.sources[name] = fresh.settings.sources[name]
+
+		app.Log.Info().
+			Str("setting", name).
+			Str("value", redact(name, value)).
+			Msg("setting reloaded")
+	}
+
+	app.live.mu.Lock()
+	app.live.corsOrigins = fresh.ConfigFlags.CORS.AllowedOrigins
+	app.live.trashRetention = fresh.ConfigFlags.Trash.Retention
+	app.live.timeouts = fresh.ConfigFlags.Timeouts
+	app.live.mu.Unlock()
+
+	return nil
+}
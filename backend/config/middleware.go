@@ -1,10 +1,110 @@
 package config
 
 import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
 	"net/http"
-	"net/http/httptest"
+	"strings"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/users"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
+func bearerToken(r *http.Request) (string, bool) {
+	return strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+func (app *App) isAdmin(r *http.Request) bool {
+	if app.Admin.Token == "" {
+		return false
+	}
+
+	token, ok := bearerToken(r)
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(app.Admin.Token)) == 1
+}
+
+// AuditMeta tags the request with its actor and request id, after middleware.RequestID.
+func (app *App) AuditMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var maxActorBytes = 100
+
+		meta := audit.Meta{
+			Actor:     "anonymous",
+			RequestID: middleware.GetReqID(r.Context()),
+		}
+
+		w.Header().Set(middleware.RequestIDHeader, meta.RequestID)
+
+		//X-Actor is not authenticated
+		if actor := strings.TrimSpace(r.Header.Get("X-Actor")); actor != "" && len(actor) <= maxActorBytes {
+			meta.Actor = users.UnverifiedPrefix + actor
+		}
+
+		token, hasToken := bearerToken(r)
+
+		switch {
+		case app.isAdmin(r):
+			meta.Actor = "admin"
+		case hasToken && app.Models.Users != nil:
+			user, err := app.Models.Users.ByTokenHash(r.Context(), users.HashToken(token))
+			switch {
+			case errors.Is(err, users.ErrNotFound):
+				app.UnauthorizedResponse(w, r)
+				return
+			case err != nil:
+				app.ServerErrorResponse(w, r, err)
+				return
+			}
+			meta.Actor = user.Name
+		}
+
+		next.ServeHTTP(w, r.WithContext(audit.NewContext(r.Context(), meta)))
+	})
+}
+
+func (app *App) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if app.Admin.Token == "" {
+			app.ForbiddenResponse(w, r)
+			return
+		}
+
+		if !app.isAdmin(r) {
+			app.UnauthorizedResponse(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder keeps the status code, the body is not buffered.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the flusher and deadlines.
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
 func (app *App) VisitedRouteLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var message = "got a request with the following:"
@@ -18,24 +118,27 @@ func (app *App) VisitedRouteLogger(next http.Handler) http.Handler {
 		}).
 			Msg(message)
 
-		c := httptest.NewRecorder()
+		c := &statusRecorder{ResponseWriter: w}
 		next.ServeHTTP(c, r)
 
-		for k, v := range c.Result().Header {
-			w.Header()[k] = v
+		if c.status == 0 {
+			c.status = http.StatusOK
+		}
+		text := http.StatusText(c.status)
+		if c.status == StatusClientClosedRequest {
+			text = "Client Closed Request"
 		}
-		w.WriteHeader(c.Code)
-		c.Body.WriteTo(w)
+		status := fmt.Sprintf("%d %s", c.status, text)
 
 		message = "sent the following response:"
 
-		if res := c.Result().StatusCode; res <= 300 {
+		if c.status <= 300 {
 			app.Log.Info().Interface("response data", struct {
 				Status  string      `json:"status"`
 				Headers interface{} `json:"headers"`
 			}{
-				Status:  c.Result().Status,
-				Headers: c.Result().Header,
+				Status:  status,
+				Headers: w.Header(),
 			}).
 				Msg(message)
 		} else {
@@ -43,8 +146,8 @@ func (app *App) VisitedRouteLogger(next http.Handler) http.Handler {
 				StatusCode string      `json:"status"`
 				Headers    interface{} `json:"headers"`
 			}{
-				StatusCode: c.Result().Status,
-				Headers:    c.Result().Header,
+				StatusCode: status,
+				Headers:    w.Header(),
 			}).
 				Msg(message)
 		}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/migrate"
+	"github.com/3WDeveloper-GM/library_app/migrations"
+)
+
+// Migrator works on the embedded migrations, logging each one it applies.
+func (app *App) Migrator() (*migrate.Migrator, error) {
+	if app.Database.DB == nil {
+		return nil, errors.New("migrations only apply to the database storage")
+	}
+
+	if app.UsesSQLite() {
+		m, err := migrate.New(app.Database.DB, migrations.SQLite)
+		if err != nil {
+			return nil, err
+		}
+		m.Dialect = migrate.SQLite
+		return app.logMigrations(m), nil
+	}
+
+	m, err := migrate.New(app.Database.DB, migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.logMigrations(m), nil
+}
+
+func (app *App) logMigrations(m *migrate.Migrator) *migrate.Migrator {
+
+	m.OnApply = func(migration migrate.Migration, direction string, took time.Duration) {
+		app.Log.Info().
+			Int64("version", migration.Version).
+			Str("name", migration.Name).
+			Str("direction", direction).
+			Dur("took", took).
+			Msg("applied migration")
+	}
+
+	return m
+}
+
+// Migrate brings the database up to the latest migration.
+func (app *App) Migrate() error {
+	m, err := app.Migrator()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		return err
+	}
+
+	app.Log.Info().Int("applied", len(applied)).Msg("database schema is up to date")
+
+	return nil
+}
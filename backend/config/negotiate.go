@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Encoder renders an envelope as one media type, a nil Supports takes anything.
+type Encoder struct {
+	MediaType string
+	// ContentType defaults to MediaType
+	ContentType string
+	// Suffix tells the representations apart in the ETag, empty for json
+	Suffix   string
+	Supports func(data Envelope) bool
+	Encode   func(w io.Writer, r *http.Request, data Envelope, indent bool) error
+}
+
+// in registration order, json first
+var encoders []Encoder
+
+func RegisterEncoder(e Encoder) {
+	for i, registered := range encoders {
+		if registered.MediaType == e.MediaType {
+			encoders[i] = e
+			return
+		}
+	}
+	encoders = append(encoders, e)
+}
+
+// MediaTypes lists the registered types able to represent data.
+func MediaTypes(data Envelope) []string {
+	types := []string{}
+	for _, e := range encoders {
+		if e.Supports == nil || e.Supports(data) {
+			types = append(types, e.MediaType)
+		}
+	}
+	return types
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+	// exact types win over type/* which win over */*
+	specificity int
+}
+
+func parseAccept(header string) []acceptRange {
+	ranges := []acceptRange{}
+
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+
+		specificity := 2
+		switch {
+		case mediaType == "*/*":
+			specificity = 0
+		case strings.HasSuffix(mediaType, "/*"):
+			specificity = 1
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q, specificity: specificity})
+	}
+
+	return ranges
+}
+
+func (a acceptRange) matches(mediaType string) bool {
+	switch a.specificity {
+	case 0:
+		return true
+	case 1:
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(a.mediaType, "*"))
+	}
+	return a.mediaType == mediaType
+}
+
+// quality is the q value the most specific matching range gives mediaType.
+func quality(ranges []acceptRange, mediaType string) float64 {
+	best, q := -1, 0.0
+	for _, a := range ranges {
+		if a.matches(mediaType) && a.specificity > best {
+			best, q = a.specificity, a.q
+		}
+	}
+	return q
+}
+
+// Negotiate picks the encoder for the Accept header.
+func (app *App) Negotiate(w http.ResponseWriter, r *http.Request, data Envelope) (Encoder, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	header := r.Header.Get("Accept")
+	if header == "" {
+		header = "*/*"
+	}
+	ranges := parseAccept(header)
+
+	type candidate struct {
+		encoder Encoder
+		q       float64
+	}
+
+	candidates := []candidate{}
+
+	for _, e := range encoders {
+		if e.Supports != nil && !e.Supports(data) {
+			continue
+		}
+		if q := quality(ranges, e.MediaType); q > 0 {
+			candidates = append(candidates, candidate{encoder: e, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Encoder{}, false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	return candidates[0].encoder, true
+}
+
+// Tag makes the ETag specific to the representation.
+func (e Encoder) Tag(etag string) string {
+	if e.Suffix == "" || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + "+" + e.Suffix + `"`
+}
+
+// WriteNegotiated buffers the body so an encoding error can become a 500.
+func (app *App) WriteNegotiated(w http.ResponseWriter, r *http.Request, e Encoder, status int, data Envelope, headers http.Header) error {
+	var body bytes.Buffer
+	if err := e.Encode(&body, r, data, app.ConfigFlags.Environment == "development"); err != nil {
+		return err
+	}
+
+	for key, value := range headers {
+		w.Header()[key] = value
+	}
+
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = e.MediaType
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(body.Bytes())
+
+	return nil
+}
+
+// WriteResponse negotiates and writes, or answers 406.
+func (app *App) WriteResponse(w http.ResponseWriter, r *http.Request, status int, data Envelope, headers http.Header) error {
+	e, ok := app.Negotiate(w, r, data)
+	if !ok {
+		app.NotAcceptableResponse(w, r, MediaTypes(data))
+		return nil
+	}
+	return app.WriteNegotiated(w, r, e, status, data, headers)
+}
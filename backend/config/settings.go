@@ -0,0 +1,239 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+/* Settings come in layers: a flag on the command line wins over a
+LIBRARY_* environment variable, which wins over the config file, which
+wins over the defaults given in BindFlags. */
+
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// secretSettings are redacted whenever the settings are printed.
+var secretSettings = map[string]bool{
+	"admin-token": true,
+	"dsn-db":      true,
+}
+
+type settings struct {
+	args    []string
+	values  map[string]string
+	sources map[string]string
+}
+
+// settingNames are the flags registered by BindFlags.
+func settingNames() map[string]bool {
+	fs := flag.NewFlagSet("settings", flag.ContinueOnError)
+	NewAppObject().BindFlags(fs)
+
+	names := map[string]bool{}
+	fs.VisitAll(func(f *flag.Flag) {
+		names[f.Name] = true
+	})
+
+	return names
+}
+
+// EnvName is LIBRARY_DSN_DB for -dsn-db.
+func EnvName(setting string) string {
+	return "LIBRARY_" + strings.ToUpper(strings.ReplaceAll(setting, "-", "_"))
+}
+
+// Load parses args into fs, then the environment, then the config file.
+func (app *App) Load(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	names := settingNames()
+	sources := map[string]string{}
+
+	fs.Visit(func(f *flag.Flag) {
+		if names[f.Name] {
+			sources[f.Name] = SourceFlag
+		}
+	})
+
+	//-config over LIBRARY_CONFIG
+	if path, ok := os.LookupEnv(EnvName("config")); ok && sources["config"] == "" {
+		if err := fs.Set("config", path); err != nil {
+			return err
+		}
+		sources["config"] = SourceEnv
+	}
+
+	if path := app.ConfigFlags.ConfigFile; path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range sortedKeys(values) {
+			if !names[name] || name == "config" {
+				return fmt.Errorf("config: %s: unknown setting %q", path, name)
+			}
+			if sources[name] != "" {
+				continue
+			}
+			if err := fs.Set(name, values[name]); err != nil {
+				return fmt.Errorf("config: %s: %s: %w", path, name, err)
+			}
+			sources[name] = SourceFile
+		}
+	}
+
+	for _, name := range sortedKeys(names) {
+		value, ok := os.LookupEnv(EnvName(name))
+		if !ok || sources[name] == SourceFlag || name == "config" {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("config: %s: %w", EnvName(name), err)
+		}
+		sources[name] = SourceEnv
+	}
+
+	if err := app.ValidateConfig(); err != nil {
+		return err
+	}
+
+	values := map[string]string{}
+	for name := range names {
+		if sources[name] == "" {
+			sources[name] = SourceDefault
+		}
+		values[name] = fs.Lookup(name).Value.String()
+	}
+
+	app.settings = settings{args: args, values: values, sources: sources}
+	app.applyLive()
+
+	return nil
+}
+
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	raw := map[string]interface{}{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("config: %s: expected a .yaml, .yml or .toml file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flattenSettings("", raw, values)
+
+	return values, nil
+}
+
+// flattenSettings turns trash: {retention:} into -trash-retention.
+func flattenSettings(prefix string, raw map[string]interface{}, values map[string]string) {
+	for key, value := range raw {
+		name := strings.ReplaceAll(strings.ToLower(key), "_", "-")
+		if prefix != "" {
+			name = prefix + "-" + name
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			flattenSettings(name, value, values)
+		case []interface{}:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			values[name] = strings.Join(items, ",")
+		case nil:
+			values[name] = ""
+		default:
+			values[name] = fmt.Sprint(value)
+		}
+	}
+}
+
+// WriteConfig prints the effective settings and where each one came from.
+func (app *App) WriteConfig(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	for _, name := range sortedKeys(app.settings.values) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, redact(name, app.settings.values[name]), app.settings.sources[name])
+	}
+
+	return tw.Flush()
+}
+
+func redact(name, value string) string {
+	if !secretSettings[name] || value == "" {
+		return value
+	}
+
+	//a url dsn keeps everything but the password
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Redacted()
+	}
+
+	return "xxxxx"
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// commaList is a flag holding a comma separated list.
+type commaList []string
+
+func (l *commaList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *commaList) Set(value string) error {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return errors.New("expected at least one value")
+	}
+
+	*l = items
+
+	return nil
+}
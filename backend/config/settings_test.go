@@ -0,0 +1,235 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func load(t *testing.T, args ...string) (*App, error) {
+	t.Helper()
+
+	app := NewAppObject()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	app.BindFlags(fs)
+
+	return app, app.Load(fs, args)
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, "library.yaml", `
+port: 7000
+grpc_port: 7001
+log-level: warn
+trash:
+  retention: 48h
+cors:
+  origins:
+    - https://library.example
+    - https://*.library.example
+`)
+
+	t.Setenv("LIBRARY_GRPC_PORT", "7002")
+	t.Setenv("LIBRARY_LOG_LEVEL", "debug")
+
+	app, err := load(t, "-config", path, "-log-level", "error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zerolog.SetGlobalLevel(zerolog.TraceLevel)
+
+	if app.ConfigFlags.Port != 7000 {
+		t.Errorf("port = %d, want the file value 7000", app.ConfigFlags.Port)
+	}
+	if app.ConfigFlags.GRPCPort != 7002 {
+		t.Errorf("grpc-port = %d, want the environment value 7002", app.ConfigFlags.GRPCPort)
+	}
+	if app.ConfigFlags.LogLevel != "error" {
+		t.Errorf("log-level = %q, want the flag value error", app.ConfigFlags.LogLevel)
+	}
+	if app.ConfigFlags.Trash.Retention != 48*time.Hour {
+		t.Errorf("trash-retention = %s, want 48h", app.ConfigFlags.Trash.Retention)
+	}
+	if app.ConfigFlags.Server.ReadTimeout != 10*time.Second {
+		t.Errorf("read-timeout = %s, want the default 10s", app.ConfigFlags.Server.ReadTimeout)
+	}
+
+	sources := map[string]string{
+		"port":         SourceFile,
+		"grpc-port":    SourceEnv,
+		"log-level":    SourceFlag,
+		"read-timeout": SourceDefault,
+	}
+	for name, want := range sources {
+		if got := app.settings.sources[name]; got != want {
+			t.Errorf("source of %s = %q, want %q", name, got, want)
+		}
+	}
+
+	if !app.AllowOrigin(nil, "https://branch.library.example") || app.AllowOrigin(nil, "https://example.com") {
+		t.Errorf("cors origins %v not applied", app.ConfigFlags.CORS.AllowedOrigins)
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	fromEnv := writeFile(t, "env.yaml", "port: 7200\n")
+	fromFlag := writeFile(t, "flag.yaml", "port: 7300\n")
+
+	t.Setenv("LIBRARY_CONFIG", fromEnv)
+
+	app, err := load(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.ConfigFlags.Port != 7200 || app.settings.sources["config"] != SourceEnv {
+		t.Errorf("port = %d from %s, want 7200 from the LIBRARY_CONFIG file", app.ConfigFlags.Port, app.settings.sources["config"])
+	}
+
+	app, err = load(t, "-config", fromFlag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.ConfigFlags.Port != 7300 || app.ConfigFlags.ConfigFile != fromFlag || app.settings.sources["config"] != SourceFlag {
+		t.Errorf("port = %d from %s, want 7300 from the -config file", app.ConfigFlags.Port, app.ConfigFlags.ConfigFile)
+	}
+
+	t.Setenv("LIBRARY_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := load(t, "-config", fromFlag); err != nil {
+		t.Errorf("a missing LIBRARY_CONFIG file is read although -config names another: %v", err)
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	path := writeFile(t, "library.toml", `
+port = 7100
+env = "staging"
+
+[db]
+max_open_conns = 10
+max_idle_conns = 5
+`)
+
+	app, err := load(t, "-config", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if app.ConfigFlags.Port != 7100 || app.ConfigFlags.Environment != "staging" {
+		t.Errorf("port, env = %d, %q", app.ConfigFlags.Port, app.ConfigFlags.Environment)
+	}
+	if app.Database.MaxOpenConns != 10 || app.Database.MaxIdleConns != 5 {
+		t.Errorf("db pool = %d/%d, want 10/5", app.Database.MaxOpenConns, app.Database.MaxIdleConns)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		args []string
+		want string
+	}{
+		{name: "unknown setting", file: "colour: blue\n", want: `unknown setting "colour"`},
+		{name: "bad value", file: "port: many\n", want: "port"},
+		{name: "invalid port", args: []string{"-port", "70000"}, want: "port: must be between 1 and 65535"},
+		{name: "invalid env", args: []string{"-env", "qa"}, want: "env: must be development"},
+		{name: "invalid level", args: []string{"-log-level", "loud"}, want: "log-level"},
+		{name: "idle over open", args: []string{"-db-max-open-conns", "2", "-db-max-idle-conns", "5"}, want: "db-max-idle-conns"},
+		{name: "bad origin", args: []string{"-cors-origins", "library.example"}, want: "cors-origins"},
+		{name: "negative timeout", file: "timeout:\n  search: -1s\n", want: "timeout-search: must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "library.yml", tt.file)}, args...)
+			}
+
+			_, err := load(t, args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteConfigRedactsSecrets(t *testing.T) {
+	app, err := load(t,
+		"-dsn-db", "postgresql://library:hunter2@db:26257/library",
+		"-admin-token", "s3cret",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := app.WriteConfig(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(out.String(), "hunter2") || strings.Contains(out.String(), "s3cret") {
+		t.Fatalf("secrets printed:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "postgresql://library:xxxxx@db:26257/library") {
+		t.Errorf("dsn not redacted as expected:\n%s", out.String())
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := writeFile(t, "library.yaml", "port: 7200\ntrash:\n  retention: 1h\n")
+
+	app, err := load(t, "-config", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nop := zerolog.Nop()
+	app.Log = &nop
+
+	if err := os.WriteFile(path, []byte("port: 7300\ntrash:\n  retention: 2h\ntimeout:\n  read: 1s\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if app.TrashRetention() != 2*time.Hour {
+		t.Errorf("trash retention = %s, want the reloaded 2h", app.TrashRetention())
+	}
+	if app.RouteTimeout(RouteRead) != time.Second {
+		t.Errorf("read timeout = %s, want the reloaded 1s", app.RouteTimeout(RouteRead))
+	}
+	if app.ConfigFlags.Port != 7200 || app.settings.values["port"] != "7200" {
+		t.Errorf("port changed to %d on reload, it needs a restart", app.ConfigFlags.Port)
+	}
+
+	if err := os.WriteFile(path, []byte("port: 0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Reload(); err == nil {
+		t.Fatal("an invalid file was reloaded")
+	}
+	if app.TrashRetention() != 2*time.Hour {
+		t.Errorf("a failed reload changed the retention to %s", app.TrashRetention())
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RouteGroup names the routes sharing a timeout, see -timeout-*.
+type RouteGroup string
+
+const (
+	RouteRead   RouteGroup = "read"
+	RouteWrite  RouteGroup = "write"
+	RouteSearch RouteGroup = "search"
+	RouteImport RouteGroup = "import"
+	RouteExport RouteGroup = "export"
+)
+
+// RouteTimeout is how long a request of the group may take, 0 for no limit.
+func (app *App) RouteTimeout(group RouteGroup) time.Duration {
+	app.live.mu.RLock()
+	defer app.live.mu.RUnlock()
+
+	timeouts := app.live.timeouts
+	if !app.live.loaded {
+		timeouts = app.ConfigFlags.Timeouts
+	}
+
+	switch group {
+	case RouteRead:
+		return timeouts.Read
+	case RouteWrite:
+		return timeouts.Write
+	case RouteSearch:
+		return timeouts.Search
+	case RouteImport:
+		return timeouts.Import
+	case RouteExport:
+		return timeouts.Export
+	}
+
+	return 0
+}
+
+// Timeout puts the deadline of the group on the request context.
+func (app *App) Timeout(group RouteGroup) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := app.RouteTimeout(group)
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"expvar"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+// txMetrics counts op.retries and op.gave_up by operation.
+var txMetrics = expvar.NewMap("transactions")
+
+// TxMetrics is published by expvar as "transactions".
+func TxMetrics() *expvar.Map {
+	return txMetrics
+}
+
+func (app *App) newTxRunner() *books.TxRunner {
+	c := app.ConfigFlags.Transactions
+
+	return &books.TxRunner{
+		MaxAttempts: c.MaxAttempts,
+		MinBackoff:  c.MinBackoff,
+		MaxBackoff:  c.MaxBackoff,
+		OnRetry: func(op string, attempt int, wait time.Duration, err error) {
+			txMetrics.Add(op+".retries", 1)
+			app.Log.Warn().Err(err).
+				Str("op", op).
+				Int("attempt", attempt).
+				Dur("wait", wait).
+				Msg("retrying transaction")
+		},
+		OnGiveUp: func(op string, attempts int, err error) {
+			txMetrics.Add(op+".gave_up", 1)
+			app.Log.Error().Err(err).
+				Str("op", op).
+				Int("attempts", attempts).
+				Msg("transaction failed after retries")
+		},
+	}
+}
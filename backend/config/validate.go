@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+	"github.com/rs/zerolog"
+)
+
+// ValidateConfig checks the settings once every layer is applied.
+func (app *App) ValidateConfig() error {
+	v := validator.NewValidator()
+	c := app.ConfigFlags
+
+	v.Check(c.Port > 0 && c.Port <= 65535, "port", "must be between 1 and 65535")
+	v.Check(c.GRPCPort >= 0 && c.GRPCPort <= 65535, "grpc-port", "must be between 0 and 65535")
+	v.Check(c.GRPCPort != c.Port, "grpc-port", "must be different from port")
+	v.Check(v.In(c.Environment, []string{"development", "production", "staging"}), "env", "must be development, production or staging")
+
+	v.Check(v.In(c.Storage, []string{StorageDatabase, StorageMemory}), "storage", "must be database or memory")
+
+	_, err := zerolog.ParseLevel(c.LogLevel)
+	v.Check(err == nil && c.LogLevel != "", "log-level", "must be trace, debug, info, warn or error")
+
+	v.Check(c.Server.ReadTimeout > 0, "read-timeout", "must be positive")
+	v.Check(c.Server.WriteTimeout > 0, "write-timeout", "must be positive")
+	v.Check(c.Server.IdleTimeout > 0, "idle-timeout", "must be positive")
+	v.Check(c.Server.ShutdownTimeout > 0, "shutdown-timeout", "must be positive")
+	v.Check(c.Server.MaxBodyBytes > 0, "max-body-bytes", "must be positive")
+
+	v.Check(len(c.CORS.AllowedOrigins) > 0, "cors-origins", "must not be empty")
+	for _, origin := range c.CORS.AllowedOrigins {
+		v.Check(origin == "*" || strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://"),
+			"cors-origins", fmt.Sprintf("%q must be * or start with http:// or https://", origin))
+	}
+
+	v.Check(app.Database.MaxOpenConns >= 0, "db-max-open-conns", "must not be negative")
+	v.Check(app.Database.MaxIdleConns >= 0, "db-max-idle-conns", "must not be negative")
+	v.Check(app.Database.MaxOpenConns == 0 || app.Database.MaxIdleConns <= app.Database.MaxOpenConns,
+		"db-max-idle-conns", "must not be more than db-max-open-conns")
+	v.Check(app.Database.MaxIdleTime >= 0, "db-max-idle-time", "must not be negative")
+
+	v.Check(c.Transactions.MaxAttempts >= 1, "tx-max-attempts", "must be at least 1")
+	v.Check(c.Transactions.MinBackoff >= 0, "tx-min-backoff", "must not be negative")
+	v.Check(c.Transactions.MaxBackoff >= c.Transactions.MinBackoff, "tx-max-backoff", "must not be less than tx-min-backoff")
+
+	for name, timeout := range map[string]time.Duration{
+		"timeout-read":   c.Timeouts.Read,
+		"timeout-write":  c.Timeouts.Write,
+		"timeout-search": c.Timeouts.Search,
+		"timeout-import": c.Timeouts.Import,
+		"timeout-export": c.Timeouts.Export,
+	} {
+		v.Check(timeout >= 0, name, "must not be negative")
+	}
+
+	v.Check(c.Trash.Retention >= 0, "trash-retention", "must not be negative")
+	v.Check(c.Trash.PurgeInterval > 0, "trash-purge-interval", "must be positive")
+	v.Check(c.Trash.PurgeTimeout > 0, "trash-purge-timeout", "must be positive")
+
+	v.Check(validator.Matches(app.OAI.AdminEmail, validator.EmailRX), "oai-admin-email", "must be an email address")
+	if app.OAI.BaseURL != "" {
+		u, err := url.Parse(app.OAI.BaseURL)
+		v.Check(err == nil && u.IsAbs() && u.Host != "", "oai-base-url", "must be an absolute url")
+	}
+	v.Check(app.OAI.RepositoryIdentifier != "", "oai-repository-identifier", "must be provided")
+
+	if v.Valid() {
+		return nil
+	}
+
+	problems := make([]string, 0, len(v.Errors))
+	for _, name := range sortedKeys(v.Errors) {
+		problems = append(problems, fmt.Sprintf("  %s: %s", name, v.Errors[name]))
+	}
+
+	return fmt.Errorf("config: invalid settings\n%s", strings.Join(problems, "\n"))
+}
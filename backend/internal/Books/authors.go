@@ -0,0 +1,125 @@
+package books
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+type AuthorModel struct {
+	DB *sql.DB
+}
+
+// MergeAuthors moves the books of from over to into and drops from.
+func (a *AuthorModel) MergeAuthors(ctx context.Context, t Tx, into, from int64) (*ReadAuthor, error) {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := lockAuthor(ctx, tx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := lockAuthor(ctx, tx, into)
+	if err != nil {
+		return nil, err
+	}
+
+	//touch the books for the harvesters
+	query := `
+		UPDATE books
+		SET updated_at = NOW()
+		WHERE book_id IN (
+			SELECT book_id FROM book_author_link WHERE author_id = $1
+		)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, source.Identifier); err != nil {
+		return nil, err
+	}
+
+	query = `
+		DELETE FROM book_author_link
+		WHERE author_id = $1 AND book_id IN (
+			SELECT book_id FROM book_author_link WHERE author_id = $2
+		)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, source.Identifier, target.Identifier); err != nil {
+		return nil, err
+	}
+
+	query = `
+		UPDATE book_author_link
+		SET author_id = $2
+		WHERE author_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, source.Identifier, target.Identifier); err != nil {
+		return nil, err
+	}
+
+	before := *target
+
+	query = `
+		UPDATE authors
+		SET books_authored = (
+			SELECT count(*)
+			FROM book_author_link bal
+			JOIN books b ON b.book_id = bal.book_id
+			WHERE bal.author_id = $1 AND b.deleted_at IS NULL
+		), updated_at = NOW()
+		WHERE author_id = $1
+		RETURNING books_authored
+	`
+
+	if err := tx.QueryRowContext(ctx, query, target.Identifier).Scan(&target.Books_authored); err != nil {
+		return nil, err
+	}
+
+	query = `
+		DELETE FROM authors
+		WHERE author_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, source.Identifier); err != nil {
+		return nil, err
+	}
+
+	err = audit.Record(ctx, tx, authorAuditEntity, target.ID, audit.ActionMerge,
+		map[string]ReadAuthor{"into": before, "from": *source},
+		target,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+func lockAuthor(ctx context.Context, tx *sql.Tx, id int64) (*ReadAuthor, error) {
+	query := `
+		SELECT id, name, author_id, books_authored
+		FROM authors
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	author := &ReadAuthor{}
+
+	err := tx.QueryRowContext(ctx, query, id).Scan(&author.ID, &author.Name, &author.Identifier, &author.Books_authored)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return author, nil
+}
@@ -9,15 +9,30 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
 	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
 	"github.com/lib/pq"
 )
 
 var (
-	ErrNotFound     = errors.New("record not found")
-	ErrEditConflict = errors.New("edit conflict")
+	ErrNotFound       = errors.New("record not found")
+	ErrEditConflict   = errors.New("edit conflict")
+	ErrAuthorsChanged = fmt.Errorf("%w: the authors of the book changed", ErrEditConflict)
 )
 
+// DuplicateError is returned for a book whose hash is taken, trashed or not.
+type DuplicateError struct {
+	ID      int64
+	Trashed bool
+}
+
+func (e *DuplicateError) Error() string {
+	if e.Trashed {
+		return fmt.Sprintf("the book is in the trash as %d", e.ID)
+	}
+	return fmt.Sprintf("the book already exists as %d", e.ID)
+}
+
 type Book struct {
 	ID        int64    `json:"id"`
 	Hash      string   `json:"hash"`
@@ -26,6 +41,7 @@ type Book struct {
 	Year      int32    `json:"year"`
 	PageCount int32    `json:"page_count"`
 	Genres    []string `json:"genres"`
+	ISBN      string   `json:"isbn"`
 }
 
 type Authors struct {
@@ -125,6 +141,11 @@ func (b *CreateBookEntry) ValidateEntry(v *validator.Validator) bool {
 		"%s field must contain at least %d genres", section, minGenreCount,
 	))
 
+	section = "isbn"
+	v.Check(b.Book.ISBN == "" || ValidISBN(b.Book.ISBN), section, fmt.Sprintf(
+		"%s field must be a valid ISBN-10 or ISBN-13", section,
+	))
+
 	if !v.Valid() {
 		return false
 	}
@@ -170,11 +191,20 @@ type CreateEntryModel struct {
 	DB *sql.DB
 }
 
-func (c *CreateEntryModel) Insert(ctx context.Context, tx *sql.Tx, entry *CreateBookEntry, read *ReadEntry) error {
+func (c *CreateEntryModel) Insert(ctx context.Context, t Tx, entry *CreateBookEntry, read *ReadEntry) error {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return err
+	}
+
+	if err := duplicate(ctx, tx, entry.Book.Hash); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO books(book_id,title,publisher,year,page_count,genres)
-		VALUES($1,$2,$3,$4,$5,$6)
-		RETURNING id,book_id,title,publisher,year,page_count,genres
+		INSERT INTO books(book_id,title,publisher,year,page_count,genres,isbn)
+		VALUES($1,$2,$3,$4,$5,$6,$7)
+		RETURNING id,book_id,title,publisher,year,page_count,genres,isbn,version
 	`
 
 	args := []interface{}{
@@ -183,16 +213,19 @@ func (c *CreateEntryModel) Insert(ctx context.Context, tx *sql.Tx, entry *Create
 		entry.Book.Publisher,
 		entry.Book.Year,
 		entry.Book.PageCount,
-		pq.Array(entry.Book.Genres)}
+		pq.Array(entry.Book.Genres),
+		entry.Book.ISBN}
 
-	err := tx.QueryRowContext(ctx, query, args...).Scan(
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
 		&read.Book.ID,
 		&read.Book.Hash,
 		&read.Book.Title,
 		&read.Book.Publisher,
 		&read.Book.Year,
 		&read.Book.PageCount,
-		pq.Array(&read.Book.Genres))
+		pq.Array(&read.Book.Genres),
+		&read.Book.ISBN,
+		&read.Book.Version)
 
 	if err != nil {
 		return err
@@ -207,7 +240,7 @@ func (c *CreateEntryModel) Insert(ctx context.Context, tx *sql.Tx, entry *Create
 			INSERT INTO authors(name,author_id,books_authored)
 			SELECT name, author_id, books_authored FROM filter1
 			ON CONFLICT (author_id) DO UPDATE
-			SET books_authored = authors.books_authored + 1
+			SET books_authored = authors.books_authored + 1, updated_at = NOW()
 			RETURNING id, name, author_id, books_authored
 		)
 		SELECT array_agg(id), array_agg(name), array_agg(author_id), array_agg(books_authored) FROM finished
@@ -242,8 +275,61 @@ func (c *CreateEntryModel) Insert(ctx context.Context, tx *sql.Tx, entry *Create
 
 	args = []interface{}{pq.Array([]string{entry.Book.Hash}), pq.Array(entry.Authors.Hash)}
 
-	return tx.QueryRowContext(ctx, query, args...).Scan(
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
 		pq.Array(entry.Authors.List),
 		pq.Array(entry.Authors.Hash),
 	)
+	if err != nil {
+		return err
+	}
+
+	snapshot := read.Snapshot()
+
+	if err := recordRevision(ctx, tx, read.Book.ID, snapshot); err != nil {
+		return err
+	}
+
+	return audit.Record(ctx, tx, auditEntity, read.Book.ID, audit.ActionInsert, nil, snapshot)
+}
+
+// duplicate returns a DuplicateError when a book holds hash already.
+func duplicate(ctx context.Context, tx *sql.Tx, hash string) error {
+	var id int64
+	var trashed bool
+
+	err := tx.QueryRowContext(ctx, `SELECT id, deleted_at IS NOT NULL FROM books WHERE book_id = $1`, hash).Scan(&id, &trashed)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	return &DuplicateError{ID: id, Trashed: trashed}
+}
+
+// ExistingHashes reports which of the hashes are taken, trashed books included.
+func (c *CreateEntryModel) ExistingHashes(ctx context.Context, hashes []string) (map[string]bool, error) {
+	query := `
+		SELECT book_id FROM books
+		WHERE book_id = ANY($1::TEXT[])
+	`
+
+	rows, err := c.DB.QueryContext(ctx, query, pq.Array(hashes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		existing[hash] = true
+	}
+
+	return existing, rows.Err()
 }
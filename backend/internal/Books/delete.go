@@ -4,81 +4,121 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
 )
 
 type DeleteID struct {
-	ID         int64
-	AuthorHash string
+	ID   int64
+	Hash string
 }
 
 type DeleteEntryModel struct {
 	DB *sql.DB
 }
 
-func (del *DeleteEntryModel) DeleteBook(ctx context.Context, tx *sql.Tx, id *DeleteID) error {
+// DeleteBook moves the book to the trash, links included.
+func (del *DeleteEntryModel) DeleteBook(ctx context.Context, t Tx, id *DeleteID) error {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return err
+	}
+
+	before := &ReadEntry{Book: ReadBook{ID: id.ID}}
+	err = (&ReadEntryModel{}).Get(ctx, tx, before)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
 
 	query := `
-	SELECT
-		bal.author_id
-	FROM
-		books b
-	JOIN
-		book_author_link bal ON b.book_id = bal.book_id
-	WHERE
-		b.id = $1;
+		UPDATE books
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING book_id
 	`
 
-	err := tx.QueryRowContext(ctx, query, id.ID).Scan(
-		&id.AuthorHash,
-	)
+	err = tx.QueryRowContext(ctx, query, id.ID).Scan(&id.Hash)
 	if err != nil {
-		return ErrNotFound
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
 	}
 
-	query = `
-		UPDATE authors
-		SET books_authored = books_authored - 1 
-		WHERE authors.author_id = $1
+	err = changeAuthorCounts(ctx, tx, id.Hash, -1)
+	if err != nil {
+		return err
+	}
+
+	return audit.Record(ctx, tx, auditEntity, id.ID, audit.ActionDelete, before.Snapshot(), nil)
+}
+
+func (del *DeleteEntryModel) Restore(ctx context.Context, t Tx, id *DeleteID) error {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE books
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING book_id
 	`
 
-	result, err := tx.ExecContext(ctx, query, id.AuthorHash)
+	err = tx.QueryRowContext(ctx, query, id.ID).Scan(&id.Hash)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return ErrEditConflict
+			return ErrNotFound
 		default:
 			return err
 		}
 	}
 
-	rows, err := result.RowsAffected()
+	err = changeAuthorCounts(ctx, tx, id.Hash, 1)
 	if err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return ErrNotFound
+	after := &ReadEntry{Book: ReadBook{ID: id.ID}}
+	err = (&ReadEntryModel{}).Get(ctx, tx, after)
+	if err != nil {
+		return err
 	}
 
-	query = `
-		DELETE FROM books
-		WHERE id = $1
+	return audit.Record(ctx, tx, auditEntity, id.ID, audit.ActionRestore, nil, after.Snapshot())
+}
+
+func changeAuthorCounts(ctx context.Context, tx *sql.Tx, bookHash string, delta int) error {
+	query := `
+		UPDATE authors
+		SET books_authored = books_authored + $2, updated_at = NOW()
+		WHERE author_id IN (
+			SELECT author_id FROM book_author_link WHERE book_id = $1
+		)
 	`
 
-	result, err = tx.ExecContext(ctx, query, id.ID)
+	result, err := tx.ExecContext(ctx, query, bookHash, delta)
 	if err != nil {
 		return err
 	}
 
-	rows, err = result.RowsAffected()
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 
 	if rows == 0 {
-		return ErrNotFound
+		return ErrEditConflict
 	}
 
 	return nil
-
 }
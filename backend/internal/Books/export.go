@@ -0,0 +1,105 @@
+package books
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ExportFilter struct {
+	Genre         string
+	Publisher     string
+	YearFrom      int32
+	YearTo        int32
+	ModifiedSince time.Time
+}
+
+// Stream hands every matching book to fn, which must not keep the entry.
+func (r *ReadEntryModel) Stream(ctx context.Context, filter ExportFilter, fn func(*ReadEntry) error) error {
+	query := `
+	SELECT
+		b.id,
+		b.book_id,
+		b.title,
+		b.publisher,
+		b.year,
+		b.page_count,
+		b.genres,
+		b.isbn,
+		b.version,
+		array_agg(a.id),
+		array_agg(a.name),
+		array_agg(a.author_id),
+		array_agg(a.books_authored),
+		GREATEST(b.updated_at, MAX(a.updated_at))
+	FROM
+		books b
+	JOIN
+		book_author_link bal ON b.book_id = bal.book_id
+	JOIN
+		authors a ON bal.author_id = a.author_id
+	WHERE
+		b.deleted_at IS NULL
+		AND ($1 = '' OR $1 = ANY(b.genres))
+		AND ($2 = '' OR b.publisher = $2)
+		AND ($3 = 0 OR b.year >= $3)
+		AND ($4 = 0 OR b.year <= $4)
+	GROUP BY
+		b.id
+	HAVING
+		$5::timestamptz IS NULL OR GREATEST(b.updated_at, MAX(a.updated_at)) >= $5
+	ORDER BY
+		b.id
+	`
+
+	args := []interface{}{
+		filter.Genre,
+		filter.Publisher,
+		filter.YearFrom,
+		filter.YearTo,
+		sql.NullTime{Time: filter.ModifiedSince, Valid: !filter.ModifiedSince.IsZero()},
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	entry := &ReadEntry{}
+
+	for rows.Next() {
+		*entry = ReadEntry{}
+
+		err := rows.Scan(
+			&entry.Book.ID,
+			&entry.Book.Hash,
+			&entry.Book.Title,
+			&entry.Book.Publisher,
+			&entry.Book.Year,
+			&entry.Book.PageCount,
+			pq.Array(&entry.Book.Genres),
+			&entry.Book.ISBN,
+			&entry.Book.Version,
+			pq.Array(&entry.List.ID),
+			pq.Array(&entry.List.Name),
+			pq.Array(&entry.List.Identifier),
+			pq.Array(&entry.List.Books_authored),
+			&entry.LastModified,
+		)
+		if err != nil {
+			return err
+		}
+
+		entry.Authors = make([]ReadAuthor, len(entry.List.Name))
+		entry.Convert()
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
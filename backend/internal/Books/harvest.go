@@ -0,0 +1,170 @@
+package books
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// HarvestFilter selects books for harvesting, trashed ones included.
+type HarvestFilter struct {
+	ID      int64
+	From    time.Time
+	Until   time.Time
+	Genres  []string
+	AfterID int64
+	Limit   int
+}
+
+type HarvestRecord struct {
+	Entry     *ReadEntry
+	Datestamp time.Time
+	Deleted   bool
+}
+
+// Harvest returns the books matching filter ordered by id.
+func (r *ReadEntryModel) Harvest(ctx context.Context, filter HarvestFilter) ([]*HarvestRecord, error) {
+	query := `
+	SELECT * FROM (
+		SELECT
+			b.id,
+			b.book_id,
+			b.title,
+			b.publisher,
+			b.year,
+			b.page_count,
+			b.genres,
+			b.isbn,
+			b.version,
+			array_agg(a.id),
+			array_agg(a.name),
+			array_agg(a.author_id),
+			array_agg(a.books_authored),
+			GREATEST(b.updated_at, MAX(a.updated_at)) AS last_modified,
+			COALESCE(b.deleted_at, GREATEST(b.updated_at, MAX(a.updated_at))) AS datestamp,
+			b.deleted_at IS NOT NULL AS deleted
+		FROM
+			books b
+		JOIN
+			book_author_link bal ON b.book_id = bal.book_id
+		JOIN
+			authors a ON bal.author_id = a.author_id
+		WHERE
+			($1 = 0 OR b.id = $1)
+			AND (cardinality($2::text[]) = 0 OR b.genres && $2::text[])
+			AND b.id > $3
+		GROUP BY
+			b.id
+	) h
+	WHERE
+		($4::timestamptz IS NULL OR h.datestamp >= $4)
+		AND ($5::timestamptz IS NULL OR h.datestamp <= $5)
+	ORDER BY
+		h.id
+	LIMIT $6
+	`
+
+	args := []interface{}{
+		filter.ID,
+		pq.Array(filter.Genres),
+		filter.AfterID,
+		sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		sql.NullTime{Time: filter.Until, Valid: !filter.Until.IsZero()},
+		filter.Limit,
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*HarvestRecord{}
+
+	for rows.Next() {
+		rec := &HarvestRecord{Entry: &ReadEntry{}}
+		entry := rec.Entry
+
+		err := rows.Scan(
+			&entry.Book.ID,
+			&entry.Book.Hash,
+			&entry.Book.Title,
+			&entry.Book.Publisher,
+			&entry.Book.Year,
+			&entry.Book.PageCount,
+			pq.Array(&entry.Book.Genres),
+			&entry.Book.ISBN,
+			&entry.Book.Version,
+			pq.Array(&entry.List.ID),
+			pq.Array(&entry.List.Name),
+			pq.Array(&entry.List.Identifier),
+			pq.Array(&entry.List.Books_authored),
+			&entry.LastModified,
+			&rec.Datestamp,
+			&rec.Deleted,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Authors = make([]ReadAuthor, len(entry.List.Name))
+		entry.Convert()
+
+		records = append(records, rec)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// Genres lists every genre used in the catalogue, trashed books included.
+func (r *ReadEntryModel) Genres(ctx context.Context) ([]string, error) {
+	query := `
+	SELECT DISTINCT unnest(genres) AS genre
+	FROM books
+	ORDER BY genre
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []string{}
+
+	for rows.Next() {
+		var genre string
+		if err := rows.Scan(&genre); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+// EarliestDatestamp is the oldest known change, zero for an empty catalogue.
+func (r *ReadEntryModel) EarliestDatestamp(ctx context.Context) (time.Time, error) {
+	query := `
+	SELECT MIN(updated_at) FROM books
+	`
+
+	var earliest sql.NullTime
+
+	err := r.DB.QueryRowContext(ctx, query).Scan(&earliest)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return earliest.Time, nil
+}
@@ -0,0 +1,57 @@
+package books
+
+import "strings"
+
+// NormalizeISBN drops hyphens and spaces and upper-cases the check character.
+func NormalizeISBN(isbn string) string {
+	var b strings.Builder
+
+	for _, r := range isbn {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == 'x' || r == 'X':
+			b.WriteRune('X')
+		}
+	}
+
+	return b.String()
+}
+
+func ValidISBN(isbn string) bool {
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i := 0; i < 10; i++ {
+			c := isbn[i]
+			var digit int
+			switch {
+			case c >= '0' && c <= '9':
+				digit = int(c - '0')
+			case c == 'X' && i == 9:
+				digit = 10
+			default:
+				return false
+			}
+			sum += digit * (10 - i)
+		}
+		return sum%11 == 0
+
+	case 13:
+		sum := 0
+		for i := 0; i < 13; i++ {
+			c := isbn[i]
+			if c < '0' || c > '9' {
+				return false
+			}
+			digit := int(c - '0')
+			if i%2 == 1 {
+				digit *= 3
+			}
+			sum += digit
+		}
+		return sum%10 == 0
+	}
+
+	return false
+}
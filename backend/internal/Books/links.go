@@ -0,0 +1,93 @@
+package books
+
+import (
+	"context"
+	"database/sql"
+)
+
+type LinkModel struct {
+	DB *sql.DB
+}
+
+// Linked returns the author hashes of a book in link order.
+func (l *LinkModel) Linked(ctx context.Context, t Tx, bookHash string) ([]string, error) {
+	query := `
+		SELECT author_id
+		FROM book_author_link
+		WHERE book_id = $1
+		ORDER BY id
+	`
+
+	var q interface {
+		QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	} = l.DB
+
+	if t != nil {
+		tx, err := sqlTx(t)
+		if err != nil {
+			return nil, err
+		}
+		q = tx
+	}
+
+	rows, err := q.QueryContext(ctx, query, bookHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, rows.Err()
+}
+
+func (l *LinkModel) Link(ctx context.Context, t Tx, bookHash string, authorHashes []string) error {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO book_author_link(book_id, author_id)
+		SELECT $1, $2
+		WHERE NOT EXISTS (
+			SELECT 1 FROM book_author_link WHERE book_id = $1 AND author_id = $2
+		)
+	`
+
+	for _, hash := range authorHashes {
+		if _, err := tx.ExecContext(ctx, query, bookHash, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *LinkModel) Unlink(ctx context.Context, t Tx, bookHash string, authorHashes []string) error {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM book_author_link
+		WHERE book_id = $1 AND author_id = $2
+	`
+
+	for _, hash := range authorHashes {
+		if _, err := tx.ExecContext(ctx, query, bookHash, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,369 @@
+package books
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	"github.com/lib/pq"
+)
+
+// ListFilter narrows the live books, Query matches title, publisher or author.
+type ListFilter struct {
+	Query    string
+	Genre    string
+	AuthorID int64
+}
+
+func (r *ReadEntryModel) List(ctx context.Context, filter ListFilter, filters internal.Filters) ([]*ReadEntry, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		count(*) OVER(),
+		b.id,
+		b.book_id,
+		b.title,
+		b.publisher,
+		b.year,
+		b.page_count,
+		b.genres,
+		b.isbn,
+		b.version,
+		array_agg(a.id),
+		array_agg(a.name),
+		array_agg(a.author_id),
+		array_agg(a.books_authored),
+		GREATEST(b.updated_at, MAX(a.updated_at))
+	FROM
+		books b
+	JOIN
+		book_author_link bal ON b.book_id = bal.book_id
+	JOIN
+		authors a ON bal.author_id = a.author_id
+	WHERE
+		b.deleted_at IS NULL
+		AND ($1 = '' OR b.title ILIKE $1 OR b.publisher ILIKE $1 OR EXISTS (
+			SELECT 1 FROM book_author_link l JOIN authors au ON au.author_id = l.author_id
+			WHERE l.book_id = b.book_id AND au.name ILIKE $1
+		))
+		AND ($2 = '' OR $2 = ANY(b.genres))
+		AND ($3 = 0 OR EXISTS (
+			SELECT 1 FROM book_author_link l JOIN authors au ON au.author_id = l.author_id
+			WHERE l.book_id = b.book_id AND au.id = $3
+		))
+	GROUP BY
+		b.id
+	ORDER BY
+		b.%s %s, b.id ASC
+	LIMIT $4 OFFSET $5
+	`, column, filters.SortDirection())
+
+	pattern := ""
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		pattern = "%" + likeEscaper.Replace(q) + "%"
+	}
+
+	args := []interface{}{pattern, filter.Genre, filter.AuthorID, filters.Limit(), filters.Offset()}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	entries := []*ReadEntry{}
+
+	for rows.Next() {
+		entry := &ReadEntry{}
+
+		err := rows.Scan(
+			&totalRecords,
+			&entry.Book.ID,
+			&entry.Book.Hash,
+			&entry.Book.Title,
+			&entry.Book.Publisher,
+			&entry.Book.Year,
+			&entry.Book.PageCount,
+			pq.Array(&entry.Book.Genres),
+			&entry.Book.ISBN,
+			&entry.Book.Version,
+			pq.Array(&entry.List.ID),
+			pq.Array(&entry.List.Name),
+			pq.Array(&entry.List.Identifier),
+			pq.Array(&entry.List.Books_authored),
+			&entry.LastModified,
+		)
+		if err != nil {
+			return nil, internal.Metadata{}, err
+		}
+
+		entry.Authors = make([]ReadAuthor, len(entry.List.Name))
+		entry.Convert()
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return entries, internal.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type AuthorSummary struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	BooksAuthored int32  `json:"books_authored"`
+}
+
+// ListAuthors pages through the authors of live books.
+func (a *AuthorModel) ListAuthors(ctx context.Context, filters internal.Filters) ([]*AuthorSummary, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), id, name, books_authored
+	FROM authors
+	WHERE books_authored > 0
+	ORDER BY %s %s, id ASC
+	LIMIT $1 OFFSET $2
+	`, column, filters.SortDirection())
+
+	rows, err := a.DB.QueryContext(ctx, query, filters.Limit(), filters.Offset())
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	authors := []*AuthorSummary{}
+
+	for rows.Next() {
+		var author AuthorSummary
+
+		err := rows.Scan(&totalRecords, &author.ID, &author.Name, &author.BooksAuthored)
+		if err != nil {
+			return nil, internal.Metadata{}, err
+		}
+
+		authors = append(authors, &author)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return authors, internal.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+type GenreCount struct {
+	Genre string `json:"genre"`
+	Books int    `json:"books"`
+}
+
+// GenreCounts counts the live books of every genre.
+func (r *ReadEntryModel) GenreCounts(ctx context.Context) ([]*GenreCount, error) {
+	query := `
+	SELECT genre, count(*)
+	FROM books, unnest(genres) AS genre
+	WHERE deleted_at IS NULL
+	GROUP BY genre
+	ORDER BY genre
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []*GenreCount{}
+
+	for rows.Next() {
+		var genre GenreCount
+		if err := rows.Scan(&genre.Genre, &genre.Books); err != nil {
+			return nil, err
+		}
+		genres = append(genres, &genre)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+// GetMany reads the live books with the given ids in order, skipping unknown ones.
+func (r *ReadEntryModel) GetMany(ctx context.Context, ids []int64) ([]*ReadEntry, error) {
+	query := `
+	SELECT
+		b.id,
+		b.book_id,
+		b.title,
+		b.publisher,
+		b.year,
+		b.page_count,
+		b.genres,
+		b.isbn,
+		b.version,
+		array_agg(a.id),
+		array_agg(a.name),
+		array_agg(a.author_id),
+		array_agg(a.books_authored),
+		GREATEST(b.updated_at, MAX(a.updated_at))
+	FROM
+		books b
+	JOIN
+		book_author_link bal ON b.book_id = bal.book_id
+	JOIN
+		authors a ON bal.author_id = a.author_id
+	WHERE
+		b.deleted_at IS NULL AND b.id = ANY($1)
+	GROUP BY
+		b.id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*ReadEntry, len(ids))
+
+	for rows.Next() {
+		entry := &ReadEntry{}
+
+		err := rows.Scan(
+			&entry.Book.ID,
+			&entry.Book.Hash,
+			&entry.Book.Title,
+			&entry.Book.Publisher,
+			&entry.Book.Year,
+			&entry.Book.PageCount,
+			pq.Array(&entry.Book.Genres),
+			&entry.Book.ISBN,
+			&entry.Book.Version,
+			pq.Array(&entry.List.ID),
+			pq.Array(&entry.List.Name),
+			pq.Array(&entry.List.Identifier),
+			pq.Array(&entry.List.Books_authored),
+			&entry.LastModified,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Authors = make([]ReadAuthor, len(entry.List.Name))
+		entry.Convert()
+
+		byID[entry.Book.ID] = entry
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*ReadEntry, 0, len(byID))
+	for _, id := range ids {
+		if entry, ok := byID[id]; ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// BooksByAuthors reads the live books of several authors in one query.
+func (a *AuthorModel) BooksByAuthors(ctx context.Context, authorIDs []int64) (map[int64][]*ReadEntry, error) {
+	query := `
+	SELECT
+		x.id,
+		b.id,
+		b.book_id,
+		b.title,
+		b.publisher,
+		b.year,
+		b.page_count,
+		b.genres,
+		b.isbn,
+		b.version,
+		array_agg(a.id),
+		array_agg(a.name),
+		array_agg(a.author_id),
+		array_agg(a.books_authored),
+		GREATEST(b.updated_at, MAX(a.updated_at))
+	FROM
+		authors x
+	JOIN
+		book_author_link xl ON xl.author_id = x.author_id
+	JOIN
+		books b ON b.book_id = xl.book_id
+	JOIN
+		book_author_link bal ON b.book_id = bal.book_id
+	JOIN
+		authors a ON bal.author_id = a.author_id
+	WHERE
+		x.id = ANY($1) AND b.deleted_at IS NULL
+	GROUP BY
+		x.id, b.id
+	ORDER BY
+		x.id, b.id
+	`
+
+	rows, err := a.DB.QueryContext(ctx, query, pq.Array(authorIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byAuthor := make(map[int64][]*ReadEntry, len(authorIDs))
+
+	for rows.Next() {
+		var authorID int64
+		entry := &ReadEntry{}
+
+		err := rows.Scan(
+			&authorID,
+			&entry.Book.ID,
+			&entry.Book.Hash,
+			&entry.Book.Title,
+			&entry.Book.Publisher,
+			&entry.Book.Year,
+			&entry.Book.PageCount,
+			pq.Array(&entry.Book.Genres),
+			&entry.Book.ISBN,
+			&entry.Book.Version,
+			pq.Array(&entry.List.ID),
+			pq.Array(&entry.List.Name),
+			pq.Array(&entry.List.Identifier),
+			pq.Array(&entry.List.Books_authored),
+			&entry.LastModified,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Authors = make([]ReadAuthor, len(entry.List.Name))
+		entry.Convert()
+
+		byAuthor[authorID] = append(byAuthor[authorID], entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return byAuthor, nil
+}
@@ -3,6 +3,8 @@ package books
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/lib/pq"
 )
@@ -15,6 +17,7 @@ type ReadBook struct {
 	Year      int32    `json:"year"`
 	PageCount int32    `json:"page_count"`
 	Genres    []string `json:"genres,omitempty"`
+	ISBN      string   `json:"isbn,omitempty"`
 	Version   int32    `json:"version,omitempty"`
 }
 
@@ -33,9 +36,15 @@ type ReadAuthorList struct {
 }
 
 type ReadEntry struct {
-	Book    ReadBook       `json:"Book"`
-	Authors []ReadAuthor   `json:"authors"`
-	List    ReadAuthorList `json:"-"`
+	Book         ReadBook       `json:"Book"`
+	Authors      []ReadAuthor   `json:"authors"`
+	List         ReadAuthorList `json:"-"`
+	LastModified time.Time      `json:"-"`
+}
+
+// ETag is "<version>.<modified>".
+func (r *ReadEntry) ETag() string {
+	return fmt.Sprintf(`"%d.%x"`, r.Book.Version, r.LastModified.UnixMicro())
 }
 
 func (r *ReadEntry) Convert() {
@@ -53,28 +62,36 @@ type ReadEntryModel struct {
 	DB *sql.DB
 }
 
-func (r *ReadEntryModel) Get(ctx context.Context, tx *sql.Tx, read *ReadEntry) error {
+func (r *ReadEntryModel) Get(ctx context.Context, tx Tx, read *ReadEntry) error {
 	query := `
-	SELECT b.book_id,b.title,b.publisher,b.year,b.page_count,b.genres, array_agg(a.name), array_agg(a.author_id), array_agg(a.id), array_agg(a.books_authored) AS authors
+	SELECT b.book_id,b.title,b.publisher,b.year,b.page_count,b.genres,b.isbn,b.version, array_agg(a.name), array_agg(a.author_id), array_agg(a.id), array_agg(a.books_authored) AS authors,
+		GREATEST(b.updated_at, MAX(a.updated_at))
 	FROM books b
 	JOIN book_author_link bal ON b.book_id = bal.book_id
 	JOIN authors a ON bal.author_id = a.author_id
-	WHERE b.id = $1
+	WHERE b.id = $1 AND b.deleted_at IS NULL
 	GROUP BY b.id;
 	`
 
 	if tx != nil {
-		return tx.QueryRowContext(ctx, query, read.Book.ID).Scan(
+		sqltx, err := sqlTx(tx)
+		if err != nil {
+			return err
+		}
+		return sqltx.QueryRowContext(ctx, query, read.Book.ID).Scan(
 			&read.Book.Hash,
 			&read.Book.Title,
 			&read.Book.Publisher,
 			&read.Book.Year,
 			&read.Book.PageCount,
 			pq.Array(&read.Book.Genres),
+			&read.Book.ISBN,
+			&read.Book.Version,
 			pq.Array(&read.List.Name),
 			pq.Array(&read.List.Identifier),
 			pq.Array(&read.List.ID),
 			pq.Array(&read.List.Books_authored),
+			&read.LastModified,
 		)
 	} else {
 		return r.DB.QueryRowContext(ctx, query, read.Book.ID).Scan(
@@ -84,18 +101,26 @@ func (r *ReadEntryModel) Get(ctx context.Context, tx *sql.Tx, read *ReadEntry) e
 			&read.Book.Year,
 			&read.Book.PageCount,
 			pq.Array(&read.Book.Genres),
+			&read.Book.ISBN,
+			&read.Book.Version,
 			pq.Array(&read.List.Name),
 			pq.Array(&read.List.Identifier),
 			pq.Array(&read.List.ID),
 			pq.Array(&read.List.Books_authored),
+			&read.LastModified,
 		)
 	}
 }
 
 type ReadAuthorEntry struct {
-	BookList    ReadBookList `json:"-"`
-	Author      ReadAuthor   `json:"authors"`
-	BookDisplay []ReadBook   `json:"books"`
+	BookList     ReadBookList `json:"-"`
+	Author       ReadAuthor   `json:"authors"`
+	BookDisplay  []ReadBook   `json:"books"`
+	LastModified time.Time    `json:"-"`
+}
+
+func (r *ReadAuthorEntry) ETag() string {
+	return fmt.Sprintf(`"%d.%x"`, r.Author.Books_authored, r.LastModified.UnixMicro())
 }
 
 type ReadBookList struct {
@@ -120,7 +145,7 @@ func (r *ReadAuthorEntry) Convert() {
 	}
 }
 
-func (r *ReadEntryModel) AuthorGet(ctx context.Context, tx *sql.Tx, read *ReadAuthorEntry) error {
+func (a *AuthorModel) AuthorGet(ctx context.Context, tx Tx, read *ReadAuthorEntry) error {
 	query := `
 	SELECT
 		a.author_id,
@@ -131,7 +156,8 @@ func (r *ReadEntryModel) AuthorGet(ctx context.Context, tx *sql.Tx, read *ReadAu
 		ARRAY_AGG(b.title) AS titles,
 		ARRAY_AGG(b.publisher) AS publishers,
 		ARRAY_AGG(b.year) AS years,
-		ARRAY_AGG(b.page_count) AS page_counts
+		ARRAY_AGG(b.page_count) AS page_counts,
+		GREATEST(a.updated_at, MAX(b.updated_at))
 	FROM
 		authors a
 	JOIN
@@ -139,15 +165,20 @@ func (r *ReadEntryModel) AuthorGet(ctx context.Context, tx *sql.Tx, read *ReadAu
 	JOIN
 		books b ON bal.book_id = b.book_id
 	WHERE
-		a.id = $1
+		a.id = $1 AND b.deleted_at IS NULL
 	GROUP BY
 		a.author_id,
 		a.name,
-		a.books_authored;
+		a.books_authored,
+		a.updated_at;
 	`
 
 	if tx != nil {
-		return tx.QueryRowContext(ctx, query, read.Author.ID).Scan(
+		sqltx, err := sqlTx(tx)
+		if err != nil {
+			return err
+		}
+		return sqltx.QueryRowContext(ctx, query, read.Author.ID).Scan(
 			&read.Author.Identifier,
 			&read.Author.Name,
 			&read.Author.Books_authored,
@@ -157,9 +188,10 @@ func (r *ReadEntryModel) AuthorGet(ctx context.Context, tx *sql.Tx, read *ReadAu
 			pq.Array(&read.BookList.Publisher),
 			pq.Array(&read.BookList.Year),
 			pq.Array(&read.BookList.PageCount),
+			&read.LastModified,
 		)
 	} else {
-		return r.DB.QueryRowContext(ctx, query, read.Author.ID).Scan(
+		return a.DB.QueryRowContext(ctx, query, read.Author.ID).Scan(
 			&read.Author.Identifier,
 			&read.Author.Name,
 			&read.Author.Books_authored,
@@ -169,6 +201,7 @@ func (r *ReadEntryModel) AuthorGet(ctx context.Context, tx *sql.Tx, read *ReadAu
 			pq.Array(&read.BookList.Publisher),
 			pq.Array(&read.BookList.Year),
 			pq.Array(&read.BookList.PageCount),
+			&read.LastModified,
 		)
 	}
 }
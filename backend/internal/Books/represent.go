@@ -0,0 +1,131 @@
+package books
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const schemaContext = "https://schema.org"
+
+func bookURL(base string, id int64) string {
+	return fmt.Sprintf("%s/v1/fetch/book/%d", base, id)
+}
+
+func authorURL(base string, id int64) string {
+	return fmt.Sprintf("%s/v1/fetch/author/%d", base, id)
+}
+
+func bookNode(base string, b ReadBook) map[string]interface{} {
+	node := map[string]interface{}{
+		"@type": "Book",
+		"@id":   bookURL(base, b.ID),
+		"name":  b.Title,
+	}
+	if b.Publisher != "" {
+		node["publisher"] = map[string]interface{}{
+			"@type": "Organization",
+			"name":  b.Publisher,
+		}
+	}
+	if b.Year != 0 {
+		node["datePublished"] = strconv.Itoa(int(b.Year))
+	}
+	if b.PageCount != 0 {
+		node["numberOfPages"] = b.PageCount
+	}
+	if len(b.Genres) > 0 {
+		node["genre"] = b.Genres
+	}
+	if b.ISBN != "" {
+		node["isbn"] = b.ISBN
+	}
+	return node
+}
+
+func personNode(base string, a ReadAuthor) map[string]interface{} {
+	node := map[string]interface{}{
+		"@type": "Person",
+		"@id":   authorURL(base, a.ID),
+		"name":  a.Name,
+	}
+	if a.Identifier != "" {
+		node["identifier"] = a.Identifier
+	}
+	return node
+}
+
+// LinkedData describes the book as a schema.org Book.
+func (r *ReadEntry) LinkedData(base string) interface{} {
+	node := bookNode(base, r.Book)
+	node["@context"] = schemaContext
+
+	authors := make([]interface{}, len(r.Authors))
+	for i, a := range r.Authors {
+		authors[i] = personNode(base, a)
+	}
+	node["author"] = authors
+
+	if !r.LastModified.IsZero() {
+		node["dateModified"] = r.LastModified.UTC().Format(time.RFC3339)
+	}
+	return node
+}
+
+// CSVRecords flattens the book into a single row.
+func (r *ReadEntry) CSVRecords() [][]string {
+	names := make([]string, len(r.Authors))
+	for i, a := range r.Authors {
+		names[i] = a.Name
+	}
+
+	return [][]string{
+		{"id", "title", "authors", "publisher", "year", "page_count", "genres", "isbn"},
+		{
+			strconv.FormatInt(r.Book.ID, 10),
+			r.Book.Title,
+			strings.Join(names, "; "),
+			r.Book.Publisher,
+			strconv.Itoa(int(r.Book.Year)),
+			strconv.Itoa(int(r.Book.PageCount)),
+			strings.Join(r.Book.Genres, "; "),
+			r.Book.ISBN,
+		},
+	}
+}
+
+// LinkedData describes the author as a schema.org Person.
+func (r *ReadAuthorEntry) LinkedData(base string) interface{} {
+	node := personNode(base, r.Author)
+	node["@context"] = schemaContext
+
+	works := make([]interface{}, len(r.BookDisplay))
+	for i, b := range r.BookDisplay {
+		works[i] = bookNode(base, b)
+	}
+	node["workExample"] = works
+
+	return node
+}
+
+// CSVRecords lists the author's books, one row each.
+func (r *ReadAuthorEntry) CSVRecords() [][]string {
+	records := [][]string{
+		{"author_id", "author", "book_id", "title", "publisher", "year", "page_count"},
+	}
+
+	for _, b := range r.BookDisplay {
+		records = append(records, []string{
+			strconv.FormatInt(r.Author.ID, 10),
+			r.Author.Name,
+			strconv.FormatInt(b.ID, 10),
+			b.Title,
+			b.Publisher,
+			strconv.Itoa(int(b.Year)),
+			strconv.Itoa(int(b.PageCount)),
+		})
+	}
+
+	return records
+}
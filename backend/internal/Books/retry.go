@@ -0,0 +1,109 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// TxRunner retries the transactions aborted with SQLSTATE 40001, nil runs them once.
+type TxRunner struct {
+	//the first run included
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	OnRetry     func(op string, attempt int, wait time.Duration, err error)
+	//a retryable error returned anyway
+	OnGiveUp func(op string, attempts int, err error)
+}
+
+// IsRetryable reports whether the transaction can be run again.
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "40001"
+}
+
+// Retry calls fn, a whole transaction, until it returns nil or an error that is not retryable.
+func (r *TxRunner) Retry(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	maxAttempts := 1
+	if r != nil && r.MaxAttempts > 1 {
+		maxAttempts = r.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+
+		if r == nil {
+			return err
+		}
+
+		wait := r.backoff(attempt)
+
+		if attempt >= maxAttempts || !fits(ctx, wait) {
+			if r.OnGiveUp != nil {
+				r.OnGiveUp(op, attempt, err)
+			}
+			return err
+		}
+
+		if r.OnRetry != nil {
+			r.OnRetry(op, attempt, wait, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Run begins a transaction, hands it to fn and commits it, see Retry.
+func (r *TxRunner) Run(ctx context.Context, op string, begin func(ctx context.Context) (Tx, error), fn func(ctx context.Context, tx Tx) error) error {
+	return r.Retry(ctx, op, func(ctx context.Context) error {
+		tx, err := begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := fn(ctx, tx); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// backoff doubles from MinBackoff up to MaxBackoff, half of it random.
+func (r *TxRunner) backoff(attempt int) time.Duration {
+	wait := r.MinBackoff
+	for i := 1; i < attempt && wait < r.MaxBackoff; i++ {
+		wait *= 2
+	}
+	wait = min(wait, r.MaxBackoff)
+
+	if wait <= 0 {
+		return 0
+	}
+
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// fits reports whether ctx is still alive after waiting d.
+func fits(ctx context.Context, d time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > d
+}
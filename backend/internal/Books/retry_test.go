@@ -0,0 +1,125 @@
+package books_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/lib/pq"
+)
+
+var errRestart = fmt.Errorf("update books: %w", &pq.Error{Code: "40001", Message: "restart transaction"})
+
+type fakeTx struct {
+	commits, rollbacks int
+}
+
+func (tx *fakeTx) Commit() error   { tx.commits++; return nil }
+func (tx *fakeTx) Rollback() error { tx.rollbacks++; return nil }
+
+// failing fails with err the first n calls.
+func failing(n int, err error) (func(ctx context.Context) error, *int) {
+	calls := 0
+	return func(ctx context.Context) error {
+		calls++
+		if calls <= n {
+			return err
+		}
+		return nil
+	}, &calls
+}
+
+func TestRetry(t *testing.T) {
+	var retries []int
+	var gaveUp int
+
+	runner := &books.TxRunner{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  4 * time.Millisecond,
+		OnRetry: func(op string, attempt int, wait time.Duration, err error) {
+			if op != "update" || wait > 4*time.Millisecond {
+				t.Errorf("retry of %s after %v", op, wait)
+			}
+			retries = append(retries, attempt)
+		},
+		OnGiveUp: func(op string, attempts int, err error) {
+			gaveUp = attempts
+		},
+	}
+
+	fn, calls := failing(2, errRestart)
+	if err := runner.Retry(context.Background(), "update", fn); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 3 || len(retries) != 2 || retries[1] != 2 {
+		t.Errorf("got %d calls and retries %v, want 3 calls after retries [1 2]", *calls, retries)
+	}
+
+	fn, calls = failing(3, errRestart)
+	if err := runner.Retry(context.Background(), "update", fn); !errors.Is(err, errRestart) {
+		t.Errorf("got %v, want the restart error once the attempts ran out", err)
+	}
+	if *calls != 3 || gaveUp != 3 {
+		t.Errorf("got %d calls, gave up after %d, want 3 and 3", *calls, gaveUp)
+	}
+
+	other := errors.New("no such table")
+	fn, calls = failing(1, other)
+	if err := runner.Retry(context.Background(), "update", fn); err != other || *calls != 1 {
+		t.Errorf("got %v after %d calls, want the error of the only call", err, *calls)
+	}
+}
+
+func TestRetryDeadline(t *testing.T) {
+	runner := &books.TxRunner{MaxAttempts: 10, MinBackoff: time.Second, MaxBackoff: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	fn, calls := failing(10, errRestart)
+	if err := runner.Retry(ctx, "create", fn); !errors.Is(err, errRestart) {
+		t.Errorf("got %v, want the restart error", err)
+	}
+	if *calls != 1 || time.Since(start) > 40*time.Millisecond {
+		t.Errorf("made %d calls in %v, the wait did not fit before the deadline", *calls, time.Since(start))
+	}
+}
+
+func TestRetryNilRunner(t *testing.T) {
+	var runner *books.TxRunner
+
+	fn, calls := failing(1, errRestart)
+	if err := runner.Retry(context.Background(), "delete", fn); !errors.Is(err, errRestart) || *calls != 1 {
+		t.Errorf("got %v after %d calls, a nil runner should not retry", err, *calls)
+	}
+}
+
+func TestRun(t *testing.T) {
+	runner := &books.TxRunner{MaxAttempts: 2}
+
+	var txs []*fakeTx
+	begin := func(ctx context.Context) (books.Tx, error) {
+		tx := &fakeTx{}
+		txs = append(txs, tx)
+		return tx, nil
+	}
+
+	err := runner.Run(context.Background(), "delete", begin, func(ctx context.Context, tx books.Tx) error {
+		if len(txs) == 1 {
+			return errRestart
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(txs) != 2 || txs[0].commits != 0 || txs[0].rollbacks != 1 || txs[1].commits != 1 {
+		t.Errorf("got transactions %+v, want the first rolled back and the second committed", txs)
+	}
+}
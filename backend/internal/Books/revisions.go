@@ -0,0 +1,183 @@
+package books
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+type Revision struct {
+	BookID    int64     `json:"book_id"`
+	Version   int32     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Actor     string    `json:"actor"`
+	Snapshot  Snapshot  `json:"snapshot"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type RevisionDiff struct {
+	From    int32         `json:"from_version"`
+	To      int32         `json:"to_version"`
+	Fields  []FieldChange `json:"fields"`
+	Authors struct {
+		Added   []string `json:"added"`
+		Removed []string `json:"removed"`
+		Kept    []string `json:"kept"`
+	} `json:"authors"`
+}
+
+func DiffSnapshots(from, to Snapshot) *RevisionDiff {
+	diff := &RevisionDiff{
+		From:   from.Version,
+		To:     to.Version,
+		Fields: []FieldChange{},
+	}
+
+	addChange := func(field string, changed bool, old, new interface{}) {
+		if changed {
+			diff.Fields = append(diff.Fields, FieldChange{Field: field, From: old, To: new})
+		}
+	}
+
+	addChange("title", from.Title != to.Title, from.Title, to.Title)
+	addChange("publisher", from.Publisher != to.Publisher, from.Publisher, to.Publisher)
+	addChange("year", from.Year != to.Year, from.Year, to.Year)
+	addChange("page_count", from.PageCount != to.PageCount, from.PageCount, to.PageCount)
+	addChange("isbn", from.ISBN != to.ISBN, from.ISBN, to.ISBN)
+
+	_, removedGenres, addedGenres := internal.DiffArrays(from.Genres, to.Genres)
+	addChange("genres", len(removedGenres) != 0 || len(addedGenres) != 0, from.Genres, to.Genres)
+
+	diff.Authors.Kept, diff.Authors.Removed, diff.Authors.Added = internal.DiffArrays(from.Authors, to.Authors)
+
+	return diff
+}
+
+func recordRevision(ctx context.Context, tx *sql.Tx, bookID int64, snapshot Snapshot) error {
+	js, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO book_revisions(book_id, version, actor, snapshot)
+		VALUES($1, $2, $3, $4)
+	`
+
+	_, err = tx.ExecContext(ctx, query, bookID, snapshot.Version, audit.FromContext(ctx).Actor, string(js))
+	return err
+}
+
+type RevisionModel struct {
+	DB *sql.DB
+}
+
+func (m *RevisionModel) List(ctx context.Context, bookID int64) ([]*Revision, error) {
+	query := `
+	SELECT book_id, version, created_at, actor, snapshot
+	FROM book_revisions
+	WHERE book_id = $1
+	ORDER BY version DESC
+	`
+
+	rows, err := m.DB.QueryContext(ctx, query, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []*Revision{}
+
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (m *RevisionModel) Get(ctx context.Context, bookID int64, version int32) (*Revision, error) {
+	query := `
+	SELECT book_id, version, created_at, actor, snapshot
+	FROM book_revisions
+	WHERE book_id = $1 AND version = $2
+	`
+
+	revision, err := scanRevision(m.DB.QueryRowContext(ctx, query, bookID, version))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return revision, nil
+}
+
+// Previous returns the latest revision before version.
+func (m *RevisionModel) Previous(ctx context.Context, bookID int64, version int32) (*Revision, error) {
+	query := `
+	SELECT book_id, version, created_at, actor, snapshot
+	FROM book_revisions
+	WHERE book_id = $1 AND version < $2
+	ORDER BY version DESC
+	LIMIT 1
+	`
+
+	revision, err := scanRevision(m.DB.QueryRowContext(ctx, query, bookID, version))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return revision, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRevision(row rowScanner) (*Revision, error) {
+	var revision Revision
+	var snapshot []byte
+
+	err := row.Scan(
+		&revision.BookID,
+		&revision.Version,
+		&revision.CreatedAt,
+		&revision.Actor,
+		&snapshot,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
@@ -0,0 +1,120 @@
+package books
+
+import (
+	"context"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+)
+
+// Normalize upper-cases the author names of a validated entry and hashes it.
+func (b *CreateBookEntry) Normalize() {
+	for index, value := range b.Authors.List {
+		b.Authors.List[index] = internal.ToUpper(value)
+	}
+
+	HashEntries(b.Book, b.Authors)
+}
+
+// Save inserts a normalized entry in its own transaction.
+func (c *CreateEntryModel) Save(ctx context.Context, entry *CreateBookEntry, read *ReadEntry) error {
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	read.Authors = make([]ReadAuthor, len(entry.Authors.List))
+
+	if err := c.Insert(ctx, tx, entry, read); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	read.Convert()
+
+	return nil
+}
+
+// BookPatch is a partial update, nil fields are left alone.
+type BookPatch struct {
+	Title     *string
+	Publisher *string
+	Year      *int32
+	PageCount *int32
+	Genres    *[]string
+	ISBN      *string
+	Authors   *[]string
+}
+
+// Apply merges the patch over current, the result is not validated.
+func (p BookPatch) Apply(current *ReadEntry) *UpdateEntry {
+	entry := &UpdateEntry{
+		Book: UpdateBook{
+			ID:        &current.Book.ID,
+			Title:     &current.Book.Title,
+			Publisher: &current.Book.Publisher,
+			Year:      &current.Book.Year,
+			PageCount: &current.Book.PageCount,
+			Genres:    current.Book.Genres,
+			ISBN:      &current.Book.ISBN,
+			Version:   &current.Book.Version,
+		},
+		Author: UpdateAuthors{
+			Name: current.List.Name,
+		},
+	}
+
+	if p.Title != nil {
+		entry.Book.Title = p.Title
+	}
+	if p.Publisher != nil {
+		entry.Book.Publisher = p.Publisher
+	}
+	if p.Year != nil {
+		entry.Book.Year = p.Year
+	}
+	if p.PageCount != nil {
+		entry.Book.PageCount = p.PageCount
+	}
+	if p.Genres != nil {
+		entry.Book.Genres = *p.Genres
+	}
+	if p.ISBN != nil {
+		isbn := NormalizeISBN(*p.ISBN)
+		entry.Book.ISBN = &isbn
+	}
+	if p.Authors != nil {
+		names := make([]string, len(*p.Authors))
+		for index, value := range *p.Authors {
+			names[index] = internal.ToUpper(value)
+		}
+		entry.Author.Name = names
+	}
+
+	return entry
+}
+
+// Save writes a validated update over current in its own transaction.
+func (u *UpdateEntryModel) Save(ctx context.Context, entry *UpdateEntry, current *ReadEntry) error {
+	_, exclusiveOld, exclusiveNew := internal.DiffArrays(current.List.Name, entry.Author.Name)
+
+	hashOld, hashNew := internal.HashArrays(exclusiveOld, exclusiveNew)
+
+	entry.HashEntries()
+
+	tx, err := u.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = u.Update(ctx, tx, entry, current, hashOld, exclusiveNew, hashNew)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,360 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Condition is a search over the live books.
+type Condition interface {
+	condition()
+}
+
+type (
+	And struct{ Left, Right Condition }
+	Or  struct{ Left, Right Condition }
+	Not struct{ Condition Condition }
+
+	// Everything matches every book
+	Everything struct{}
+
+	// TextMatch matches when any value of the field does
+	TextMatch struct {
+		Field   TextField
+		Pattern Pattern
+	}
+
+	// TextCompare compares case insensitively
+	TextCompare struct {
+		Field TextField
+		Op    Comparison
+		Value string
+	}
+
+	YearCompare struct {
+		Op   Comparison
+		Year int
+	}
+
+	ISBNIs struct {
+		ISBN string
+	}
+)
+
+func (And) condition()         {}
+func (Or) condition()          {}
+func (Not) condition()         {}
+func (Everything) condition()  {}
+func (TextMatch) condition()   {}
+func (TextCompare) condition() {}
+func (YearCompare) condition() {}
+func (ISBNIs) condition()      {}
+
+type TextField int
+
+const (
+	FieldTitle TextField = iota
+	FieldPublisher
+	// FieldCreator holds the names of the authors
+	FieldCreator
+	// FieldSubject holds the genres
+	FieldSubject
+)
+
+type Comparison string
+
+const (
+	Equal          Comparison = "="
+	NotEqual       Comparison = "<>"
+	Less           Comparison = "<"
+	LessOrEqual    Comparison = "<="
+	Greater        Comparison = ">"
+	GreaterOrEqual Comparison = ">="
+)
+
+// Valid reports whether c is one of the comparisons above.
+func (c Comparison) Valid() bool {
+	switch c {
+	case Equal, NotEqual, Less, LessOrEqual, Greater, GreaterOrEqual:
+		return true
+	}
+	return false
+}
+
+// Ordering reports whether c is one of <, <=, > and >=.
+func (c Comparison) Ordering() bool {
+	return c.Valid() && c != Equal && c != NotEqual
+}
+
+// Compare applies c to the result of a three way comparison.
+func (c Comparison) Compare(cmp int) bool {
+	switch c {
+	case Equal:
+		return cmp == 0
+	case NotEqual:
+		return cmp != 0
+	case Less:
+		return cmp < 0
+	case LessOrEqual:
+		return cmp <= 0
+	case Greater:
+		return cmp > 0
+	case GreaterOrEqual:
+		return cmp >= 0
+	}
+	return false
+}
+
+// Pattern matches the whole text case insensitively, with * ? and \ escapes.
+type Pattern string
+
+// Like is the pattern in LIKE syntax, escaped with backslashes.
+func (p Pattern) Like() string {
+	var b strings.Builder
+
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch c {
+		case '\\':
+			if i+1 < len(p) {
+				i++
+				c = p[i]
+			}
+			if c == '%' || c == '_' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// Regexp is the pattern as a regular expression matching whole strings.
+func (p Pattern) Regexp() *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString(`(?is)^`)
+
+	runes := []rune(string(p))
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				r = runes[i]
+			}
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	b.WriteString(`$`)
+	return regexp.MustCompile(b.String())
+}
+
+// ErrUnsupported is returned for a condition a store does not know.
+var ErrUnsupported = errors.New("search condition not supported by this storage")
+
+const (
+	creatorExists = `EXISTS (SELECT 1 FROM book_author_link l JOIN authors au ON au.author_id = l.author_id WHERE l.book_id = b.book_id AND %s)`
+	subjectExists = `EXISTS (SELECT 1 FROM unnest(b.genres) AS g(genre) WHERE %s)`
+)
+
+// searchSQL writes a Condition over the books aliased b, values go in args.
+type searchSQL struct {
+	args []interface{}
+}
+
+func (s *searchSQL) arg(value interface{}) string {
+	s.args = append(s.args, value)
+	return fmt.Sprintf("$%d", len(s.args))
+}
+
+func (s *searchSQL) where(cond Condition) (string, error) {
+	switch c := cond.(type) {
+	case And:
+		return s.pair(c.Left, " AND ", c.Right)
+	case Or:
+		return s.pair(c.Left, " OR ", c.Right)
+	case Not:
+		inner, err := s.where(c.Condition)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + inner + ")", nil
+	case Everything:
+		return "TRUE", nil
+	case TextMatch:
+		return s.text(c.Field, func(col string) string {
+			return col + " ILIKE " + s.arg(c.Pattern.Like())
+		})
+	case TextCompare:
+		if !c.Op.Ordering() {
+			break
+		}
+		return s.text(c.Field, func(col string) string {
+			return "lower(" + col + ") " + string(c.Op) + " lower(" + s.arg(c.Value) + ")"
+		})
+	case YearCompare:
+		if !c.Op.Valid() {
+			break
+		}
+		return "b.year " + string(c.Op) + " " + s.arg(c.Year), nil
+	case ISBNIs:
+		return "b.isbn = " + s.arg(c.ISBN), nil
+	}
+
+	return "", fmt.Errorf("%w: %#v", ErrUnsupported, cond)
+}
+
+func (s *searchSQL) pair(left Condition, op string, right Condition) (string, error) {
+	l, err := s.where(left)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := s.where(right)
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + l + op + r + ")", nil
+}
+
+func (s *searchSQL) text(field TextField, match func(col string) string) (string, error) {
+	switch field {
+	case FieldTitle:
+		return match("b.title"), nil
+	case FieldPublisher:
+		return match("b.publisher"), nil
+	case FieldCreator:
+		return fmt.Sprintf(creatorExists, match("au.name")), nil
+	case FieldSubject:
+		return fmt.Sprintf(subjectExists, match("g.genre")), nil
+	}
+
+	return "", fmt.Errorf("%w: field %d", ErrUnsupported, field)
+}
+
+// Search returns a page of the books matching cond and their total.
+func (r *ReadEntryModel) Search(ctx context.Context, cond Condition, limit, offset int) ([]*ReadEntry, int, error) {
+	search := &searchSQL{}
+
+	where, err := search.where(cond)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	countQuery := fmt.Sprintf(`
+	SELECT count(*)
+	FROM books b
+	WHERE b.deleted_at IS NULL AND %s
+		AND EXISTS (SELECT 1 FROM book_author_link bal WHERE bal.book_id = b.book_id)
+	`, where)
+
+	total := 0
+
+	err = r.DB.QueryRowContext(ctx, countQuery, search.args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 || offset >= total {
+		return []*ReadEntry{}, total, nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		b.id,
+		b.book_id,
+		b.title,
+		b.publisher,
+		b.year,
+		b.page_count,
+		b.genres,
+		b.isbn,
+		b.version,
+		array_agg(a.id),
+		array_agg(a.name),
+		array_agg(a.author_id),
+		array_agg(a.books_authored),
+		GREATEST(b.updated_at, MAX(a.updated_at))
+	FROM
+		books b
+	JOIN
+		book_author_link bal ON b.book_id = bal.book_id
+	JOIN
+		authors a ON bal.author_id = a.author_id
+	WHERE
+		b.deleted_at IS NULL AND %s
+	GROUP BY
+		b.id
+	ORDER BY
+		b.id
+	LIMIT $%d OFFSET $%d
+	`, where, len(search.args)+1, len(search.args)+2)
+
+	args := append(append([]interface{}{}, search.args...), limit, offset)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []*ReadEntry{}
+
+	for rows.Next() {
+		entry := &ReadEntry{}
+
+		err := rows.Scan(
+			&entry.Book.ID,
+			&entry.Book.Hash,
+			&entry.Book.Title,
+			&entry.Book.Publisher,
+			&entry.Book.Year,
+			&entry.Book.PageCount,
+			pq.Array(&entry.Book.Genres),
+			&entry.Book.ISBN,
+			&entry.Book.Version,
+			pq.Array(&entry.List.ID),
+			pq.Array(&entry.List.Name),
+			pq.Array(&entry.List.Identifier),
+			pq.Array(&entry.List.Books_authored),
+			&entry.LastModified,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entry.Authors = make([]ReadAuthor, len(entry.List.Name))
+		entry.Convert()
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
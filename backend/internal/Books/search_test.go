@@ -0,0 +1,99 @@
+package books
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		pattern Pattern
+		like    string
+		matches []string
+		misses  []string
+	}{
+		{"*dune*", "%dune%", []string{"Dune", "Dune Messiah", "children of dune"}, []string{"Dun"}},
+		{"dune", "dune", []string{"DUNE"}, []string{"Dune Messiah"}},
+		{"h?perion", "h_perion", []string{"Hyperion"}, []string{"Hperion"}},
+		{"*50%*", `%50\%%`, []string{"The 50% Solution"}, []string{"The 500 Solution"}},
+		{"a_b", `a\_b`, []string{"a_b"}, []string{"axb"}},
+		{`dune\*`, "dune*", []string{"Dune*"}, []string{"Dune Messiah"}},
+		{`\?`, "?", []string{"?"}, []string{"x"}},
+		{`a\\*`, `a\\%`, []string{`a\b`}, []string{"ab"}},
+		{"*años*", "%años%", []string{"Cien Años de soledad"}, []string{"anos"}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pattern.Like(); got != tt.like {
+			t.Errorf("%s: got LIKE %q, want %q", tt.pattern, got, tt.like)
+		}
+
+		re := tt.pattern.Regexp()
+		for _, s := range tt.matches {
+			if !re.MatchString(s) {
+				t.Errorf("%s: does not match %q", tt.pattern, s)
+			}
+		}
+		for _, s := range tt.misses {
+			if re.MatchString(s) {
+				t.Errorf("%s: matches %q", tt.pattern, s)
+			}
+		}
+	}
+}
+
+func TestSearchSQL(t *testing.T) {
+	tests := []struct {
+		cond  Condition
+		where string
+		args  []interface{}
+	}{
+		{Everything{}, "TRUE", nil},
+		{
+			And{Left: TextMatch{Field: FieldTitle, Pattern: "*dune*"}, Right: Not{Condition: YearCompare{Op: Less, Year: 1965}}},
+			"(b.title ILIKE $1 AND NOT (b.year < $2))",
+			[]interface{}{"%dune%", 1965},
+		},
+		{
+			Or{Left: TextCompare{Field: FieldPublisher, Op: GreaterOrEqual, Value: "M"}, Right: ISBNIs{ISBN: "9780441013593"}},
+			"(lower(b.publisher) >= lower($1) OR b.isbn = $2)",
+			[]interface{}{"M", "9780441013593"},
+		},
+		{
+			TextMatch{Field: FieldCreator, Pattern: "herbert"},
+			"EXISTS (SELECT 1 FROM book_author_link l JOIN authors au ON au.author_id = l.author_id WHERE l.book_id = b.book_id AND au.name ILIKE $1)",
+			[]interface{}{"herbert"},
+		},
+		{
+			TextMatch{Field: FieldSubject, Pattern: "*fiction"},
+			"EXISTS (SELECT 1 FROM unnest(b.genres) AS g(genre) WHERE g.genre ILIKE $1)",
+			[]interface{}{"%fiction"},
+		},
+	}
+
+	for _, tt := range tests {
+		s := &searchSQL{}
+		where, err := s.where(tt.cond)
+		if err != nil {
+			t.Errorf("%#v: %v", tt.cond, err)
+			continue
+		}
+		if where != tt.where || !reflect.DeepEqual(s.args, tt.args) {
+			t.Errorf("%#v:\n got %s %v\nwant %s %v", tt.cond, where, s.args, tt.where, tt.args)
+		}
+	}
+
+	for _, cond := range []Condition{
+		nil,
+		TextCompare{Field: FieldTitle, Op: Equal, Value: "x"},
+		TextCompare{Field: FieldTitle, Op: "1=1 OR", Value: "x"},
+		YearCompare{Op: "; DROP", Year: 1},
+		TextMatch{Field: TextField(99), Pattern: "x"},
+		And{Left: Everything{}, Right: nil},
+	} {
+		if _, err := (&searchSQL{}).where(cond); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("%#v: got %v, want ErrUnsupported", cond, err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package books
+
+// entity names used for the audit log entries of this package
+const (
+	auditEntity       = "book"
+	authorAuditEntity = "author"
+)
+
+// Snapshot is a book as the audit log stores it.
+type Snapshot struct {
+	Title     string   `json:"title"`
+	Publisher string   `json:"publisher"`
+	Year      int32    `json:"year"`
+	PageCount int32    `json:"page_count"`
+	Genres    []string `json:"genres"`
+	ISBN      string   `json:"isbn,omitempty"`
+	Version   int32    `json:"version"`
+	Authors   []string `json:"authors"`
+}
+
+func (r *ReadEntry) Snapshot() Snapshot {
+	return Snapshot{
+		Title:     r.Book.Title,
+		Publisher: r.Book.Publisher,
+		Year:      r.Book.Year,
+		PageCount: r.Book.PageCount,
+		Genres:    append([]string(nil), r.Book.Genres...),
+		ISBN:      r.Book.ISBN,
+		Version:   r.Book.Version,
+		Authors:   append([]string(nil), r.List.Name...),
+	}
+}
+
+func (u *UpdateEntry) Snapshot(version int32) Snapshot {
+	var isbn string
+	if u.Book.ISBN != nil {
+		isbn = *u.Book.ISBN
+	}
+
+	return Snapshot{
+		Title:     *u.Book.Title,
+		Publisher: *u.Book.Publisher,
+		Year:      *u.Book.Year,
+		PageCount: *u.Book.PageCount,
+		Genres:    append([]string(nil), u.Book.Genres...),
+		ISBN:      isbn,
+		Version:   version,
+		Authors:   append([]string(nil), u.Author.Name...),
+	}
+}
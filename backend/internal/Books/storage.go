@@ -0,0 +1,121 @@
+package books
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+)
+
+/* The models below are the SQL implementation of these repositories, the
+memory and sqlite packages have others. */
+
+// Tx is a transaction of the store that began it.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+type ReadRepository interface {
+	Get(ctx context.Context, tx Tx, read *ReadEntry) error
+	GetMany(ctx context.Context, ids []int64) ([]*ReadEntry, error)
+	List(ctx context.Context, filter ListFilter, filters internal.Filters) ([]*ReadEntry, internal.Metadata, error)
+	GenreCounts(ctx context.Context) ([]*GenreCount, error)
+	Stream(ctx context.Context, filter ExportFilter, fn func(*ReadEntry) error) error
+	Search(ctx context.Context, cond Condition, limit, offset int) ([]*ReadEntry, int, error)
+	Harvest(ctx context.Context, filter HarvestFilter) ([]*HarvestRecord, error)
+	Genres(ctx context.Context) ([]string, error)
+	EarliestDatestamp(ctx context.Context) (time.Time, error)
+	Verify(ctx context.Context) ([]Problem, error)
+}
+
+type CreateRepository interface {
+	BeginTx(ctx context.Context) (Tx, error)
+	Insert(ctx context.Context, tx Tx, entry *CreateBookEntry, read *ReadEntry) error
+	Save(ctx context.Context, entry *CreateBookEntry, read *ReadEntry) error
+	ExistingHashes(ctx context.Context, hashes []string) (map[string]bool, error)
+}
+
+type UpdateRepository interface {
+	Save(ctx context.Context, entry *UpdateEntry, current *ReadEntry) error
+}
+
+type DeleteRepository interface {
+	BeginTx(ctx context.Context) (Tx, error)
+	DeleteBook(ctx context.Context, tx Tx, id *DeleteID) error
+	Restore(ctx context.Context, tx Tx, id *DeleteID) error
+	ListTrash(ctx context.Context, filters internal.Filters) ([]*TrashedBook, internal.Metadata, error)
+	Purge(ctx context.Context, tx Tx, id *DeleteID) error
+	PurgeDeletedBefore(ctx context.Context, tx Tx, before time.Time) (int64, error)
+}
+
+type AuthorRepository interface {
+	BeginTx(ctx context.Context) (Tx, error)
+	AuthorGet(ctx context.Context, tx Tx, read *ReadAuthorEntry) error
+	ListAuthors(ctx context.Context, filters internal.Filters) ([]*AuthorSummary, internal.Metadata, error)
+	BooksByAuthors(ctx context.Context, authorIDs []int64) (map[int64][]*ReadEntry, error)
+	MergeAuthors(ctx context.Context, tx Tx, into, from int64) (*ReadAuthor, error)
+}
+
+// LinkRepository links books and authors by their hashes.
+type LinkRepository interface {
+	BeginTx(ctx context.Context) (Tx, error)
+	Linked(ctx context.Context, tx Tx, bookHash string) ([]string, error)
+	Link(ctx context.Context, tx Tx, bookHash string, authorHashes []string) error
+	Unlink(ctx context.Context, tx Tx, bookHash string, authorHashes []string) error
+}
+
+type RevisionRepository interface {
+	List(ctx context.Context, bookID int64) ([]*Revision, error)
+	Get(ctx context.Context, bookID int64, version int32) (*Revision, error)
+	Previous(ctx context.Context, bookID int64, version int32) (*Revision, error)
+}
+
+func beginTx(ctx context.Context, db *sql.DB) (Tx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ErrForeignTx is returned for a transaction begun by another store.
+var ErrForeignTx = errors.New("books: the transaction was not begun by this store")
+
+// sqlTx unwraps a transaction begun by one of the SQL models.
+func sqlTx(tx Tx) (*sql.Tx, error) {
+	sqltx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrForeignTx, tx)
+	}
+	return sqltx, nil
+}
+
+func (c *CreateEntryModel) BeginTx(ctx context.Context) (Tx, error) {
+	return beginTx(ctx, c.DB)
+}
+
+func (del *DeleteEntryModel) BeginTx(ctx context.Context) (Tx, error) {
+	return beginTx(ctx, del.DB)
+}
+
+func (a *AuthorModel) BeginTx(ctx context.Context) (Tx, error) {
+	return beginTx(ctx, a.DB)
+}
+
+func (l *LinkModel) BeginTx(ctx context.Context) (Tx, error) {
+	return beginTx(ctx, l.DB)
+}
+
+var (
+	_ ReadRepository     = (*ReadEntryModel)(nil)
+	_ CreateRepository   = (*CreateEntryModel)(nil)
+	_ UpdateRepository   = (*UpdateEntryModel)(nil)
+	_ DeleteRepository   = (*DeleteEntryModel)(nil)
+	_ AuthorRepository   = (*AuthorModel)(nil)
+	_ LinkRepository     = (*LinkModel)(nil)
+	_ RevisionRepository = (*RevisionModel)(nil)
+)
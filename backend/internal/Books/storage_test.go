@@ -0,0 +1,54 @@
+package books_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/migrate"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/storetest"
+	"github.com/3WDeveloper-GM/library_app/migrations"
+)
+
+// The SQL models need a database of their own, LIBRARY_TEST_DSN names it.
+// Every case starts by migrating it down to nothing and back up.
+func TestModels(t *testing.T) {
+	dsn := os.Getenv("LIBRARY_TEST_DSN")
+	if dsn == "" {
+		t.Skip("LIBRARY_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	storetest.Run(t, func(t *testing.T) storetest.Repositories {
+		m, err := migrate.New(db, migrations.FS)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := m.Goto(context.Background(), 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Up(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		return storetest.Repositories{
+			Read:      &books.ReadEntryModel{DB: db},
+			Create:    &books.CreateEntryModel{DB: db},
+			Update:    &books.UpdateEntryModel{DB: db},
+			Delete:    &books.DeleteEntryModel{DB: db},
+			Authors:   &books.AuthorModel{DB: db},
+			Links:     &books.LinkModel{DB: db},
+			Revisions: &books.RevisionModel{DB: db},
+			Audit:     &audit.AuditModel{DB: db},
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package books
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/lib/pq"
+)
+
+type TrashedBook struct {
+	ID        int64     `json:"id"`
+	Hash      string    `json:"hash"`
+	Title     string    `json:"title"`
+	Publisher string    `json:"publisher"`
+	Year      int32     `json:"year"`
+	PageCount int32     `json:"page_count"`
+	Genres    []string  `json:"genres"`
+	Version   int32     `json:"version"`
+	Authors   []string  `json:"authors"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type purgedBook struct {
+	id    int64
+	Hash  string `json:"hash"`
+	Title string `json:"title"`
+}
+
+func (del *DeleteEntryModel) ListTrash(ctx context.Context, filters internal.Filters) ([]*TrashedBook, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		count(*) OVER(),
+		b.id,
+		b.book_id,
+		b.title,
+		b.publisher,
+		b.year,
+		b.page_count,
+		b.genres,
+		b.version,
+		b.deleted_at,
+		array_agg(a.name)
+	FROM
+		books b
+	JOIN
+		book_author_link bal ON b.book_id = bal.book_id
+	JOIN
+		authors a ON bal.author_id = a.author_id
+	WHERE
+		b.deleted_at IS NOT NULL
+	GROUP BY
+		b.id
+	ORDER BY
+		b.%s %s, b.id ASC
+	LIMIT $1 OFFSET $2
+	`, column, filters.SortDirection())
+
+	rows, err := del.DB.QueryContext(ctx, query, filters.Limit(), filters.Offset())
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	trashed := []*TrashedBook{}
+
+	for rows.Next() {
+		var book TrashedBook
+
+		err := rows.Scan(
+			&totalRecords,
+			&book.ID,
+			&book.Hash,
+			&book.Title,
+			&book.Publisher,
+			&book.Year,
+			&book.PageCount,
+			pq.Array(&book.Genres),
+			&book.Version,
+			&book.DeletedAt,
+			pq.Array(&book.Authors),
+		)
+		if err != nil {
+			return nil, internal.Metadata{}, err
+		}
+
+		trashed = append(trashed, &book)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return trashed, internal.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// Purge removes a trashed book and its links for good.
+func (del *DeleteEntryModel) Purge(ctx context.Context, t Tx, id *DeleteID) error {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM books
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING book_id, title
+	`
+
+	purged := purgedBook{id: id.ID}
+
+	err = tx.QueryRowContext(ctx, query, id.ID).Scan(&purged.Hash, &purged.Title)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	id.Hash = purged.Hash
+
+	return audit.Record(ctx, tx, auditEntity, id.ID, audit.ActionPurge, purged, nil)
+}
+
+func (del *DeleteEntryModel) PurgeDeletedBefore(ctx context.Context, t Tx, before time.Time) (int64, error) {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		DELETE FROM books
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		RETURNING id, book_id, title
+	`
+
+	rows, err := tx.QueryContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	purged := []purgedBook{}
+
+	for rows.Next() {
+		var book purgedBook
+
+		if err := rows.Scan(&book.id, &book.Hash, &book.Title); err != nil {
+			return 0, err
+		}
+
+		purged = append(purged, book)
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	//audit once the rows are drained
+	for _, book := range purged {
+		err = audit.Record(ctx, tx, auditEntity, book.id, audit.ActionPurge, book, nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(purged)), nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
 	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
 	"github.com/lib/pq"
 )
@@ -21,6 +22,8 @@ type UpdateBook struct {
 	Year      *int32   `json:"year"`
 	PageCount *int32   `json:"page_count"`
 	Genres    []string `json:"genres"`
+	ISBN      *string  `json:"isbn"`
+	Version   *int32   `json:"version"`
 }
 
 type UpdateAuthors struct {
@@ -61,12 +64,18 @@ type UpdateEntryModel struct {
 	DB *sql.DB
 }
 
-func (u *UpdateEntryModel) Update(ctx context.Context, tx *sql.Tx, entry *UpdateEntry, read *ReadEntry, hashOld, exclusiveNew, hashNew []string) error {
+func (u *UpdateEntryModel) Update(ctx context.Context, t Tx, entry *UpdateEntry, read *ReadEntry, hashOld, exclusiveNew, hashNew []string) error {
+	tx, err := sqlTx(t)
+	if err != nil {
+		return err
+	}
+	before := read.Snapshot()
+
 	query := `
 		UPDATE books
-		SET title = $1, publisher = $2, year = $3, page_count = $4, genres = $5, version = version + 1
-		WHERE id = $6
-		RETURNING book_id, title, publisher, year, page_count, genres, version
+		SET title = $1, publisher = $2, year = $3, page_count = $4, genres = $5, isbn = $8, version = version + 1, updated_at = NOW()
+		WHERE id = $6 AND version = $7 AND deleted_at IS NULL
+		RETURNING book_id, title, publisher, year, page_count, genres, isbn, version
 	`
 
 	args := []interface{}{
@@ -76,15 +85,18 @@ func (u *UpdateEntryModel) Update(ctx context.Context, tx *sql.Tx, entry *Update
 		entry.Book.PageCount,
 		pq.Array(entry.Book.Genres),
 		entry.Book.ID,
+		entry.Book.Version,
+		entry.Book.ISBN,
 	}
 
-	err := tx.QueryRowContext(ctx, query, args...).Scan(
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
 		&read.Book.Hash,
 		&read.Book.Title,
 		&read.Book.Publisher,
 		&read.Book.Year,
 		&read.Book.PageCount,
 		pq.Array(&read.Book.Genres),
+		&read.Book.ISBN,
 		&read.Book.Version,
 	)
 
@@ -108,7 +120,7 @@ func (u *UpdateEntryModel) Update(ctx context.Context, tx *sql.Tx, entry *Update
 			INSERT INTO authors(name,author_id,books_authored)
 			SELECT name, author_id, books_authored FROM filter1
 			ON CONFLICT (author_id) DO UPDATE
-			SET books_authored = authors.books_authored + 1
+			SET books_authored = authors.books_authored + 1, updated_at = NOW()
 			RETURNING id, name, author_id, books_authored
 		)
 		SELECT array_agg(id), array_agg(name), array_agg(author_id), array_agg(books_authored) FROM finished
@@ -125,14 +137,14 @@ func (u *UpdateEntryModel) Update(ctx context.Context, tx *sql.Tx, entry *Update
 			)
 
 		if err != nil {
-			return errors.New(err.Error() + " 2")
+			return fmt.Errorf("%w 2", err)
 		}
 	}
 
 	if len(hashOld) != 0 {
 		query = `
 		UPDATE authors
-		SET books_authored = books_authored - 1
+		SET books_authored = books_authored - 1, updated_at = NOW()
 		WHERE authors.author_id = ANY(
 			SELECT * FROM UNNEST($1::TEXT[]) as author_id
 		)
@@ -146,7 +158,7 @@ func (u *UpdateEntryModel) Update(ctx context.Context, tx *sql.Tx, entry *Update
 		if err != nil {
 			switch {
 			case errors.Is(err, sql.ErrNoRows):
-				return ErrEditConflict
+				return ErrAuthorsChanged
 			default:
 				return err
 			}
@@ -162,13 +174,13 @@ func (u *UpdateEntryModel) Update(ctx context.Context, tx *sql.Tx, entry *Update
 	result, err := tx.ExecContext(ctx, query, args2...)
 
 	if err != nil {
-		return errors.New(err.Error() + " 4")
+		return fmt.Errorf("%w 4", err)
 	}
 
 	rows, err := result.RowsAffected()
 
 	if err != nil {
-		return errors.New(err.Error() + " 5")
+		return fmt.Errorf("%w 5", err)
 	}
 
 	if rows == 0 {
@@ -191,11 +203,21 @@ func (u *UpdateEntryModel) Update(ctx context.Context, tx *sql.Tx, entry *Update
 
 	args3 := []interface{}{pq.Array([]string{*entry.Book.Hash}), pq.Array(entry.Author.Hash)}
 
-	return tx.QueryRowContext(ctx, query, args3...).Scan(
+	err = tx.QueryRowContext(ctx, query, args3...).Scan(
 		pq.Array(read.List.Name),
 		pq.Array(read.List.Identifier),
 	)
+	if err != nil {
+		return err
+	}
 
+	after := entry.Snapshot(read.Book.Version)
+
+	if err := recordRevision(ctx, tx, read.Book.ID, after); err != nil {
+		return err
+	}
+
+	return audit.Record(ctx, tx, auditEntity, read.Book.ID, audit.ActionUpdate, before, after)
 }
 
 func (b *UpdateEntry) ValidateEntry(v *validator.Validator) bool {
@@ -263,6 +285,11 @@ func (b *UpdateEntry) ValidateEntry(v *validator.Validator) bool {
 		"%s field must contain at least %d genres", section, minGenreCount,
 	))
 
+	section = "isbn"
+	v.Check(b.Book.ISBN == nil || *b.Book.ISBN == "" || ValidISBN(*b.Book.ISBN), section, fmt.Sprintf(
+		"%s field must be a valid ISBN-10 or ISBN-13", section,
+	))
+
 	if !v.Valid() {
 		return false
 	}
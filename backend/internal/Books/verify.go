@@ -0,0 +1,145 @@
+package books
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+)
+
+// Problem is an inconsistency found by Verify.
+type Problem struct {
+	Check  string `json:"check"`
+	Entity string `json:"entity"`
+	ID     int64  `json:"id"`
+	Detail string `json:"detail"`
+}
+
+var verifyChecks = []struct {
+	name   string
+	entity string
+	query  string
+}{
+	{
+		name:   "author_counter",
+		entity: "author",
+		query: `
+		SELECT a.id, format('books_authored is %s, %s books are linked', a.books_authored, count(b.id))
+		FROM authors a
+		LEFT JOIN book_author_link bal ON bal.author_id = a.author_id
+		LEFT JOIN books b ON b.book_id = bal.book_id AND b.deleted_at IS NULL
+		GROUP BY a.id, a.books_authored
+		HAVING a.books_authored != count(b.id)
+		ORDER BY a.id`,
+	},
+	{
+		name:   "orphan_author",
+		entity: "author",
+		query: `
+		SELECT a.id, 'no book links to the author'
+		FROM authors a
+		WHERE NOT EXISTS (SELECT 1 FROM book_author_link bal WHERE bal.author_id = a.author_id)
+		ORDER BY a.id`,
+	},
+	{
+		name:   "book_without_authors",
+		entity: "book",
+		query: `
+		SELECT b.id, 'the book has no authors'
+		FROM books b
+		WHERE NOT EXISTS (SELECT 1 FROM book_author_link bal WHERE bal.book_id = b.book_id)
+		ORDER BY b.id`,
+	},
+	{
+		name:   "missing_revision",
+		entity: "book",
+		query: `
+		SELECT b.id, format('no revision for version %s', b.version)
+		FROM books b
+		WHERE NOT EXISTS (
+			SELECT 1 FROM book_revisions r WHERE r.book_id = b.id AND r.version = b.version
+		)
+		ORDER BY b.id`,
+	},
+}
+
+// Verify runs the consistency checks, trashed books included.
+func (r *ReadEntryModel) Verify(ctx context.Context) ([]Problem, error) {
+	problems := []Problem{}
+
+	for _, check := range verifyChecks {
+		rows, err := r.DB.QueryContext(ctx, check.query)
+		if err != nil {
+			return nil, fmt.Errorf("verify %s: %w", check.name, err)
+		}
+
+		for rows.Next() {
+			problem := Problem{Check: check.name, Entity: check.entity}
+			if err := rows.Scan(&problem.ID, &problem.Detail); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			problems = append(problems, problem)
+		}
+
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	hashes, err := r.verifyHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(problems, hashes...), nil
+}
+
+// see HashEntries
+var hashChecks = []struct {
+	name   string
+	entity string
+	query  string
+}{
+	{"book_hash", "book", `SELECT id, book_id, title FROM books ORDER BY id`},
+	{"author_hash", "author", `SELECT id, author_id, name FROM authors ORDER BY id`},
+}
+
+func (r *ReadEntryModel) verifyHashes(ctx context.Context) ([]Problem, error) {
+	problems := []Problem{}
+
+	for _, check := range hashChecks {
+		rows, err := r.DB.QueryContext(ctx, check.query)
+		if err != nil {
+			return nil, fmt.Errorf("verify %s: %w", check.name, err)
+		}
+
+		for rows.Next() {
+			var id int64
+			var hash, source string
+
+			if err := rows.Scan(&id, &hash, &source); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			sum := sha1.Sum([]byte(source))
+			if want := hex.EncodeToString(sum[:]); hash != want {
+				problems = append(problems, Problem{
+					Check:  check.name,
+					Entity: check.entity,
+					ID:     id,
+					Detail: fmt.Sprintf("hash %s does not match %q, expected %s", hash, source, want),
+				})
+			}
+		}
+
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return problems, nil
+}
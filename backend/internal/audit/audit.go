@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+)
+
+const (
+	ActionInsert  = "insert"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+	ActionMerge   = "merge"
+)
+
+type Meta struct {
+	Actor     string
+	RequestID string
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, contextKey{}, meta)
+}
+
+// FromContext falls back on the system actor outside of requests.
+func FromContext(ctx context.Context) Meta {
+	meta, ok := ctx.Value(contextKey{}).(Meta)
+	if !ok || meta.Actor == "" {
+		meta.Actor = "system"
+	}
+	return meta
+}
+
+type Entry struct {
+	ID        int64           `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id"`
+	Entity    string          `json:"entity"`
+	EntityID  int64           `json:"entity_id"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+// Record appends an entry inside the transaction of the mutation.
+func Record(ctx context.Context, tx *sql.Tx, entity string, entityID int64, action string, before, after interface{}) error {
+	meta := FromContext(ctx)
+
+	beforeJS, err := marshalState(before)
+	if err != nil {
+		return err
+	}
+
+	afterJS, err := marshalState(after)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_log(actor, request_id, entity, entity_id, action, before, after)
+		VALUES($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = tx.ExecContext(ctx, query, meta.Actor, meta.RequestID, entity, entityID, action, beforeJS, afterJS)
+	return err
+}
+
+// the json goes over the wire as text, lib/pq would send a []byte as bytea
+func marshalState(state interface{}) (sql.NullString, error) {
+	if state == nil {
+		return sql.NullString{}, nil
+	}
+
+	js, err := json.Marshal(state)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(js), Valid: true}, nil
+}
+
+type Filter struct {
+	Actor     string
+	Entity    string
+	EntityID  int64
+	Action    string
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Repository reads the audit log, AuditModel is the SQL one.
+type Repository interface {
+	List(ctx context.Context, filter Filter, filters internal.Filters) ([]*Entry, internal.Metadata, error)
+}
+
+type AuditModel struct {
+	DB *sql.DB
+}
+
+func (m *AuditModel) List(ctx context.Context, filter Filter, filters internal.Filters) ([]*Entry, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		count(*) OVER(), id, created_at, actor, request_id, entity, entity_id, action, before, after
+	FROM
+		audit_log
+	WHERE
+		($1 = '' OR actor = $1)
+		AND ($2 = '' OR entity = $2)
+		AND ($3 = 0 OR entity_id = $3)
+		AND ($4 = '' OR action = $4)
+		AND ($5 = '' OR request_id = $5)
+		AND ($6::timestamptz IS NULL OR created_at >= $6)
+		AND ($7::timestamptz IS NULL OR created_at < $7)
+	ORDER BY
+		%s %s, id ASC
+	LIMIT $8 OFFSET $9
+	`, column, filters.SortDirection())
+
+	args := []interface{}{
+		filter.Actor,
+		filter.Entity,
+		filter.EntityID,
+		filter.Action,
+		filter.RequestID,
+		sql.NullTime{Time: filter.Since, Valid: !filter.Since.IsZero()},
+		sql.NullTime{Time: filter.Until, Valid: !filter.Until.IsZero()},
+		filters.Limit(),
+		filters.Offset(),
+	}
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	entries := []*Entry{}
+
+	for rows.Next() {
+		var entry Entry
+		var before, after []byte
+
+		err := rows.Scan(
+			&totalRecords,
+			&entry.ID,
+			&entry.CreatedAt,
+			&entry.Actor,
+			&entry.RequestID,
+			&entry.Entity,
+			&entry.EntityID,
+			&entry.Action,
+			&before,
+			&after,
+		)
+		if err != nil {
+			return nil, internal.Metadata{}, err
+		}
+
+		entry.Before = before
+		entry.After = after
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return entries, internal.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
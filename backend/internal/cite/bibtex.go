@@ -0,0 +1,117 @@
+package cite
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`%`, `\%`,
+	`&`, `\&`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`^`, `\^{}`,
+	`~`, `\~{}`,
+)
+
+func bibtexEscape(s string) string {
+	return bibtexEscaper.Replace(oneLine(s))
+}
+
+func renderBibTeX(works []*Work) ([]byte, error) {
+	var buf bytes.Buffer
+	used := make(map[string]int)
+
+	for i, w := range works {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		key := bibtexKey(w)
+		used[key]++
+		if n := used[key]; n > 1 {
+			// herbert1965dune, herbert1965duneb, ...
+			key += string(rune('a' + n - 1))
+		}
+
+		fmt.Fprintf(&buf, "@book{%s,\n", key)
+
+		authors := []string{}
+		for _, name := range w.Authors() {
+			// a literal "and" inside a name would split it
+			authors = append(authors, strings.ReplaceAll(bibtexEscape(name.Inverted()), " and ", " {and} "))
+		}
+		if len(authors) > 0 {
+			fmt.Fprintf(&buf, "  author = {%s},\n", strings.Join(authors, " and "))
+		}
+
+		// double braces keep the capitalisation of the title
+		fmt.Fprintf(&buf, "  title = {{%s}},\n", bibtexEscape(w.Title))
+
+		if w.Publisher != "" {
+			fmt.Fprintf(&buf, "  publisher = {%s},\n", bibtexEscape(w.Publisher))
+		}
+		if w.Year != 0 {
+			fmt.Fprintf(&buf, "  year = {%d},\n", w.Year)
+		}
+		if w.ISBN != "" {
+			fmt.Fprintf(&buf, "  isbn = {%s},\n", w.ISBN)
+		}
+		if w.Pages > 0 {
+			fmt.Fprintf(&buf, "  pagetotal = {%d},\n", w.Pages)
+		}
+		if len(w.Keywords) > 0 {
+			keywords := make([]string, len(w.Keywords))
+			for i, k := range w.Keywords {
+				keywords[i] = bibtexEscape(k)
+			}
+			fmt.Fprintf(&buf, "  keywords = {%s},\n", strings.Join(keywords, ", "))
+		}
+
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// bibtexKey builds an ASCII family-year-word key, or falls back on the id.
+func bibtexKey(w *Work) string {
+	var b strings.Builder
+
+	if authors := w.Authors(); len(authors) > 0 {
+		b.WriteString(keyPart(authors[0].Family))
+	}
+
+	if w.Year != 0 {
+		fmt.Fprintf(&b, "%d", w.Year)
+	}
+
+	for _, word := range strings.Fields(w.Title) {
+		if part := keyPart(word); len(part) > 3 || (part != "" && b.Len() == 0) {
+			b.WriteString(part)
+			break
+		}
+	}
+
+	if b.Len() == 0 {
+		return fmt.Sprintf("book%d", w.ID)
+	}
+
+	return b.String()
+}
+
+func keyPart(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
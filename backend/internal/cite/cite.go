@@ -0,0 +1,102 @@
+package cite
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+// Work is the data every style is rendered from.
+type Work struct {
+	ID           int64
+	Title        string
+	Contributors []Contributor
+	Publisher    string
+	Year         int32
+	ISBN         string
+	Pages        int32
+	Keywords     []string
+}
+
+func FromEntry(entry *books.ReadEntry) *Work {
+	w := &Work{
+		ID:        entry.Book.ID,
+		Title:     strings.TrimSpace(entry.Book.Title),
+		Publisher: strings.TrimSpace(entry.Book.Publisher),
+		Year:      entry.Book.Year,
+		ISBN:      entry.Book.ISBN,
+		Pages:     entry.Book.PageCount,
+		Keywords:  entry.Book.Genres,
+	}
+
+	for _, name := range entry.List.Name {
+		w.Contributors = append(w.Contributors, Contributor{Name: ParseName(name), Role: "author"})
+	}
+
+	return w
+}
+
+// Authors returns the contributors with the author role, in order.
+func (w *Work) Authors() []Name {
+	names := []Name{}
+	for _, c := range w.Contributors {
+		if c.Role == "author" {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+type Format struct {
+	Name        string
+	ContentType string
+	Render      func(works []*Work) ([]byte, error)
+}
+
+var formats = map[string]Format{}
+
+func Register(f Format) {
+	formats[f.Name] = f
+}
+
+func Lookup(name string) (Format, bool) {
+	f, ok := formats[name]
+	return f, ok
+}
+
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func UnknownFormatError(name string) error {
+	return fmt.Errorf("unsupported format %q, expected one of %s", name, strings.Join(Names(), ", "))
+}
+
+func init() {
+	Register(Format{Name: "bibtex", ContentType: "application/x-bibtex; charset=utf-8", Render: renderBibTeX})
+	Register(Format{Name: "ris", ContentType: "application/x-research-info-systems; charset=utf-8", Render: renderRIS})
+	Register(Format{Name: "csljson", ContentType: "application/vnd.citationstyles.csl+json", Render: renderCSLJSON})
+	Register(Format{Name: "apa", ContentType: "text/plain; charset=utf-8", Render: renderText(apa)})
+	Register(Format{Name: "mla", ContentType: "text/plain; charset=utf-8", Render: renderText(mla)})
+	Register(Format{Name: "chicago", ContentType: "text/plain; charset=utf-8", Render: renderText(chicago)})
+}
+
+func year(w *Work) string {
+	if w.Year == 0 {
+		return "n.d."
+	}
+	return strconv.Itoa(int(w.Year))
+}
+
+// oneLine keeps user data from breaking line based formats.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
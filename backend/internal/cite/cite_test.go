@@ -0,0 +1,186 @@
+package cite_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/cite"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		raw      string
+		want     cite.Name
+		inverted string
+		initials string
+	}{
+		{"Frank Herbert", cite.Name{Given: "Frank", Family: "Herbert"}, "Herbert, Frank", "F."},
+		{"FRANK HERBERT", cite.Name{Given: "Frank", Family: "Herbert"}, "Herbert, Frank", "F."},
+		{"Herbert, Frank", cite.Name{Given: "Frank", Family: "Herbert"}, "Herbert, Frank", "F."},
+		{"Ludwig van Beethoven", cite.Name{Given: "Ludwig", Particle: "van", Family: "Beethoven"}, "van Beethoven, Ludwig", "L."},
+		{"LUDWIG VAN BEETHOVEN", cite.Name{Given: "Ludwig", Particle: "van", Family: "Beethoven"}, "van Beethoven, Ludwig", "L."},
+		{"Martin Luther King Jr.", cite.Name{Given: "Martin Luther", Family: "King", Suffix: "Jr."}, "King, Martin Luther, Jr.", "M. L."},
+		{"King, Martin Luther, Jr.", cite.Name{Given: "Martin Luther", Family: "King", Suffix: "Jr."}, "King, Martin Luther, Jr.", "M. L."},
+		{"Jean-Paul Sartre", cite.Name{Given: "Jean-Paul", Family: "Sartre"}, "Sartre, Jean-Paul", "J.-P."},
+		{"J. R. R. Tolkien", cite.Name{Given: "J. R. R.", Family: "Tolkien"}, "Tolkien, J. R. R.", "J. R. R."},
+		{"JOHN O'BRIEN", cite.Name{Given: "John", Family: "O'Brien"}, "O'Brien, John", "J."},
+		{"Homer", cite.Name{Family: "Homer"}, "Homer", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got := cite.ParseName(tt.raw)
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if inverted := got.Inverted(); inverted != tt.inverted {
+				t.Errorf("got inverted %q, want %q", inverted, tt.inverted)
+			}
+			if initials := got.Initials(); initials != tt.initials {
+				t.Errorf("got initials %q, want %q", initials, tt.initials)
+			}
+		})
+	}
+}
+
+func works() []*cite.Work {
+	return []*cite.Work{
+		{
+			ID:    1,
+			Title: "Good Omens",
+			Contributors: []cite.Contributor{
+				{Name: cite.ParseName("TERRY PRATCHETT"), Role: "author"},
+				{Name: cite.ParseName("NEIL GAIMAN"), Role: "author"},
+			},
+			Publisher: "Gollancz",
+			Year:      1990,
+			ISBN:      "9780575048003",
+			Pages:     288,
+			Keywords:  []string{"fantasy", "humour"},
+		},
+		{
+			ID:        2,
+			Title:     "100% {Braces}\nand_more",
+			Publisher: "A & B",
+		},
+	}
+}
+
+func render(t *testing.T, format string) string {
+	t.Helper()
+
+	f, ok := cite.Lookup(format)
+	if !ok {
+		t.Fatalf("%s is not registered", format)
+	}
+
+	out, err := f.Render(works())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"bibtex", "@book{pratchett1990good,\n" +
+			"  author = {Pratchett, Terry and Gaiman, Neil},\n" +
+			"  title = {{Good Omens}},\n" +
+			"  publisher = {Gollancz},\n" +
+			"  year = {1990},\n" +
+			"  isbn = {9780575048003},\n" +
+			"  pagetotal = {288},\n" +
+			"  keywords = {fantasy, humour},\n" +
+			"}\n" +
+			"\n" +
+			"@book{100,\n" +
+			"  title = {{100\\% \\{Braces\\} and\\_more}},\n" +
+			"  publisher = {A \\& B},\n" +
+			"}\n"},
+		{"ris", "TY  - BOOK\r\n" +
+			"ID  - book1\r\n" +
+			"AU  - Pratchett, Terry\r\n" +
+			"AU  - Gaiman, Neil\r\n" +
+			"TI  - Good Omens\r\n" +
+			"PB  - Gollancz\r\n" +
+			"PY  - 1990\r\n" +
+			"SN  - 9780575048003\r\n" +
+			"KW  - fantasy\r\n" +
+			"KW  - humour\r\n" +
+			"ER  - \r\n" +
+			"TY  - BOOK\r\n" +
+			"ID  - book2\r\n" +
+			"TI  - 100% {Braces} and_more\r\n" +
+			"PB  - A & B\r\n" +
+			"ER  - \r\n"},
+		{"apa", "Pratchett, T., & Gaiman, N. (1990). Good Omens. Gollancz.\n" +
+			"\n" +
+			"100% {Braces} and_more. (n.d.). A & B.\n"},
+		{"mla", "Pratchett, Terry, and Neil Gaiman. Good Omens. Gollancz, 1990.\n" +
+			"\n" +
+			"100% {Braces} and_more. A & B.\n"},
+		{"chicago", "Pratchett, Terry, and Neil Gaiman. Good Omens. Gollancz, 1990.\n" +
+			"\n" +
+			"100% {Braces} and_more. A & B.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := render(t, tt.format); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderCSLJSON(t *testing.T) {
+	var items []map[string]any
+	if err := json.Unmarshal([]byte(render(t, "csljson")), &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	first := items[0]
+	if first["type"] != "book" || first["title"] != "Good Omens" || first["publisher"] != "Gollancz" {
+		t.Errorf("got item %v", first)
+	}
+
+	authors, _ := first["author"].([]any)
+	if len(authors) != 2 {
+		t.Fatalf("got authors %v", first["author"])
+	}
+	if author := authors[0].(map[string]any); author["family"] != "Pratchett" || author["given"] != "Terry" {
+		t.Errorf("got author %v", author)
+	}
+}
+
+func TestBibTeXKeysAreUnique(t *testing.T) {
+	w := works()[0]
+	f, _ := cite.Lookup("bibtex")
+
+	out, err := f.Render([]*cite.Work{w, w, w})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"@book{pratchett1990good,", "@book{pratchett1990goodb,", "@book{pratchett1990goodc,"} {
+		if !strings.Contains(string(out), key) {
+			t.Errorf("%s is missing from\n%s", key, out)
+		}
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, ok := cite.Lookup("endnote"); ok {
+		t.Fatal("endnote is registered")
+	}
+	if err := cite.UnknownFormatError("endnote"); !strings.Contains(err.Error(), "apa, bibtex, chicago, csljson, mla, ris") {
+		t.Errorf("got error %v", err)
+	}
+}
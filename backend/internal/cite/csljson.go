@@ -0,0 +1,76 @@
+package cite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type cslName struct {
+	Family   string `json:"family,omitempty"`
+	Given    string `json:"given,omitempty"`
+	Particle string `json:"non-dropping-particle,omitempty"`
+	Suffix   string `json:"suffix,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+type cslItem struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title"`
+	Author        []cslName `json:"author,omitempty"`
+	Publisher     string    `json:"publisher,omitempty"`
+	Issued        *cslDate  `json:"issued,omitempty"`
+	ISBN          string    `json:"ISBN,omitempty"`
+	NumberOfPages string    `json:"number-of-pages,omitempty"`
+	Keyword       string    `json:"keyword,omitempty"`
+}
+
+// CSL-JSON is always an array, even for a single book.
+func renderCSLJSON(works []*Work) ([]byte, error) {
+	items := make([]cslItem, 0, len(works))
+
+	for _, w := range works {
+		item := cslItem{
+			ID:        fmt.Sprintf("book%d", w.ID),
+			Type:      "book",
+			Title:     w.Title,
+			Publisher: w.Publisher,
+			ISBN:      w.ISBN,
+		}
+
+		for _, name := range w.Authors() {
+			item.Author = append(item.Author, cslName{Family: name.Family, Given: name.Given, Particle: name.Particle, Suffix: name.Suffix})
+		}
+
+		if w.Year != 0 {
+			item.Issued = &cslDate{DateParts: [][]int{{int(w.Year)}}}
+		}
+		if w.Pages > 0 {
+			item.NumberOfPages = strconv.Itoa(int(w.Pages))
+		}
+		for i, keyword := range w.Keywords {
+			if i > 0 {
+				item.Keyword += ", "
+			}
+			item.Keyword += keyword
+		}
+
+		items = append(items, item)
+	}
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(items); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
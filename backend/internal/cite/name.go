@@ -0,0 +1,166 @@
+package cite
+
+import (
+	"strings"
+)
+
+// Name is a personal name split the way citation styles need it.
+type Name struct {
+	Given    string
+	Particle string
+	Family   string
+	Suffix   string
+}
+
+// Contributor is a name with its role, the catalogue only records authors.
+type Contributor struct {
+	Name
+	Role string
+}
+
+var particles = map[string]bool{
+	"da": true, "de": true, "del": true, "della": true, "der": true, "di": true,
+	"du": true, "la": true, "le": true, "van": true, "von": true, "den": true,
+}
+
+var suffixes = map[string]bool{
+	"jr": true, "jr.": true, "sr": true, "sr.": true, "ii": true, "iii": true, "iv": true,
+}
+
+// ParseName reads "Family, Given", "Family, Given, Suffix" or "Given Family".
+func ParseName(raw string) Name {
+	raw = strings.Join(strings.Fields(raw), " ")
+
+	if !isUpper(raw) {
+		return parseName(raw)
+	}
+
+	// particles of a recased name are guessed to be lower case
+	n := parseName(titleCase(raw))
+	n.Particle = strings.ToLower(n.Particle)
+
+	return n
+}
+
+func parseName(raw string) Name {
+	var n Name
+
+	if family, rest, ok := strings.Cut(raw, ","); ok {
+		given, suffix, _ := strings.Cut(rest, ",")
+		n.Given = strings.TrimSpace(given)
+		n.Suffix = strings.TrimSpace(suffix)
+		n.Particle, n.Family = splitParticle(strings.Fields(family))
+		return n
+	}
+
+	words := strings.Fields(raw)
+
+	if len(words) > 1 && suffixes[strings.ToLower(words[len(words)-1])] {
+		n.Suffix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	if len(words) <= 1 {
+		n.Family = strings.Join(words, " ")
+		return n
+	}
+
+	// the family name starts at the first particle, or is the last word
+	start := len(words) - 1
+	for i := 1; i < len(words)-1; i++ {
+		if particles[strings.ToLower(words[i])] {
+			start = i
+			break
+		}
+	}
+
+	n.Given = strings.Join(words[:start], " ")
+	n.Particle, n.Family = splitParticle(words[start:])
+
+	return n
+}
+
+func splitParticle(words []string) (string, string) {
+	i := 0
+	for i < len(words)-1 && particles[strings.ToLower(words[i])] {
+		i++
+	}
+	return strings.Join(words[:i], " "), strings.Join(words[i:], " ")
+}
+
+// FamilyWithParticle is the family name as it is sorted and inverted.
+func (n Name) FamilyWithParticle() string {
+	if n.Particle == "" {
+		return n.Family
+	}
+	return n.Particle + " " + n.Family
+}
+
+// Inverted is "Family, Given, Suffix".
+func (n Name) Inverted() string {
+	out := n.FamilyWithParticle()
+	if n.Given != "" {
+		out += ", " + n.Given
+	}
+	if n.Suffix != "" {
+		out += ", " + n.Suffix
+	}
+	return out
+}
+
+// Natural is "Given Family Suffix".
+func (n Name) Natural() string {
+	parts := []string{}
+	if n.Given != "" {
+		parts = append(parts, n.Given)
+	}
+	parts = append(parts, n.FamilyWithParticle())
+	out := strings.Join(parts, " ")
+	if n.Suffix != "" {
+		out += ", " + n.Suffix
+	}
+	return out
+}
+
+// Initials abbreviates the given names, "Jean-Paul Marie" giving "J.-P. M.".
+func (n Name) Initials() string {
+	words := strings.Fields(n.Given)
+	out := make([]string, 0, len(words))
+
+	for _, word := range words {
+		parts := strings.Split(word, "-")
+		for i, part := range parts {
+			r := []rune(part)
+			if len(r) == 0 {
+				continue
+			}
+			if len(r) == 2 && r[1] == '.' {
+				parts[i] = part
+				continue
+			}
+			parts[i] = string(r[0]) + "."
+		}
+		out = append(out, strings.Join(parts, "-"))
+	}
+
+	return strings.Join(out, " ")
+}
+
+func isUpper(s string) bool {
+	return strings.ToUpper(s) == s && strings.ToLower(s) != s
+}
+
+// titleCase lowers every letter that does not start a word.
+func titleCase(s string) string {
+	out := []rune(strings.ToLower(s))
+	start := true
+
+	for i, r := range out {
+		if start {
+			out[i] = []rune(strings.ToUpper(string(r)))[0]
+		}
+		start = r == ' ' || r == '-' || r == '\'' || r == '.'
+	}
+
+	return string(out)
+}
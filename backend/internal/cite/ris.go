@@ -0,0 +1,40 @@
+package cite
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// RIS records are CRLF ended tag lines.
+func renderRIS(works []*Work) ([]byte, error) {
+	var buf bytes.Buffer
+
+	line := func(tag, value string) {
+		if value = oneLine(value); value != "" {
+			fmt.Fprintf(&buf, "%s  - %s\r\n", tag, value)
+		}
+	}
+
+	for _, w := range works {
+		buf.WriteString("TY  - BOOK\r\n")
+		line("ID", fmt.Sprintf("book%d", w.ID))
+
+		for _, name := range w.Authors() {
+			line("AU", name.Inverted())
+		}
+
+		line("TI", w.Title)
+		line("PB", w.Publisher)
+		if w.Year != 0 {
+			line("PY", fmt.Sprintf("%d", w.Year))
+		}
+		line("SN", w.ISBN)
+		for _, keyword := range w.Keywords {
+			line("KW", keyword)
+		}
+
+		buf.WriteString("ER  - \r\n")
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,135 @@
+package cite
+
+import (
+	"bytes"
+	"strings"
+)
+
+// plain text entries, one paragraph each
+func renderText(style func(w *Work) string) func(works []*Work) ([]byte, error) {
+	return func(works []*Work) ([]byte, error) {
+		var buf bytes.Buffer
+		for i, w := range works {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(style(w))
+			buf.WriteString("\n")
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// sentence appends s and a period unless s already ends a sentence.
+func sentence(b *strings.Builder, s string) {
+	s = strings.TrimSpace(oneLine(s))
+	if s == "" {
+		return
+	}
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(s)
+	if !strings.HasSuffix(s, ".") && !strings.HasSuffix(s, "?") && !strings.HasSuffix(s, "!") {
+		b.WriteByte('.')
+	}
+}
+
+// apa follows APA 7: Family, I., & Family, I. (Year). Title. Publisher.
+func apa(w *Work) string {
+	var b strings.Builder
+
+	names := []string{}
+	for _, name := range w.Authors() {
+		n := name.FamilyWithParticle()
+		if initials := name.Initials(); initials != "" {
+			n += ", " + initials
+		}
+		if name.Suffix != "" {
+			n += ", " + name.Suffix
+		}
+		names = append(names, n)
+	}
+
+	switch {
+	case len(names) == 0:
+		// without an author the title takes its place
+		sentence(&b, w.Title)
+		b.WriteString(" (" + year(w) + ").")
+		sentence(&b, w.Publisher)
+		return b.String()
+	case len(names) > 20:
+		names = append(append(names[:19:19], "..."), names[len(names)-1])
+		b.WriteString(strings.Join(names[:20], ", ") + " " + names[20])
+	case len(names) == 1:
+		b.WriteString(names[0])
+	default:
+		b.WriteString(strings.Join(names[:len(names)-1], ", ") + ", & " + names[len(names)-1])
+	}
+
+	b.WriteString(" (" + year(w) + ").")
+	sentence(&b, w.Title)
+	sentence(&b, w.Publisher)
+
+	return b.String()
+}
+
+// mla follows MLA 9: Family, Given, and Given Family. Title. Publisher, Year.
+func mla(w *Work) string {
+	var b strings.Builder
+
+	authors := w.Authors()
+	switch {
+	case len(authors) == 1:
+		sentence(&b, authors[0].Inverted())
+	case len(authors) == 2:
+		sentence(&b, authors[0].Inverted()+", and "+authors[1].Natural())
+	case len(authors) > 2:
+		sentence(&b, authors[0].Inverted()+", et al")
+	}
+
+	sentence(&b, w.Title)
+	sentence(&b, publication(w))
+
+	return b.String()
+}
+
+// chicago follows the Chicago 17 bibliography entry.
+func chicago(w *Work) string {
+	var b strings.Builder
+
+	authors := w.Authors()
+	names := []string{}
+	for i, name := range authors {
+		if i == 0 {
+			names = append(names, name.Inverted())
+			continue
+		}
+		names = append(names, name.Natural())
+	}
+
+	switch {
+	case len(names) == 1:
+		sentence(&b, names[0])
+	case len(names) > 10:
+		sentence(&b, strings.Join(names[:7], ", ")+", et al")
+	case len(names) > 1:
+		sentence(&b, strings.Join(names[:len(names)-1], ", ")+", and "+names[len(names)-1])
+	}
+
+	sentence(&b, w.Title)
+	sentence(&b, publication(w))
+
+	return b.String()
+}
+
+func publication(w *Work) string {
+	parts := []string{}
+	if w.Publisher != "" {
+		parts = append(parts, oneLine(w.Publisher))
+	}
+	if w.Year != 0 {
+		parts = append(parts, year(w))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,117 @@
+package cql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenSlash
+	tokenComparitor
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// SyntaxError carries the byte offset of the error.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("cql: %s at position %d", e.Msg, e.Pos)
+}
+
+func lex(query string) ([]token, error) {
+	tokens := []token{}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+
+		case c == '/':
+			tokens = append(tokens, token{kind: tokenSlash, value: "/", pos: i})
+			i++
+
+		case c == '=' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(query) {
+				two := query[i : i+2]
+				if two == "==" || two == "<>" || two == "<=" || two == ">=" {
+					op = two
+				}
+			}
+			tokens = append(tokens, token{kind: tokenComparitor, value: op, pos: i})
+			i += len(op)
+
+		case c == '"':
+			value, end, err := lexString(query, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: i})
+			i = end
+
+		default:
+			start := i
+			for i < len(query) && !isDelimiter(query[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: query[start:i], pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(query)}), nil
+}
+
+// lexString reads a quoted term, only escaped quotes lose their backslash.
+func lexString(query string, start int) (string, int, error) {
+	var b strings.Builder
+
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if i+1 >= len(query) {
+				return "", 0, &SyntaxError{Pos: i, Msg: "dangling escape"}
+			}
+			if query[i+1] != '"' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(query[i+1])
+			i++
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(query[i])
+		}
+	}
+
+	return "", 0, &SyntaxError{Pos: start, Msg: "unterminated string"}
+}
+
+func isDelimiter(c byte) bool {
+	return unicode.IsSpace(rune(c)) || strings.IndexByte(`()=<>/"`, c) >= 0
+}
@@ -0,0 +1,222 @@
+package cql
+
+import (
+	"strings"
+)
+
+// Node is either a *Clause or a *Boolean.
+type Node interface {
+	node()
+}
+
+// Clause is a single search clause, Index and Relation are lower cased.
+type Clause struct {
+	Index     string
+	Relation  string
+	Modifiers []Modifier
+	Term      string
+}
+
+type Boolean struct {
+	Op        string
+	Modifiers []Modifier
+	Left      Node
+	Right     Node
+}
+
+type Modifier struct {
+	Name       string
+	Comparison string
+	Value      string
+}
+
+func (*Clause) node()  {}
+func (*Boolean) node() {}
+
+const ServerChoice = "cql.serverchoice"
+
+var booleans = map[string]bool{"and": true, "or": true, "not": true, "prox": true}
+
+var namedRelations = map[string]bool{"all": true, "any": true, "adj": true, "exact": true, "within": true, "encloses": true}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse reads a CQL query, without sortBy, into its syntax tree.
+func Parse(query string) (Node, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{Pos: 0, Msg: "empty query"}
+	}
+
+	node, err := p.scopedClause()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		if t.kind == tokenWord && strings.EqualFold(t.value, "sortby") {
+			return nil, &SyntaxError{Pos: t.pos, Msg: "sortBy is not supported"}
+		}
+		return nil, &SyntaxError{Pos: t.pos, Msg: "unexpected " + quote(t)}
+	}
+
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) scopedClause() (Node, error) {
+	left, err := p.searchClause()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokenWord || !booleans[strings.ToLower(t.value)] {
+			return left, nil
+		}
+		p.next()
+
+		modifiers, err := p.modifiers()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := p.searchClause()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Boolean{Op: strings.ToLower(t.value), Modifiers: modifiers, Left: left, Right: right}
+	}
+}
+
+func (p *parser) searchClause() (Node, error) {
+	t := p.peek()
+
+	if t.kind == tokenLParen {
+		p.next()
+		node, err := p.scopedClause()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: closing.pos, Msg: "expected ) instead of " + quote(closing)}
+		}
+		return node, nil
+	}
+
+	if !isTerm(t) {
+		return nil, &SyntaxError{Pos: t.pos, Msg: "expected a search term instead of " + quote(t)}
+	}
+
+	if !p.startsRelation(1) {
+		p.next()
+		return &Clause{Index: ServerChoice, Relation: "=", Term: t.value}, nil
+	}
+
+	p.next()
+	relation := p.next()
+
+	modifiers, err := p.modifiers()
+	if err != nil {
+		return nil, err
+	}
+
+	term := p.next()
+	if !isTerm(term) {
+		return nil, &SyntaxError{Pos: term.pos, Msg: "expected a search term instead of " + quote(term)}
+	}
+
+	return &Clause{
+		Index:     strings.ToLower(t.value),
+		Relation:  strings.ToLower(relation.value),
+		Modifiers: modifiers,
+		Term:      term.value,
+	}, nil
+}
+
+// startsRelation tells an index from a bare term.
+func (p *parser) startsRelation(offset int) bool {
+	t := p.peekAt(offset)
+
+	switch t.kind {
+	case tokenComparitor:
+		return true
+	case tokenWord:
+		name := strings.ToLower(t.value)
+		if !namedRelations[name] && !strings.Contains(name, ".") {
+			return false
+		}
+		after := p.peekAt(offset + 1)
+		return isTerm(after) || after.kind == tokenSlash
+	}
+
+	return false
+}
+
+func (p *parser) modifiers() ([]Modifier, error) {
+	modifiers := []Modifier{}
+
+	for p.peek().kind == tokenSlash {
+		p.next()
+
+		name := p.next()
+		if name.kind != tokenWord {
+			return nil, &SyntaxError{Pos: name.pos, Msg: "expected a modifier name instead of " + quote(name)}
+		}
+
+		m := Modifier{Name: strings.ToLower(name.value)}
+
+		if p.peek().kind == tokenComparitor {
+			m.Comparison = p.next().value
+			value := p.next()
+			if !isTerm(value) {
+				return nil, &SyntaxError{Pos: value.pos, Msg: "expected a modifier value instead of " + quote(value)}
+			}
+			m.Value = value.value
+		}
+
+		modifiers = append(modifiers, m)
+	}
+
+	return modifiers, nil
+}
+
+func isTerm(t token) bool {
+	return t.kind == tokenWord || t.kind == tokenString
+}
+
+func quote(t token) string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+	return `"` + t.value + `"`
+}
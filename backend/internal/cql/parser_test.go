@@ -0,0 +1,121 @@
+package cql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// show prints a tree with every boolean in parentheses.
+func show(node Node) string {
+	switch n := node.(type) {
+	case *Boolean:
+		return "(" + show(n.Left) + " " + n.Op + showModifiers(n.Modifiers) + " " + show(n.Right) + ")"
+	case *Clause:
+		return fmt.Sprintf("%s %s%s %q", n.Index, n.Relation, showModifiers(n.Modifiers), n.Term)
+	}
+	return "?"
+}
+
+func showModifiers(modifiers []Modifier) string {
+	var b strings.Builder
+	for _, m := range modifiers {
+		b.WriteString("/" + m.Name + m.Comparison + m.Value)
+	}
+	return b.String()
+}
+
+func TestLex(t *testing.T) {
+	tokens, err := lex(`dc.title<>"a \"b\" \*"/x=1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []token{
+		{tokenWord, "dc.title", 0},
+		{tokenComparitor, "<>", 8},
+		{tokenString, `a "b" \*`, 10},
+		{tokenSlash, "/", 22},
+		{tokenWord, "x", 23},
+		{tokenComparitor, "=", 24},
+		{tokenWord, "1", 25},
+		{tokenEOF, "", 26},
+	}
+	if fmt.Sprint(tokens) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", tokens, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`dune`, `cql.serverchoice = "dune"`},
+		{`"dune messiah"`, `cql.serverchoice = "dune messiah"`},
+		{`DC.Title == Dune`, `dc.title == "Dune"`},
+		{`title<>dune`, `title <> "dune"`},
+		{`date >= 1965`, `date >= "1965"`},
+		{`date within "1960 1970"`, `date within "1960 1970"`},
+		{`title ANY "dune hyperion"`, `title any "dune hyperion"`},
+		{`title =/cql.nowhere x`, `title =/cql.nowhere "x"`},
+		{`title =/locale=en x`, `title =/locale=en "x"`},
+		{`title = "with \"quotes\" and \*"`, `title = "with \"quotes\" and \\*"`},
+
+		// booleans bind equally and associate to the left
+		{`a and b or c`, `((cql.serverchoice = "a" and cql.serverchoice = "b") or cql.serverchoice = "c")`},
+		{`a or b and c`, `((cql.serverchoice = "a" or cql.serverchoice = "b") and cql.serverchoice = "c")`},
+		{`a or (b and c)`, `(cql.serverchoice = "a" or (cql.serverchoice = "b" and cql.serverchoice = "c"))`},
+		{`a NOT b`, `(cql.serverchoice = "a" not cql.serverchoice = "b")`},
+		{`a prox/unit=word b`, `(cql.serverchoice = "a" prox/unit=word cql.serverchoice = "b")`},
+
+		// a word that could be a relation is a term when nothing follows it
+		{`any`, `cql.serverchoice = "any"`},
+		{`exact and all`, `(cql.serverchoice = "exact" and cql.serverchoice = "all")`},
+	}
+
+	for _, tt := range tests {
+		node, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("%s: %v", tt.query, err)
+			continue
+		}
+		if got := show(node); got != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{``, 0},
+		{`   `, 0},
+		{`"dune`, 0},
+		{`"dune\`, 5},
+		{`(dune`, 5},
+		{`dune)`, 4},
+		{`title =`, 7},
+		{`title = )`, 8},
+		{`dune and`, 8},
+		{`and dune`, 4},
+		{`title =/ x`, 10},
+		{`dune sortBy title`, 5},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) {
+			t.Errorf("%q: got %v, want a syntax error", tt.query, err)
+			continue
+		}
+		if syntax.Pos != tt.pos {
+			t.Errorf("%q: got %v, want position %d", tt.query, err, tt.pos)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package dublincore
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+const (
+	ElementsNamespace = "http://purl.org/dc/elements/1.1/"
+	OAINamespace      = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+	OAISchema         = "http://www.openarchives.org/OAI/2.0/oai_dc.xsd"
+	SRWNamespace      = "info:srw/schema/1/dc-schema"
+	xsiNamespace      = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// Record holds the simple Dublin Core elements of a book.
+type Record struct {
+	Title       []string `xml:"dc:title"`
+	Creator     []string `xml:"dc:creator"`
+	Subject     []string `xml:"dc:subject"`
+	Publisher   []string `xml:"dc:publisher"`
+	Date        []string `xml:"dc:date"`
+	Type        []string `xml:"dc:type"`
+	Format      []string `xml:"dc:format"`
+	Identifier  []string `xml:"dc:identifier"`
+	Description []string `xml:"dc:description"`
+}
+
+func FromEntry(entry *books.ReadEntry) *Record {
+	rec := &Record{
+		Title:     []string{entry.Book.Title},
+		Creator:   entry.List.Name,
+		Subject:   entry.Book.Genres,
+		Publisher: []string{entry.Book.Publisher},
+		Date:      []string{strconv.Itoa(int(entry.Book.Year))},
+		Type:      []string{"Text"},
+	}
+
+	if entry.Book.PageCount > 0 {
+		rec.Format = []string{fmt.Sprintf("%d pages", entry.Book.PageCount)}
+	}
+
+	if entry.Book.ISBN != "" {
+		rec.Identifier = []string{"urn:isbn:" + entry.Book.ISBN}
+	}
+
+	return rec
+}
+
+// OAIDC is the oai_dc container used by OAI-PMH.
+type OAIDC struct {
+	XMLName        xml.Name `xml:"oai_dc:dc"`
+	NS             string   `xml:"xmlns:oai_dc,attr"`
+	DC             string   `xml:"xmlns:dc,attr"`
+	XSI            string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	*Record
+}
+
+func NewOAIDC(rec *Record) *OAIDC {
+	return &OAIDC{
+		NS:             OAINamespace,
+		DC:             ElementsNamespace,
+		XSI:            xsiNamespace,
+		SchemaLocation: OAINamespace + " " + OAISchema,
+		Record:         rec,
+	}
+}
+
+// SRWDC is the srw_dc container used by SRU.
+type SRWDC struct {
+	XMLName xml.Name `xml:"srw_dc:dc"`
+	NS      string   `xml:"xmlns:srw_dc,attr"`
+	DC      string   `xml:"xmlns:dc,attr"`
+	*Record
+}
+
+func NewSRWDC(rec *Record) *SRWDC {
+	return &SRWDC{
+		NS:     SRWNamespace,
+		DC:     ElementsNamespace,
+		Record: rec,
+	}
+}
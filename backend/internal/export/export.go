@@ -0,0 +1,241 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+// Writer serialises a stream of entries between Begin and End.
+type Writer interface {
+	Begin() error
+	Write(entry *books.ReadEntry) error
+	End() error
+}
+
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	New         func(w io.Writer) Writer
+}
+
+var formats = map[string]Format{}
+
+func Register(f Format) {
+	formats[f.Name] = f
+}
+
+func Lookup(name string) (Format, bool) {
+	f, ok := formats[name]
+	return f, ok
+}
+
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(Format{Name: "csv", ContentType: "text/csv; charset=utf-8", Extension: "csv", New: newCSVWriter})
+	Register(Format{Name: "json", ContentType: "application/json", Extension: "json", New: newJSONWriter})
+	Register(Format{Name: "ndjson", ContentType: "application/x-ndjson", Extension: "ndjson", New: newNDJSONWriter})
+}
+
+// Record is the flat shape books are exported with.
+type Record struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Publisher string    `json:"publisher"`
+	Year      int32     `json:"year"`
+	PageCount int32     `json:"page_count"`
+	Genres    []string  `json:"genres"`
+	ISBN      string    `json:"isbn,omitempty"`
+	Authors   []string  `json:"authors"`
+	Version   int32     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewRecord(entry *books.ReadEntry) Record {
+	return Record{
+		ID:        entry.Book.ID,
+		Title:     entry.Book.Title,
+		Publisher: entry.Book.Publisher,
+		Year:      entry.Book.Year,
+		PageCount: entry.Book.PageCount,
+		Genres:    entry.Book.Genres,
+		ISBN:      entry.Book.ISBN,
+		Authors:   entry.List.Name,
+		Version:   entry.Book.Version,
+		UpdatedAt: entry.LastModified.UTC(),
+	}
+}
+
+// the importer default columns
+var csvHeader = []string{"id", "title", "publisher", "year", "page_count", "genres", "authors", "isbn", "version", "updated_at"}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Begin() error {
+	return c.w.Write(csvHeader)
+}
+
+func (c *csvWriter) Write(entry *books.ReadEntry) error {
+	rec := NewRecord(entry)
+
+	return c.w.Write([]string{
+		strconv.FormatInt(rec.ID, 10),
+		rec.Title,
+		rec.Publisher,
+		strconv.Itoa(int(rec.Year)),
+		strconv.Itoa(int(rec.PageCount)),
+		strings.Join(rec.Genres, ";"),
+		strings.Join(rec.Authors, ";"),
+		rec.ISBN,
+		strconv.Itoa(int(rec.Version)),
+		rec.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (c *csvWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func newJSONWriter(w io.Writer) Writer {
+	return &jsonWriter{w: bufio.NewWriter(w)}
+}
+
+func (j *jsonWriter) Begin() error {
+	_, err := j.w.WriteString("[\n")
+	return err
+}
+
+func (j *jsonWriter) Write(entry *books.ReadEntry) error {
+	js, err := json.Marshal(NewRecord(entry))
+	if err != nil {
+		return err
+	}
+
+	if j.count > 0 {
+		if _, err := j.w.WriteString(",\n"); err != nil {
+			return err
+		}
+	}
+	j.count++
+
+	_, err = j.w.Write(js)
+	return err
+}
+
+func (j *jsonWriter) End() error {
+	if _, err := j.w.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
+
+type ndjsonWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) Writer {
+	bw := bufio.NewWriter(w)
+	return &ndjsonWriter{w: bw, enc: json.NewEncoder(bw)}
+}
+
+func (n *ndjsonWriter) Begin() error {
+	return nil
+}
+
+func (n *ndjsonWriter) Write(entry *books.ReadEntry) error {
+	return n.enc.Encode(NewRecord(entry))
+}
+
+func (n *ndjsonWriter) End() error {
+	return n.w.Flush()
+}
+
+// Flusher is implemented by the writers that buffer.
+type Flusher interface {
+	Flush() error
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (j *jsonWriter) Flush() error {
+	return j.w.Flush()
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
+
+func UnknownFormatError(name string) error {
+	return fmt.Errorf("unsupported format %q, expected one of %s", name, strings.Join(Names(), ", "))
+}
+
+// entries between two flushes of the underlying writer
+const flushEvery = 100
+
+// Run streams the books matching filter into w, calling flush every few entries.
+func Run(ctx context.Context, read books.ReadRepository, f Format, filter books.ExportFilter, w io.Writer, flush func() error) (int, error) {
+	ew := f.New(w)
+
+	if err := ew.Begin(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	err := read.Stream(ctx, filter, func(entry *books.ReadEntry) error {
+		if err := ew.Write(entry); err != nil {
+			return err
+		}
+		count++
+
+		if flush == nil || count%flushEvery != 0 {
+			return nil
+		}
+
+		if fl, ok := ew.(Flusher); ok {
+			if err := fl.Flush(); err != nil {
+				return err
+			}
+		}
+		return flush()
+	})
+	if err != nil {
+		return count, err
+	}
+
+	return count, ew.End()
+}
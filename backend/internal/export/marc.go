@@ -0,0 +1,57 @@
+package export
+
+import (
+	"io"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/marc"
+)
+
+func init() {
+	Register(Format{Name: "marc", ContentType: "application/marc", Extension: "mrc", New: newMARCWriter})
+	Register(Format{Name: "marcxml", ContentType: "application/marcxml+xml", Extension: "xml", New: newMARCXMLWriter})
+}
+
+type marcWriter struct {
+	w *marc.Writer
+}
+
+func newMARCWriter(w io.Writer) Writer {
+	return &marcWriter{w: marc.NewWriter(w)}
+}
+
+func (m *marcWriter) Begin() error {
+	return nil
+}
+
+func (m *marcWriter) Write(entry *books.ReadEntry) error {
+	return m.w.Write(marc.FromEntry(entry))
+}
+
+func (m *marcWriter) End() error {
+	return nil
+}
+
+type marcXMLWriter struct {
+	w *marc.XMLWriter
+}
+
+func newMARCXMLWriter(w io.Writer) Writer {
+	return &marcXMLWriter{w: marc.NewXMLWriter(w)}
+}
+
+func (m *marcXMLWriter) Begin() error {
+	return nil
+}
+
+func (m *marcXMLWriter) Write(entry *books.ReadEntry) error {
+	return m.w.Write(marc.FromEntry(entry))
+}
+
+func (m *marcXMLWriter) End() error {
+	return m.w.Close()
+}
+
+func (m *marcXMLWriter) Flush() error {
+	return m.w.Flush()
+}
@@ -3,11 +3,15 @@ package internal
 import (
 	"crypto/sha1"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
 )
 
+var ErrUnsafeSort = errors.New("unsafe sort parameter")
+
 type Filters struct {
 	Page         int
 	PageSize     int
@@ -30,8 +34,8 @@ func (f *Filters) ValidateFilters(v *validator.Validator) bool {
 	var minPageSize = 0
 	var maxPageSize = 30
 
-	v.Check(f.Page > minPage, section, fmt.Sprintf(fieldGreaterThanMsg, section, minPageSize))
-	v.Check(f.Page <= maxPage, section, fmt.Sprintf(fieldLessThanMsg, section, maxPageSize))
+	v.Check(f.PageSize > minPageSize, section, fmt.Sprintf(fieldGreaterThanMsg, section, minPageSize))
+	v.Check(f.PageSize <= maxPageSize, section, fmt.Sprintf(fieldLessThanMsg, section, maxPageSize))
 
 	section = "sort"
 	v.Check(v.In(f.Sort, f.SortSafeList), section, fmt.Sprintf("invalid %s parameter", section))
@@ -39,6 +43,53 @@ func (f *Filters) ValidateFilters(v *validator.Validator) bool {
 	return v.Valid()
 }
 
+func (f Filters) SortColumn() (string, error) {
+	for _, safeValue := range f.SortSafeList {
+		if f.Sort == safeValue {
+			return strings.TrimPrefix(f.Sort, "-"), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnsafeSort, f.Sort)
+}
+
+func (f Filters) SortDirection() string {
+	if strings.HasPrefix(f.Sort, "-") {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+func (f Filters) Limit() int {
+	return f.PageSize
+}
+
+func (f Filters) Offset() int {
+	return (f.Page - 1) * f.PageSize
+}
+
+type Metadata struct {
+	CurrentPage  int `json:"current_page,omitempty"`
+	PageSize     int `json:"page_size,omitempty"`
+	FirstPage    int `json:"first_page,omitempty"`
+	LastPage     int `json:"last_page,omitempty"`
+	TotalRecords int `json:"total_records,omitempty"`
+}
+
+func CalculateMetadata(totalRecords, page, pageSize int) Metadata {
+	if totalRecords == 0 {
+		return Metadata{}
+	}
+
+	return Metadata{
+		CurrentPage:  page,
+		PageSize:     pageSize,
+		FirstPage:    1,
+		LastPage:     (totalRecords + pageSize - 1) / pageSize,
+		TotalRecords: totalRecords,
+	}
+}
+
 func DiffArrays(oldArr, newArr []string) ([]string, []string, []string) {
 	common := make([]string, 0)
 	exclusiveOld := make([]string, 0)
@@ -71,6 +122,18 @@ func DiffArrays(oldArr, newArr []string) ([]string, []string, []string) {
 	return common, exclusiveOld, exclusiveNew
 }
 
+// ToUpper only touches ASCII letters, the author hashes depend on it.
+func ToUpper(str string) string {
+	new_str := []byte(str)
+	for i := 0; i < len(str); i++ {
+		if str[i] >= 'a' && str[i] <= 'z' {
+			chr := uint8(rune(str[i]) - 'a' + 'A')
+			new_str[i] = chr
+		}
+	}
+	return string(new_str)
+}
+
 func HashArrays(arr1, arr2 []string) ([]string, []string) {
 	hashArr1 := make([]string, len(arr1))
 	hashArr2 := make([]string, len(arr2))
@@ -0,0 +1,73 @@
+package gql
+
+import (
+	"fmt"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+const (
+	CodeValidation      = "VALIDATION_FAILED"
+	CodeNotFound        = "NOT_FOUND"
+	CodeVersionConflict = "VERSION_CONFLICT"
+	CodeEditConflict    = "EDIT_CONFLICT"
+	CodeDuplicate       = "DUPLICATE"
+	CodeInternal        = "INTERNAL"
+)
+
+// Error carries its code and details in the extensions.
+type Error struct {
+	Message string
+	Code    string
+	Details map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"code": e.Code}
+	for key, value := range e.Details {
+		ext[key] = value
+	}
+	return ext
+}
+
+func validationError(errs map[string]string) *Error {
+	return &Error{
+		Message: "the input failed validation",
+		Code:    CodeValidation,
+		Details: map[string]interface{}{"fields": errs},
+	}
+}
+
+func notFoundError(kind string, id int64) *Error {
+	return &Error{
+		Message: fmt.Sprintf("%s %d could not be found", kind, id),
+		Code:    CodeNotFound,
+	}
+}
+
+func versionConflictError(current int32) *Error {
+	return &Error{
+		Message: "unable to update the record, it was modified since the version you provided",
+		Code:    CodeVersionConflict,
+		Details: map[string]interface{}{"currentVersion": current},
+	}
+}
+
+func duplicateError(dup *books.DuplicateError) *Error {
+	return &Error{
+		Message: "a book with the same title already exists",
+		Code:    CodeDuplicate,
+		Details: map[string]interface{}{"bookId": dup.ID, "trashed": dup.Trashed},
+	}
+}
+
+func editConflictError() *Error {
+	return &Error{
+		Message: "unable to update the record due to an edit conflict, please try again",
+		Code:    CodeEditConflict,
+	}
+}
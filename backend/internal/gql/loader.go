@@ -0,0 +1,95 @@
+package gql
+
+import (
+	"context"
+	"sync"
+)
+
+// Loader batches the lookups of one request, the first Load fetches every key
+// announced with Want.
+type Loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	results map[K]*result[V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func NewLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		results: make(map[K]*result[V]),
+	}
+}
+
+// Want queues keys for the next batch.
+func (l *Loader[K, V]) Want(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if _, ok := l.results[key]; ok {
+			continue
+		}
+		l.results[key] = &result[V]{done: make(chan struct{})}
+		l.pending = append(l.pending, key)
+	}
+}
+
+// Load returns the value for key, fetching the queued keys with it.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.Want(key)
+
+	l.mu.Lock()
+	res := l.results[key]
+	batch := []K{}
+	if containsKey(l.pending, key) {
+		batch, l.pending = l.pending, nil
+	}
+	l.mu.Unlock()
+
+	if len(batch) > 0 {
+		l.run(ctx, batch)
+	}
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *Loader[K, V]) run(ctx context.Context, batch []K) {
+	values, err := l.fetch(ctx, batch)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range batch {
+		res := l.results[key]
+		res.value, res.err = values[key], err
+		close(res.done)
+
+		//failures are not cached
+		if err != nil {
+			delete(l.results, key)
+		}
+	}
+}
+
+func containsKey[K comparable](keys []K, key K) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/memory"
+)
+
+// fetcher records the batches a loader asks for.
+type fetcher struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (f *fetcher) fetch(ctx context.Context, keys []int) (map[int]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.batches = append(f.batches, slices.Clone(keys))
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	values := make(map[int]string, len(keys))
+	for _, key := range keys {
+		if key > 0 {
+			values[key] = fmt.Sprint("value ", key)
+		}
+	}
+	return values, nil
+}
+
+func TestLoaderBatches(t *testing.T) {
+	f := &fetcher{}
+	l := NewLoader(f.fetch)
+
+	l.Want(1, 2, 3, 2)
+
+	for _, key := range []int{2, 1, 3, -1} {
+		value, err := l.Load(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key > 0 && value != fmt.Sprint("value ", key) {
+			t.Errorf("got %q for %d", value, key)
+		}
+		if key < 0 && value != "" {
+			t.Errorf("got %q for a missing key", value)
+		}
+	}
+
+	// the first Load takes every announced key, the unannounced one goes alone
+	if want := [][]int{{1, 2, 3}, {-1}}; fmt.Sprint(f.batches) != fmt.Sprint(want) {
+		t.Errorf("got batches %v, want %v", f.batches, want)
+	}
+
+	l.Want(1, 4)
+	if _, err := l.Load(context.Background(), 4); err != nil {
+		t.Fatal(err)
+	}
+	if last := f.batches[len(f.batches)-1]; fmt.Sprint(last) != "[4]" {
+		t.Errorf("a loaded key was fetched again in %v", last)
+	}
+}
+
+func TestLoaderConcurrent(t *testing.T) {
+	f := &fetcher{}
+	l := NewLoader(f.fetch)
+
+	keys := []int{1, 2, 3, 4, 5}
+	l.Want(keys...)
+
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			if _, err := l.Load(context.Background(), key); err != nil {
+				t.Error(err)
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	if len(f.batches) != 1 {
+		t.Errorf("got batches %v, want a single one", f.batches)
+	}
+}
+
+func TestLoaderError(t *testing.T) {
+	f := &fetcher{err: errors.New("the database is gone")}
+	l := NewLoader(f.fetch)
+
+	if _, err := l.Load(context.Background(), 1); !errors.Is(err, f.err) {
+		t.Fatalf("got error %v, want %v", err, f.err)
+	}
+
+	f.err = nil
+	value, err := l.Load(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "value 1" || len(f.batches) != 2 {
+		t.Errorf("got %q after %d batches, the failure must not be cached", value, len(f.batches))
+	}
+}
+
+// countingAuthors counts the author→books lookups of a query.
+type countingAuthors struct {
+	books.AuthorRepository
+	calls int
+}
+
+func (c *countingAuthors) BooksByAuthors(ctx context.Context, authorIDs []int64) (map[int64][]*books.ReadEntry, error) {
+	c.calls++
+	return c.AuthorRepository.BooksByAuthors(ctx, authorIDs)
+}
+
+func TestQueryBatchesBooksByAuthor(t *testing.T) {
+	store := memory.New()
+
+	for _, entry := range []*books.CreateBookEntry{
+		{Book: &books.Book{Title: "Hyperion", Publisher: "Doubleday", Year: 1989, PageCount: 482}, Authors: &books.Authors{List: []string{"Dan Simmons"}}},
+		{Book: &books.Book{Title: "Dune", Publisher: "Ace", Year: 1965, PageCount: 412}, Authors: &books.Authors{List: []string{"Frank Herbert"}}},
+		{Book: &books.Book{Title: "Good Omens", Publisher: "Gollancz", Year: 1990, PageCount: 288}, Authors: &books.Authors{List: []string{"Terry Pratchett", "Neil Gaiman"}}},
+	} {
+		entry.Normalize()
+		if err := store.Create().Save(context.Background(), entry, &books.ReadEntry{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	authors := &countingAuthors{AuthorRepository: store.Authors()}
+	service, err := NewService(&Resolver{Read: store.Read(), AuthorStore: authors})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := service.Exec(context.Background(), Request{Query: `{ books { nodes { title authors { name books { title } } } } }`})
+	if len(res.Errors) != 0 {
+		t.Fatal(res.Errors)
+	}
+
+	if authors.calls != 1 {
+		t.Errorf("the books of four authors took %d lookups, want 1", authors.calls)
+	}
+	// each title shows once as a book and once more under each of its authors
+	for title, want := range map[string]int{"Hyperion": 2, "Dune": 2, "Good Omens": 3} {
+		if n := strings.Count(string(res.Data), fmt.Sprintf(`"title":%q`, title)); n != want {
+			t.Errorf("%s shows %d times in %s, want %d", title, n, res.Data, want)
+		}
+	}
+}
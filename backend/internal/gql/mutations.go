@@ -0,0 +1,160 @@
+package gql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type createBookInput struct {
+	Title     string
+	Publisher string
+	Year      int32
+	PageCount int32
+	Genres    *[]string
+	Isbn      *string
+	Authors   []string
+}
+
+func (r *Resolver) CreateBook(ctx context.Context, args struct{ Input createBookInput }) (*bookResolver, error) {
+	in := args.Input
+
+	entry := &books.CreateBookEntry{
+		Book: &books.Book{
+			Title:     in.Title,
+			Publisher: in.Publisher,
+			Year:      in.Year,
+			PageCount: in.PageCount,
+			ISBN:      books.NormalizeISBN(stringValue(in.Isbn)),
+		},
+		Authors: &books.Authors{
+			List: in.Authors,
+		},
+	}
+	if in.Genres != nil {
+		entry.Book.Genres = *in.Genres
+	}
+
+	v := validator.NewValidator()
+	if !entry.ValidateEntry(v) {
+		return nil, validationError(v.Errors)
+	}
+
+	entry.Normalize()
+
+	read := &books.ReadEntry{}
+	err := r.Tx.Retry(ctx, "create", func(ctx context.Context) error {
+		return r.Create.Save(ctx, entry, read)
+	})
+	if err != nil {
+		var dup *books.DuplicateError
+		if errors.As(err, &dup) {
+			return nil, duplicateError(dup)
+		}
+		return nil, r.internalError(err)
+	}
+
+	return newBookResolvers(ctx, []*books.ReadEntry{read})[0], nil
+}
+
+type updateBookInput struct {
+	Title     *string
+	Publisher *string
+	Year      *int32
+	PageCount *int32
+	Genres    *[]string
+	Isbn      *string
+	Authors   *[]string
+	Version   int32
+}
+
+func (r *Resolver) UpdateBook(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateBookInput
+}) (*bookResolver, error) {
+
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+	in := args.Input
+
+	old := &books.ReadEntry{Book: books.ReadBook{ID: id}}
+	if err := r.Read.Get(ctx, nil, old); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("book", id)
+		}
+		return nil, r.internalError(err)
+	}
+
+	if in.Version != old.Book.Version {
+		return nil, versionConflictError(old.Book.Version)
+	}
+
+	update := books.BookPatch{
+		Title:     in.Title,
+		Publisher: in.Publisher,
+		Year:      in.Year,
+		PageCount: in.PageCount,
+		Genres:    in.Genres,
+		ISBN:      in.Isbn,
+		Authors:   in.Authors,
+	}.Apply(old)
+
+	v := validator.NewValidator()
+	if !update.ValidateEntry(v) {
+		return nil, validationError(v.Errors)
+	}
+
+	err = r.Tx.Retry(ctx, "update", func(ctx context.Context) error {
+		attempt := *old
+		return r.Update.Save(ctx, update, &attempt)
+	})
+	if err != nil {
+		if errors.Is(err, books.ErrEditConflict) {
+			current := &books.ReadEntry{Book: books.ReadBook{ID: id}}
+			if err := r.Read.Get(ctx, nil, current); err != nil {
+				return nil, editConflictError()
+			}
+			return nil, versionConflictError(current.Book.Version)
+		}
+		return nil, r.internalError(err)
+	}
+
+	entry, err := r.getBook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, notFoundError("book", id)
+	}
+
+	return newBookResolvers(ctx, []*books.ReadEntry{entry})[0], nil
+}
+
+func (r *Resolver) DeleteBook(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return false, err
+	}
+
+	err = r.Tx.Run(ctx, "delete", r.Delete.BeginTx, func(ctx context.Context, tx books.Tx) error {
+		return r.Delete.DeleteBook(ctx, tx, &books.DeleteID{ID: id})
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, books.ErrNotFound):
+			return false, notFoundError("book", id)
+		case errors.Is(err, books.ErrEditConflict):
+			return false, editConflictError()
+		default:
+			return false, r.internalError(err)
+		}
+	}
+
+	return true, nil
+}
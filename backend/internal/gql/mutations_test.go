@@ -0,0 +1,59 @@
+package gql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/memory"
+)
+
+func TestUpdateBookVersion(t *testing.T) {
+	store := memory.New()
+
+	entry := &books.CreateBookEntry{
+		Book:    &books.Book{Title: "Dune", Publisher: "Ace", Year: 1965, PageCount: 412, Genres: []string{"science fiction"}},
+		Authors: &books.Authors{List: []string{"Frank Herbert"}},
+	}
+	entry.Normalize()
+	if err := store.Create().Save(context.Background(), entry, &books.ReadEntry{}); err != nil {
+		t.Fatal(err)
+	}
+
+	service, err := NewService(&Resolver{Read: store.Read(), Update: store.Update(), AuthorStore: store.Authors()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"without a version", `{publisher: "Chilton Books"}`, `Expected "Int!", found null`},
+		{"with a stale version", `{publisher: "Chilton Books", version: 7}`, "modified since the version you provided"},
+		{"with the current version", `{publisher: "Chilton Books", version: 1}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := service.Exec(context.Background(), Request{Query: `mutation { updateBook(id: "1", input: ` + tt.input + `) { publisher version } }`})
+
+			switch {
+			case tt.wantErr == "" && len(res.Errors) != 0:
+				t.Fatal(res.Errors)
+			case tt.wantErr != "" && (len(res.Errors) == 0 || !strings.Contains(res.Errors[0].Message, tt.wantErr)):
+				t.Fatalf("got errors %v, want %q", res.Errors, tt.wantErr)
+			}
+		})
+	}
+
+	read := &books.ReadEntry{Book: books.ReadBook{ID: 1}}
+	if err := store.Read().Get(context.Background(), nil, read); err != nil {
+		t.Fatal(err)
+	}
+	if read.Book.Version != 2 {
+		t.Errorf("got version %d, only the update with the current version should land", read.Book.Version)
+	}
+}
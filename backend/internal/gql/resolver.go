@@ -0,0 +1,201 @@
+package gql
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"strconv"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+var (
+	bookSortSafeList   = []string{"id", "title", "year", "created_at", "-id", "-title", "-year", "-created_at"}
+	authorSortSafeList = []string{"id", "name", "-id", "-name"}
+)
+
+// Resolver is the root of the schema.
+type Resolver struct {
+	Read   books.ReadRepository
+	Create books.CreateRepository
+	Update books.UpdateRepository
+	Delete books.DeleteRepository
+	// AuthorStore, Authors being the query
+	AuthorStore books.AuthorRepository
+	// Tx retries the writes, nil runs them once
+	Tx *books.TxRunner
+	// OnError sees the errors hidden from the client behind CodeInternal
+	OnError func(err error)
+}
+
+func (r *Resolver) internalError(err error) *Error {
+	if r.OnError != nil {
+		r.OnError(err)
+	}
+	return &Error{
+		Message: "the server encountered a problem and could not process your request",
+		Code:    CodeInternal,
+	}
+}
+
+func parseID(id graphql.ID, field string) (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || n < 1 {
+		return 0, validationError(map[string]string{field: "must be a positive integer"})
+	}
+	return n, nil
+}
+
+func (r *Resolver) getBook(ctx context.Context, id int64) (*books.ReadEntry, error) {
+	entry := &books.ReadEntry{Book: books.ReadBook{ID: id}}
+
+	err := r.Read.Get(ctx, nil, entry)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, r.internalError(err)
+	}
+
+	entry.Authors = make([]books.ReadAuthor, len(entry.List.Name))
+	entry.Convert()
+
+	return entry, nil
+}
+
+func (r *Resolver) Book(ctx context.Context, args struct{ ID graphql.ID }) (*bookResolver, error) {
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := r.getBook(ctx, id)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	return newBookResolvers(ctx, []*books.ReadEntry{entry})[0], nil
+}
+
+type bookFilter struct {
+	Query    *string
+	Genre    *string
+	AuthorId *graphql.ID
+}
+
+func (r *Resolver) Books(ctx context.Context, args struct {
+	Filter   *bookFilter
+	Sort     *string
+	Page     *int32
+	PageSize *int32
+}) (*bookPageResolver, error) {
+
+	v := validator.NewValidator()
+
+	filter := books.ListFilter{}
+	if args.Filter != nil {
+		filter.Query = stringValue(args.Filter.Query)
+		filter.Genre = stringValue(args.Filter.Genre)
+		if args.Filter.AuthorId != nil {
+			id, err := parseID(*args.Filter.AuthorId, "filter.authorId")
+			if err != nil {
+				return nil, err
+			}
+			filter.AuthorID = id
+		}
+	}
+
+	filters := internal.Filters{
+		Page:         int(int32Value(args.Page, 1)),
+		PageSize:     int(int32Value(args.PageSize, 20)),
+		Sort:         stringValueOr(args.Sort, "id"),
+		SortSafeList: bookSortSafeList,
+	}
+
+	if !filters.ValidateFilters(v) {
+		return nil, validationError(v.Errors)
+	}
+
+	entries, metadata, err := r.Read.List(ctx, filter, filters)
+	if err != nil {
+		return nil, r.internalError(err)
+	}
+
+	return &bookPageResolver{nodes: newBookResolvers(ctx, entries), metadata: metadata}, nil
+}
+
+func (r *Resolver) Author(ctx context.Context, args struct{ ID graphql.ID }) (*authorResolver, error) {
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &books.ReadAuthorEntry{Author: books.ReadAuthor{ID: id}}
+
+	err = r.AuthorStore.AuthorGet(ctx, nil, entry)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, r.internalError(err)
+	}
+
+	return newAuthorResolvers(ctx, []books.ReadAuthor{entry.Author})[0], nil
+}
+
+func (r *Resolver) Authors(ctx context.Context, args struct {
+	Sort     *string
+	Page     *int32
+	PageSize *int32
+}) (*authorPageResolver, error) {
+
+	v := validator.NewValidator()
+
+	filters := internal.Filters{
+		Page:         int(int32Value(args.Page, 1)),
+		PageSize:     int(int32Value(args.PageSize, 20)),
+		Sort:         stringValueOr(args.Sort, "name"),
+		SortSafeList: authorSortSafeList,
+	}
+
+	if !filters.ValidateFilters(v) {
+		return nil, validationError(v.Errors)
+	}
+
+	summaries, metadata, err := r.AuthorStore.ListAuthors(ctx, filters)
+	if err != nil {
+		return nil, r.internalError(err)
+	}
+
+	authors := make([]books.ReadAuthor, len(summaries))
+	for i, s := range summaries {
+		authors[i] = books.ReadAuthor{ID: s.ID, Name: s.Name, Books_authored: s.BooksAuthored}
+	}
+
+	return &authorPageResolver{nodes: newAuthorResolvers(ctx, authors), metadata: metadata}, nil
+}
+
+func stringValue(s *string) string {
+	return stringValueOr(s, "")
+}
+
+func stringValueOr(s *string, fallback string) string {
+	if s == nil {
+		return fallback
+	}
+	return *s
+}
+
+func int32Value(n *int32, fallback int32) int32 {
+	if n == nil {
+		return fallback
+	}
+	return *n
+}
@@ -0,0 +1,56 @@
+// Package gql serves books and authors over GraphQL.
+package gql
+
+import (
+	"context"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	maxDepth       = 8
+	maxParallelism = 10
+)
+
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Service struct {
+	resolver *Resolver
+	schema   *graphql.Schema
+}
+
+func NewService(resolver *Resolver) (*Service, error) {
+	schema, err := graphql.ParseSchema(Schema, resolver,
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{resolver: resolver, schema: schema}, nil
+}
+
+// Exec runs one request with its own loaders.
+func (s *Service) Exec(ctx context.Context, req Request) *graphql.Response {
+	ctx = context.WithValue(ctx, loadersKey{}, &loaders{
+		booksByAuthor: NewLoader(s.resolver.AuthorStore.BooksByAuthors),
+	})
+
+	return s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+type loadersKey struct{}
+
+type loaders struct {
+	booksByAuthor *Loader[int64, []*books.ReadEntry]
+}
+
+func booksByAuthorFrom(ctx context.Context) *Loader[int64, []*books.ReadEntry] {
+	return ctx.Value(loadersKey{}).(*loaders).booksByAuthor
+}
@@ -0,0 +1,166 @@
+package gql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type bookResolver struct {
+	entry *books.ReadEntry
+}
+
+// newBookResolvers announces the authors of every book to the loader.
+func newBookResolvers(ctx context.Context, entries []*books.ReadEntry) []*bookResolver {
+	loader := booksByAuthorFrom(ctx)
+
+	resolvers := make([]*bookResolver, len(entries))
+	for i, entry := range entries {
+		for _, author := range entry.Authors {
+			loader.Want(author.ID)
+		}
+		resolvers[i] = &bookResolver{entry: entry}
+	}
+	return resolvers
+}
+
+func (b *bookResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(b.entry.Book.ID, 10))
+}
+
+func (b *bookResolver) Hash() string {
+	return b.entry.Book.Hash
+}
+
+func (b *bookResolver) Title() string {
+	return b.entry.Book.Title
+}
+
+func (b *bookResolver) Publisher() string {
+	return b.entry.Book.Publisher
+}
+
+func (b *bookResolver) Year() int32 {
+	return b.entry.Book.Year
+}
+
+func (b *bookResolver) PageCount() int32 {
+	return b.entry.Book.PageCount
+}
+
+func (b *bookResolver) Genres() []string {
+	if b.entry.Book.Genres == nil {
+		return []string{}
+	}
+	return b.entry.Book.Genres
+}
+
+func (b *bookResolver) Isbn() *string {
+	if b.entry.Book.ISBN == "" {
+		return nil
+	}
+	return &b.entry.Book.ISBN
+}
+
+func (b *bookResolver) Version() int32 {
+	return b.entry.Book.Version
+}
+
+func (b *bookResolver) Authors(ctx context.Context) []*authorResolver {
+	return newAuthorResolvers(ctx, b.entry.Authors)
+}
+
+type authorResolver struct {
+	author books.ReadAuthor
+}
+
+func newAuthorResolvers(ctx context.Context, authors []books.ReadAuthor) []*authorResolver {
+	loader := booksByAuthorFrom(ctx)
+
+	resolvers := make([]*authorResolver, len(authors))
+	for i, author := range authors {
+		loader.Want(author.ID)
+		resolvers[i] = &authorResolver{author: author}
+	}
+	return resolvers
+}
+
+func (a *authorResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(a.author.ID, 10))
+}
+
+func (a *authorResolver) Name() string {
+	return a.author.Name
+}
+
+func (a *authorResolver) Identifier() *string {
+	if a.author.Identifier == "" {
+		return nil
+	}
+	return &a.author.Identifier
+}
+
+func (a *authorResolver) BooksAuthored() int32 {
+	return a.author.Books_authored
+}
+
+func (a *authorResolver) Books(ctx context.Context) ([]*bookResolver, error) {
+	entries, err := booksByAuthorFrom(ctx).Load(ctx, a.author.ID)
+	if err != nil {
+		return nil, err
+	}
+	return newBookResolvers(ctx, entries), nil
+}
+
+type bookPageResolver struct {
+	nodes    []*bookResolver
+	metadata internal.Metadata
+}
+
+func (p *bookPageResolver) Nodes() []*bookResolver {
+	return p.nodes
+}
+
+func (p *bookPageResolver) Metadata() *metadataResolver {
+	return &metadataResolver{p.metadata}
+}
+
+type authorPageResolver struct {
+	nodes    []*authorResolver
+	metadata internal.Metadata
+}
+
+func (p *authorPageResolver) Nodes() []*authorResolver {
+	return p.nodes
+}
+
+func (p *authorPageResolver) Metadata() *metadataResolver {
+	return &metadataResolver{p.metadata}
+}
+
+type metadataResolver struct {
+	m internal.Metadata
+}
+
+func (m *metadataResolver) CurrentPage() int32 {
+	return int32(m.m.CurrentPage)
+}
+
+func (m *metadataResolver) PageSize() int32 {
+	return int32(m.m.PageSize)
+}
+
+func (m *metadataResolver) FirstPage() int32 {
+	return int32(m.m.FirstPage)
+}
+
+func (m *metadataResolver) LastPage() int32 {
+	return int32(m.m.LastPage)
+}
+
+func (m *metadataResolver) TotalRecords() int32 {
+	return int32(m.m.TotalRecords)
+}
@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	libraryv1 "github.com/3WDeveloper-GM/library_app/backend/proto/library/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func bookMessage(entry *books.ReadEntry) *libraryv1.Book {
+	book := &libraryv1.Book{
+		Id:        entry.Book.ID,
+		Hash:      entry.Book.Hash,
+		Title:     entry.Book.Title,
+		Publisher: entry.Book.Publisher,
+		Year:      entry.Book.Year,
+		PageCount: entry.Book.PageCount,
+		Genres:    entry.Book.Genres,
+		Isbn:      entry.Book.ISBN,
+		Version:   entry.Book.Version,
+		Authors:   make([]*libraryv1.AuthorSummary, len(entry.Authors)),
+	}
+
+	for i := range entry.Authors {
+		book.Authors[i] = authorSummaryMessage(entry.Authors[i])
+	}
+	if !entry.LastModified.IsZero() {
+		book.UpdatedAt = timestamppb.New(entry.LastModified)
+	}
+
+	return book
+}
+
+func authorSummaryMessage(author books.ReadAuthor) *libraryv1.AuthorSummary {
+	return &libraryv1.AuthorSummary{
+		Id:            author.ID,
+		Name:          author.Name,
+		Identifier:    author.Identifier,
+		BooksAuthored: author.Books_authored,
+	}
+}
+
+// the books of an author carry no author list, the caller already has it
+func authorMessage(entry *books.ReadAuthorEntry) *libraryv1.Author {
+	author := &libraryv1.Author{
+		Id:            entry.Author.ID,
+		Name:          entry.Author.Name,
+		Identifier:    entry.Author.Identifier,
+		BooksAuthored: entry.Author.Books_authored,
+		Books:         make([]*libraryv1.Book, len(entry.BookDisplay)),
+	}
+
+	for i := range entry.BookDisplay {
+		author.Books[i] = bookMessage(&books.ReadEntry{Book: entry.BookDisplay[i]})
+	}
+
+	return author
+}
+
+func metadataMessage(metadata internal.Metadata) *libraryv1.PageMetadata {
+	return &libraryv1.PageMetadata{
+		CurrentPage:  int32(metadata.CurrentPage),
+		PageSize:     int32(metadata.PageSize),
+		FirstPage:    int32(metadata.FirstPage),
+		LastPage:     int32(metadata.LastPage),
+		TotalRecords: int32(metadata.TotalRecords),
+	}
+}
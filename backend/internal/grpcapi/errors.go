@@ -0,0 +1,89 @@
+package grpcapi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const errorDomain = "library.v1"
+
+func (s *Server) internalError(err error) error {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+	return status.Error(codes.Internal, "the server encountered a problem and could not process your request")
+}
+
+// validationError carries one violation per field.
+func validationError(errs map[string]string) error {
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: errs[field],
+		})
+	}
+
+	return withDetails(status.New(codes.InvalidArgument, "the input failed validation"), badRequest)
+}
+
+func notFoundError(kind string, id int64) error {
+	return status.Error(codes.NotFound, fmt.Sprintf("%s %d could not be found", kind, id))
+}
+
+func versionConflictError(current int32) error {
+	st := status.New(codes.FailedPrecondition, "unable to update the record, it was modified since the version you provided")
+
+	return withDetails(st, &errdetails.ErrorInfo{
+		Reason:   "VERSION_CONFLICT",
+		Domain:   errorDomain,
+		Metadata: map[string]string{"current_version": strconv.Itoa(int(current))},
+	})
+}
+
+func versionRequiredError() error {
+	st := status.New(codes.FailedPrecondition, "the version of the book must be sent")
+
+	return withDetails(st, &errdetails.ErrorInfo{
+		Reason: "VERSION_REQUIRED",
+		Domain: errorDomain,
+	})
+}
+
+func duplicateError(dup *books.DuplicateError) error {
+	st := status.New(codes.AlreadyExists, "a book with the same title already exists")
+
+	return withDetails(st, &errdetails.ErrorInfo{
+		Reason: "DUPLICATE",
+		Domain: errorDomain,
+		Metadata: map[string]string{
+			"book_id": strconv.FormatInt(dup.ID, 10),
+			"trashed": strconv.FormatBool(dup.Trashed),
+		},
+	})
+}
+
+func editConflictError() error {
+	return status.Error(codes.Aborted, "unable to update the record due to an edit conflict, please try again")
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,119 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	libraryv1 "github.com/3WDeveloper-GM/library_app/backend/proto/library/v1"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const maxActorBytes = 100
+
+// NewGRPCServer registers s on a grpc server that audits and logs every call.
+func NewGRPCServer(s *Server, log *zerolog.Logger, adminToken string) *grpc.Server {
+	i := interceptors{log: log, adminToken: adminToken}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	)
+
+	libraryv1.RegisterLibraryServiceServer(server, s)
+	reflection.Register(server)
+
+	return server
+}
+
+type interceptors struct {
+	log        *zerolog.Logger
+	adminToken string
+}
+
+// auditMeta reads the headers of config.AuditMeta from the grpc metadata.
+func (i interceptors) auditMeta(ctx context.Context) audit.Meta {
+	meta := audit.Meta{Actor: "anonymous"}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get("x-request-id"); len(values) > 0 && values[0] != "" {
+		meta.RequestID = values[0]
+	} else {
+		meta.RequestID = newRequestID()
+	}
+
+	if values := md.Get("x-actor"); len(values) > 0 {
+		if actor := strings.TrimSpace(values[0]); actor != "" && len(actor) <= maxActorBytes {
+			meta.Actor = actor
+		}
+	}
+
+	if values := md.Get("authorization"); len(values) > 0 && i.adminToken != "" {
+		token, ok := strings.CutPrefix(values[0], "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(i.adminToken)) == 1 {
+			meta.Actor = "admin"
+		}
+	}
+
+	return meta
+}
+
+func (i interceptors) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	meta := i.auditMeta(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", meta.RequestID))
+
+	start := time.Now()
+	res, err := handler(audit.NewContext(ctx, meta), req)
+	i.logCall(info.FullMethod, meta, start, err)
+
+	return res, err
+}
+
+func (i interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	meta := i.auditMeta(ss.Context())
+	ss.SetHeader(metadata.Pairs("x-request-id", meta.RequestID))
+
+	start := time.Now()
+	err := handler(srv, &auditStream{ServerStream: ss, ctx: audit.NewContext(ss.Context(), meta)})
+	i.logCall(info.FullMethod, meta, start, err)
+
+	return err
+}
+
+func (i interceptors) logCall(method string, meta audit.Meta, start time.Time, err error) {
+	event := i.log.Info()
+	if err != nil {
+		event = i.log.Error()
+	}
+
+	event.Str("method", method).
+		Str("code", status.Code(err).String()).
+		Str("actor", meta.Actor).
+		Str("request_id", meta.RequestID).
+		Dur("duration", time.Since(start)).
+		Msg("handled a grpc call")
+}
+
+type auditStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *auditStream) Context() context.Context {
+	return s.ctx
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,329 @@
+// Package grpcapi serves the catalogue over gRPC.
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+	libraryv1 "github.com/3WDeveloper-GM/library_app/backend/proto/library/v1"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	bookSortSafeList   = []string{"id", "title", "year", "created_at", "-id", "-title", "-year", "-created_at"}
+	authorSortSafeList = []string{"id", "name", "-id", "-name"}
+)
+
+type Server struct {
+	libraryv1.UnimplementedLibraryServiceServer
+
+	Read    books.ReadRepository
+	Create  books.CreateRepository
+	Update  books.UpdateRepository
+	Delete  books.DeleteRepository
+	Authors books.AuthorRepository
+	// Tx retries the writes, nil runs them once
+	Tx *books.TxRunner
+	// OnError sees the errors hidden from the client behind codes.Internal
+	OnError func(err error)
+}
+
+func checkID(id int64) error {
+	if id < 1 {
+		return validationError(map[string]string{"id": "must be a positive integer"})
+	}
+	return nil
+}
+
+func pageFilters(page, pageSize int32, sort, fallback string, safeList []string) internal.Filters {
+	filters := internal.Filters{
+		Page:         int(page),
+		PageSize:     int(pageSize),
+		Sort:         sort,
+		SortSafeList: safeList,
+	}
+
+	//proto3 sends zero for unset fields
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.PageSize == 0 {
+		filters.PageSize = 20
+	}
+	if filters.Sort == "" {
+		filters.Sort = fallback
+	}
+
+	return filters
+}
+
+func (s *Server) getBook(ctx context.Context, id int64) (*books.ReadEntry, error) {
+	entry := &books.ReadEntry{Book: books.ReadBook{ID: id}}
+
+	err := s.Read.Get(ctx, nil, entry)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("book", id)
+		}
+		return nil, s.internalError(err)
+	}
+
+	entry.Authors = make([]books.ReadAuthor, len(entry.List.Name))
+	entry.Convert()
+
+	return entry, nil
+}
+
+func (s *Server) GetBook(ctx context.Context, req *libraryv1.GetBookRequest) (*libraryv1.GetBookResponse, error) {
+	if err := checkID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.getBook(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &libraryv1.GetBookResponse{Book: bookMessage(entry)}, nil
+}
+
+func (s *Server) ListBooks(ctx context.Context, req *libraryv1.ListBooksRequest) (*libraryv1.ListBooksResponse, error) {
+	v := validator.NewValidator()
+
+	v.Check(req.GetAuthorId() >= 0, "author_id", "must be a positive integer")
+
+	filters := pageFilters(req.GetPage(), req.GetPageSize(), req.GetSort(), "id", bookSortSafeList)
+	if !filters.ValidateFilters(v) || !v.Valid() {
+		return nil, validationError(v.Errors)
+	}
+
+	filter := books.ListFilter{
+		Query:    req.GetQuery(),
+		Genre:    req.GetGenre(),
+		AuthorID: req.GetAuthorId(),
+	}
+
+	entries, metadata, err := s.Read.List(ctx, filter, filters)
+	if err != nil {
+		return nil, s.internalError(err)
+	}
+
+	res := &libraryv1.ListBooksResponse{
+		Books:    make([]*libraryv1.Book, len(entries)),
+		Metadata: metadataMessage(metadata),
+	}
+	for i, entry := range entries {
+		res.Books[i] = bookMessage(entry)
+	}
+
+	return res, nil
+}
+
+func (s *Server) CreateBook(ctx context.Context, req *libraryv1.CreateBookRequest) (*libraryv1.CreateBookResponse, error) {
+	entry := &books.CreateBookEntry{
+		Book: &books.Book{
+			Title:     req.GetTitle(),
+			Publisher: req.GetPublisher(),
+			Year:      req.GetYear(),
+			PageCount: req.GetPageCount(),
+			Genres:    req.GetGenres(),
+			ISBN:      books.NormalizeISBN(req.GetIsbn()),
+		},
+		Authors: &books.Authors{
+			List: req.GetAuthors(),
+		},
+	}
+
+	v := validator.NewValidator()
+	if !entry.ValidateEntry(v) {
+		return nil, validationError(v.Errors)
+	}
+
+	entry.Normalize()
+
+	read := &books.ReadEntry{}
+	err := s.Tx.Retry(ctx, "create", func(ctx context.Context) error {
+		return s.Create.Save(ctx, entry, read)
+	})
+	if err != nil {
+		var dup *books.DuplicateError
+		if errors.As(err, &dup) {
+			return nil, duplicateError(dup)
+		}
+		return nil, s.internalError(err)
+	}
+
+	return &libraryv1.CreateBookResponse{Book: bookMessage(read)}, nil
+}
+
+func (s *Server) UpdateBook(ctx context.Context, req *libraryv1.UpdateBookRequest) (*libraryv1.UpdateBookResponse, error) {
+	if err := checkID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	old, err := s.getBook(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Version == nil {
+		return nil, versionRequiredError()
+	}
+
+	if req.GetVersion() != old.Book.Version {
+		return nil, versionConflictError(old.Book.Version)
+	}
+
+	patch := books.BookPatch{
+		Title:     req.Title,
+		Publisher: req.Publisher,
+		Year:      req.Year,
+		PageCount: req.PageCount,
+		ISBN:      req.Isbn,
+	}
+	if req.Genres != nil {
+		patch.Genres = &req.Genres.Values
+	}
+	if req.Authors != nil {
+		patch.Authors = &req.Authors.Values
+	}
+
+	update := patch.Apply(old)
+
+	v := validator.NewValidator()
+	if !update.ValidateEntry(v) {
+		return nil, validationError(v.Errors)
+	}
+
+	err = s.Tx.Retry(ctx, "update", func(ctx context.Context) error {
+		attempt := *old
+		return s.Update.Save(ctx, update, &attempt)
+	})
+	if err != nil {
+		if errors.Is(err, books.ErrEditConflict) {
+			current, err := s.getBook(ctx, req.GetId())
+			if err != nil {
+				return nil, editConflictError()
+			}
+			return nil, versionConflictError(current.Book.Version)
+		}
+		return nil, s.internalError(err)
+	}
+
+	entry, err := s.getBook(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &libraryv1.UpdateBookResponse{Book: bookMessage(entry)}, nil
+}
+
+func (s *Server) DeleteBook(ctx context.Context, req *libraryv1.DeleteBookRequest) (*libraryv1.DeleteBookResponse, error) {
+	if err := checkID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	err := s.Tx.Run(ctx, "delete", s.Delete.BeginTx, func(ctx context.Context, tx books.Tx) error {
+		return s.Delete.DeleteBook(ctx, tx, &books.DeleteID{ID: req.GetId()})
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, books.ErrNotFound):
+			return nil, notFoundError("book", req.GetId())
+		case errors.Is(err, books.ErrEditConflict):
+			return nil, editConflictError()
+		default:
+			return nil, s.internalError(err)
+		}
+	}
+
+	return &libraryv1.DeleteBookResponse{}, nil
+}
+
+func (s *Server) GetAuthor(ctx context.Context, req *libraryv1.GetAuthorRequest) (*libraryv1.GetAuthorResponse, error) {
+	if err := checkID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	entry := &books.ReadAuthorEntry{Author: books.ReadAuthor{ID: req.GetId()}}
+
+	err := s.Authors.AuthorGet(ctx, nil, entry)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("author", req.GetId())
+		}
+		return nil, s.internalError(err)
+	}
+
+	entry.BookDisplay = make([]books.ReadBook, len(entry.BookList.Hash))
+	entry.Convert()
+
+	return &libraryv1.GetAuthorResponse{Author: authorMessage(entry)}, nil
+}
+
+func (s *Server) ListAuthors(ctx context.Context, req *libraryv1.ListAuthorsRequest) (*libraryv1.ListAuthorsResponse, error) {
+	v := validator.NewValidator()
+
+	filters := pageFilters(req.GetPage(), req.GetPageSize(), req.GetSort(), "name", authorSortSafeList)
+	if !filters.ValidateFilters(v) {
+		return nil, validationError(v.Errors)
+	}
+
+	summaries, metadata, err := s.Authors.ListAuthors(ctx, filters)
+	if err != nil {
+		return nil, s.internalError(err)
+	}
+
+	res := &libraryv1.ListAuthorsResponse{
+		Authors:  make([]*libraryv1.AuthorSummary, len(summaries)),
+		Metadata: metadataMessage(metadata),
+	}
+	for i, summary := range summaries {
+		res.Authors[i] = authorSummaryMessage(books.ReadAuthor{
+			ID:             summary.ID,
+			Name:           summary.Name,
+			Books_authored: summary.BooksAuthored,
+		})
+	}
+
+	return res, nil
+}
+
+// ExportBooks streams the books as the rows are read.
+func (s *Server) ExportBooks(req *libraryv1.ExportBooksRequest, stream libraryv1.LibraryService_ExportBooksServer) error {
+	v := validator.NewValidator()
+
+	v.Check(req.GetYearFrom() >= 0, "year_from", "must not be negative")
+	v.Check(req.GetYearTo() >= 0, "year_to", "must not be negative")
+	v.Check(req.GetYearTo() == 0 || req.GetYearFrom() <= req.GetYearTo(), "year_to", "must not be before year_from")
+
+	if !v.Valid() {
+		return validationError(v.Errors)
+	}
+
+	filter := books.ExportFilter{
+		Genre:     req.GetGenre(),
+		Publisher: req.GetPublisher(),
+		YearFrom:  req.GetYearFrom(),
+		YearTo:    req.GetYearTo(),
+	}
+	if req.ModifiedSince != nil {
+		filter.ModifiedSince = req.GetModifiedSince().AsTime()
+	}
+
+	err := s.Read.Stream(stream.Context(), filter, func(entry *books.ReadEntry) error {
+		return stream.Send(&libraryv1.ExportBooksResponse{Book: bookMessage(entry)})
+	})
+	if err != nil {
+		if ctxErr := stream.Context().Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		return s.internalError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package grpcapi_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/grpcapi"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/memory"
+	libraryv1 "github.com/3WDeveloper-GM/library_app/backend/proto/library/v1"
+	"github.com/rs/zerolog"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves a fresh memory store over an in-process listener.
+func dial(t *testing.T) libraryv1.LibraryServiceClient {
+	t.Helper()
+
+	store := memory.New()
+	log := zerolog.Nop()
+
+	server := grpcapi.NewGRPCServer(&grpcapi.Server{
+		Read:    store.Read(),
+		Create:  store.Create(),
+		Update:  store.Update(),
+		Delete:  store.Delete(),
+		Authors: store.Authors(),
+	}, &log, "")
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return libraryv1.NewLibraryServiceClient(conn)
+}
+
+func createDune(t *testing.T, client libraryv1.LibraryServiceClient) *libraryv1.Book {
+	t.Helper()
+
+	res, err := client.CreateBook(context.Background(), &libraryv1.CreateBookRequest{
+		Title:     "Dune",
+		Publisher: "Ace",
+		Year:      1965,
+		PageCount: 412,
+		Genres:    []string{"science fiction"},
+		Authors:   []string{"Frank Herbert"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.GetBook()
+}
+
+func errorInfo(t *testing.T, err error, code codes.Code) *errdetails.ErrorInfo {
+	t.Helper()
+
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Fatalf("got %v, want %s", err, code)
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+func TestBooks(t *testing.T) {
+	client := dial(t)
+	ctx := context.Background()
+
+	dune := createDune(t, client)
+	if dune.GetId() != 1 || dune.GetVersion() != 1 || len(dune.GetAuthors()) != 1 {
+		t.Fatalf("got book %v", dune)
+	}
+
+	got, err := client.GetBook(ctx, &libraryv1.GetBookRequest{Id: dune.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetBook().GetTitle() != "Dune" || got.GetBook().GetAuthors()[0].GetName() != "FRANK HERBERT" {
+		t.Errorf("got book %v", got.GetBook())
+	}
+
+	list, err := client.ListBooks(ctx, &libraryv1.ListBooksRequest{Query: "dune"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.GetBooks()) != 1 || list.GetMetadata().GetTotalRecords() != 1 {
+		t.Errorf("got list %v", list)
+	}
+
+	publisher := "Chilton Books"
+	version := int32(1)
+	updated, err := client.UpdateBook(ctx, &libraryv1.UpdateBookRequest{Id: dune.GetId(), Publisher: &publisher, Version: &version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetBook().GetPublisher() != publisher || updated.GetBook().GetVersion() != 2 {
+		t.Errorf("got book %v", updated.GetBook())
+	}
+
+	if _, err := client.DeleteBook(ctx, &libraryv1.DeleteBookRequest{Id: dune.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetBook(ctx, &libraryv1.GetBookRequest{Id: dune.GetId()}); status.Code(err) != codes.NotFound {
+		t.Errorf("got %v for a deleted book, want NotFound", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	client := dial(t)
+	ctx := context.Background()
+
+	createDune(t, client)
+	title := "Dune Messiah"
+
+	_, err := client.GetBook(ctx, &libraryv1.GetBookRequest{Id: 0})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v for id 0, want InvalidArgument", err)
+	}
+
+	_, err = client.CreateBook(ctx, &libraryv1.CreateBookRequest{Publisher: "Ace", Year: 1965, PageCount: 412, Genres: []string{"science fiction"}, Authors: []string{"Frank Herbert"}})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("got %v for a book without a title, want InvalidArgument", err)
+	}
+	for _, detail := range st.Details() {
+		if bad, ok := detail.(*errdetails.BadRequest); !ok || len(bad.GetFieldViolations()) != 1 || bad.GetFieldViolations()[0].GetField() != "title" {
+			t.Errorf("got details %v", detail)
+		}
+	}
+
+	_, err = client.CreateBook(ctx, &libraryv1.CreateBookRequest{Title: "Dune", Publisher: "Ace", Year: 1965, PageCount: 412, Genres: []string{"science fiction"}, Authors: []string{"Frank Herbert"}})
+	if info := errorInfo(t, err, codes.AlreadyExists); info.GetReason() != "DUPLICATE" || info.GetMetadata()["book_id"] != "1" {
+		t.Errorf("got %v for a duplicate", info)
+	}
+
+	_, err = client.UpdateBook(ctx, &libraryv1.UpdateBookRequest{Id: 1, Title: &title})
+	if info := errorInfo(t, err, codes.FailedPrecondition); info.GetReason() != "VERSION_REQUIRED" {
+		t.Errorf("got %v without a version", info)
+	}
+
+	stale := int32(7)
+	_, err = client.UpdateBook(ctx, &libraryv1.UpdateBookRequest{Id: 1, Title: &title, Version: &stale})
+	if info := errorInfo(t, err, codes.FailedPrecondition); info.GetReason() != "VERSION_CONFLICT" || info.GetMetadata()["current_version"] != "1" {
+		t.Errorf("got %v for a stale version", info)
+	}
+
+	_, err = client.ListBooks(ctx, &libraryv1.ListBooksRequest{Sort: "pages"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v for an unsafe sort, want InvalidArgument", err)
+	}
+}
+
+func TestExportBooks(t *testing.T) {
+	client := dial(t)
+	createDune(t, client)
+
+	stream, err := client.ExportBooks(context.Background(), &libraryv1.ExportBooksRequest{Genre: "science fiction"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	titles := []string{}
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		titles = append(titles, res.GetBook().GetTitle())
+	}
+
+	if len(titles) != 1 || titles[0] != "Dune" {
+		t.Errorf("got titles %q", titles)
+	}
+
+	stream, err = client.ExportBooks(context.Background(), &libraryv1.ExportBooksRequest{YearFrom: 2000, YearTo: 1990})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v for a reversed range, want InvalidArgument", err)
+	}
+}
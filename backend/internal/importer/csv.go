@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+// separator for the multi-valued authors and genres columns
+const listSeparator = ";"
+
+var Fields = []string{"title", "publisher", "year", "page_count", "genres", "authors", "isbn"}
+
+// columns that may be missing unless mapped
+var optionalFields = map[string]bool{"isbn": true}
+
+// Mapping goes from a book field to the header of the CSV column holding it.
+type Mapping map[string]string
+
+func DefaultMapping() Mapping {
+	m := make(Mapping, len(Fields))
+	for _, field := range Fields {
+		m[field] = field
+	}
+	return m
+}
+
+// ParseMapping reads overrides such as "title=Book Title,authors=Written by".
+func ParseMapping(s string) (Mapping, error) {
+	m := DefaultMapping()
+
+	if strings.TrimSpace(s) == "" {
+		return m, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.TrimSpace(field)
+		column = strings.TrimSpace(column)
+
+		if !ok || column == "" {
+			return nil, fmt.Errorf("invalid mapping %q, expected field=column", pair)
+		}
+		if _, known := m[field]; !known {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+
+		m[field] = column
+	}
+
+	return m, nil
+}
+
+type Row struct {
+	Line   int
+	Entry  *books.CreateBookEntry
+	Errors map[string]string
+}
+
+func (row *Row) addError(key, message string) {
+	if row.Errors == nil {
+		row.Errors = make(map[string]string)
+	}
+	if _, exists := row.Errors[key]; !exists {
+		row.Errors[key] = message
+	}
+}
+
+func ReadCSV(r io.Reader, m Mapping) ([]*Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv file must not be empty")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = index
+	}
+
+	positions := make(map[string]int, len(m))
+	for field, column := range m {
+		index, ok := columns[column]
+		switch {
+		case !ok && optionalFields[field] && column == field:
+			positions[field] = -1
+			continue
+		case !ok:
+			return nil, fmt.Errorf("column %q mapped to %s is missing from the header", column, field)
+		}
+		positions[field] = index
+	}
+
+	rows := []*Row{}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		row := &Row{
+			Line: line,
+			Entry: &books.CreateBookEntry{
+				Book:    &books.Book{},
+				Authors: &books.Authors{},
+			},
+		}
+		rows = append(rows, row)
+
+		if err != nil {
+			row.addError("row", err.Error())
+			continue
+		}
+
+		value := func(field string) string {
+			index := positions[field]
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		row.Entry.Book.Title = value("title")
+		row.Entry.Book.Publisher = value("publisher")
+		row.Entry.Book.Year = row.readInt32(value("year"), "year")
+		row.Entry.Book.PageCount = row.readInt32(value("page_count"), "page_count")
+		row.Entry.Book.Genres = splitList(value("genres"))
+		row.Entry.Authors.List = splitList(value("authors"))
+		row.Entry.Book.ISBN = books.NormalizeISBN(value("isbn"))
+	}
+
+	return rows, nil
+}
+
+func (row *Row) readInt32(s, key string) int32 {
+	if s == "" {
+		return 0
+	}
+
+	i, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		row.addError(key, "must be an integer value")
+		return 0
+	}
+
+	return int32(i)
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	list := []string{}
+	for _, item := range strings.Split(s, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,167 @@
+package importer_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/importer"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/memory"
+)
+
+func TestParseMapping(t *testing.T) {
+	m, err := importer.ParseMapping(" title = Book Title ,authors=Written by")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["title"] != "Book Title" || m["authors"] != "Written by" || m["publisher"] != "publisher" {
+		t.Errorf("got mapping %v", m)
+	}
+
+	for _, s := range []string{"title", "title=", "shelf=Shelf"} {
+		if _, err := importer.ParseMapping(s); err == nil {
+			t.Errorf("ParseMapping(%q) succeeded", s)
+		}
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	file := "\ufefftitle,publisher,year,page_count,genres,authors\n" +
+		"Dune,Ace,1965,412,science fiction; classic ,Frank Herbert\n" +
+		"Hyperion,Doubleday,nineteen,482,,Dan Simmons;;\n" +
+		"\"Good Omens\",Gollancz,1990,288,fantasy,Terry Pratchett;Neil Gaiman\n"
+
+	rows, err := importer.ReadCSV(strings.NewReader(file), importer.DefaultMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+
+	dune := rows[0]
+	if dune.Line != 2 || dune.Errors != nil {
+		t.Errorf("got line %d and errors %v", dune.Line, dune.Errors)
+	}
+	if dune.Entry.Book.Year != 1965 || dune.Entry.Book.PageCount != 412 {
+		t.Errorf("got year %d and page count %d", dune.Entry.Book.Year, dune.Entry.Book.PageCount)
+	}
+	if want := []string{"science fiction", "classic"}; !reflect.DeepEqual(dune.Entry.Book.Genres, want) {
+		t.Errorf("got genres %q, want %q", dune.Entry.Book.Genres, want)
+	}
+
+	hyperion := rows[1]
+	if _, ok := hyperion.Errors["year"]; !ok || len(hyperion.Errors) != 1 {
+		t.Errorf("got errors %v, want one on year", hyperion.Errors)
+	}
+	if want := []string{"Dan Simmons"}; !reflect.DeepEqual(hyperion.Entry.Authors.List, want) {
+		t.Errorf("got authors %q, want %q", hyperion.Entry.Authors.List, want)
+	}
+
+	if want := []string{"Terry Pratchett", "Neil Gaiman"}; !reflect.DeepEqual(rows[2].Entry.Authors.List, want) {
+		t.Errorf("got authors %q, want %q", rows[2].Entry.Authors.List, want)
+	}
+}
+
+func TestReadCSVHeader(t *testing.T) {
+	m, err := importer.ParseMapping("title=Book Title")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := importer.ReadCSV(strings.NewReader("Book Title,publisher,year,page_count,genres,authors\nDune,Ace,1965,412,,Frank Herbert\n"), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title := rows[0].Entry.Book.Title; title != "Dune" {
+		t.Errorf("got title %q through the mapping", title)
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		m        importer.Mapping
+		contains string
+	}{
+		{"empty file", "", importer.DefaultMapping(), "must not be empty"},
+		{"missing column", "title,publisher,page_count,genres,authors\n", importer.DefaultMapping(), `"year"`},
+		{"mapped isbn missing", "title,publisher,year,page_count,genres,authors\n", importer.Mapping{"isbn": "ISBN-13"}, `"ISBN-13"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importer.ReadCSV(strings.NewReader(tt.file), tt.m)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("got error %v, want one containing %s", err, tt.contains)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	file := "title,publisher,year,page_count,genres,authors\n" +
+		"Dune,Ace,1965,412,science fiction,Frank Herbert\n" +
+		"Hyperion,Doubleday,1989,482,science fiction,Dan Simmons\n" +
+		"Hyperion,Doubleday,1989,482,science fiction,Dan Simmons\n" +
+		",Doubleday,1989,482,science fiction,Dan Simmons\n" +
+		"Endymion,Bantam,1996,441,science fiction,Dan Simmons\n"
+
+	for _, dryRun := range []bool{true, false} {
+		name := "insert"
+		if dryRun {
+			name = "dry run"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			rows, err := importer.ReadCSV(strings.NewReader(file), importer.DefaultMapping())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			store := memory.New()
+			imp := &importer.Importer{Create: store.Create()}
+
+			// Dune is already in the catalogue
+			first, err := importer.ReadCSV(strings.NewReader("title,publisher,year,page_count,genres,authors\nDune,Ace,1965,412,science fiction,Frank Herbert\n"), importer.DefaultMapping())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := imp.Run(context.Background(), first, importer.Options{}); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := imp.Run(context.Background(), rows, importer.Options{DryRun: dryRun, BatchSize: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fresh := importer.StatusCreated
+			if dryRun {
+				fresh = importer.StatusValid
+			}
+			want := []string{importer.StatusDuplicate, fresh, importer.StatusDuplicate, importer.StatusRejected, fresh}
+
+			got := make([]string, len(report.Rows))
+			for i, row := range report.Rows {
+				got[i] = row.Status
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got statuses %q, want %q", got, want)
+			}
+
+			if report.Total != 5 || report.Duplicates != 2 || report.Rejected != 1 || report.Created+report.Valid != 2 {
+				t.Errorf("got report %+v", report)
+			}
+			if _, ok := report.Rows[3].Errors["title"]; !ok {
+				t.Errorf("the row without a title is rejected with %v", report.Rows[3].Errors)
+			}
+
+			for _, row := range report.Rows {
+				if (row.ID != 0) != (row.Status == importer.StatusCreated) {
+					t.Errorf("line %d is %s with id %d", row.Line, row.Status, row.ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"context"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+const (
+	StatusCreated   = "created"
+	StatusValid     = "valid"
+	StatusDuplicate = "duplicate"
+	StatusRejected  = "rejected"
+)
+
+type Options struct {
+	DryRun    bool
+	BatchSize int
+	// OnError gets the database errors behind rejected rows
+	OnError func(line int, err error)
+}
+
+type Result struct {
+	Line   int               `json:"line"`
+	Status string            `json:"status"`
+	ID     int64             `json:"id,omitempty"`
+	Title  string            `json:"title"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+type Report struct {
+	DryRun     bool      `json:"dry_run"`
+	Total      int       `json:"total"`
+	Created    int       `json:"created"`
+	Valid      int       `json:"valid"`
+	Duplicates int       `json:"duplicates"`
+	Rejected   int       `json:"rejected"`
+	Rows       []*Result `json:"rows"`
+}
+
+func (rep *Report) set(result *Result, status string) {
+	result.Status = status
+
+	switch status {
+	case StatusCreated:
+		rep.Created++
+	case StatusValid:
+		rep.Valid++
+	case StatusDuplicate:
+		rep.Duplicates++
+	case StatusRejected:
+		rep.Rejected++
+	}
+}
+
+type Importer struct {
+	Create books.CreateRepository
+	// Tx retries the batches, nil runs them once
+	Tx *books.TxRunner
+}
+
+// Run validates the rows, skips the duplicates and inserts the rest in batches.
+func (imp *Importer) Run(ctx context.Context, rows []*Row, opts Options) (*Report, error) {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 100
+	}
+
+	report := &Report{
+		DryRun: opts.DryRun,
+		Total:  len(rows),
+		Rows:   make([]*Result, len(rows)),
+	}
+
+	pending := []int{}
+	hashes := []string{}
+
+	for index, row := range rows {
+		result := &Result{
+			Line:   row.Line,
+			Title:  row.Entry.Book.Title,
+			Errors: row.Errors,
+		}
+		report.Rows[index] = result
+
+		if len(row.Errors) != 0 {
+			report.set(result, StatusRejected)
+			continue
+		}
+
+		v := validator.NewValidator()
+		if row.Entry.ValidateEntry(v) {
+			v.Check(validator.Unique(row.Entry.Authors.List), "authors", "authors field must not contain duplicates")
+		}
+		if !v.Valid() {
+			result.Errors = v.Errors
+			report.set(result, StatusRejected)
+			continue
+		}
+
+		for i, name := range row.Entry.Authors.List {
+			row.Entry.Authors.List[i] = internal.ToUpper(name)
+		}
+		books.HashEntries(row.Entry.Book, row.Entry.Authors)
+
+		pending = append(pending, index)
+		hashes = append(hashes, row.Entry.Book.Hash)
+	}
+
+	existing, err := imp.Create.ExistingHashes(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := []int{}
+
+	for _, index := range pending {
+		hash := rows[index].Entry.Book.Hash
+
+		if existing[hash] {
+			report.set(report.Rows[index], StatusDuplicate)
+			continue
+		}
+		existing[hash] = true
+
+		if opts.DryRun {
+			report.set(report.Rows[index], StatusValid)
+			continue
+		}
+
+		batch = append(batch, index)
+		if len(batch) == opts.BatchSize {
+			if err := imp.insertBatch(ctx, rows, batch, report, opts); err != nil {
+				return nil, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) != 0 {
+		if err := imp.insertBatch(ctx, rows, batch, report, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// a failed batch is retried one row at a time
+func (imp *Importer) insertBatch(ctx context.Context, rows []*Row, batch []int, report *Report, opts Options) error {
+	ids, err := imp.insert(ctx, rows, batch)
+	if err == nil {
+		for i, index := range batch {
+			report.Rows[index].ID = ids[i]
+			report.set(report.Rows[index], StatusCreated)
+		}
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	for _, index := range batch {
+		ids, err := imp.insert(ctx, rows, []int{index})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if opts.OnError != nil {
+				opts.OnError(rows[index].Line, err)
+			}
+			report.Rows[index].Errors = map[string]string{"row": "the row could not be inserted"}
+			report.set(report.Rows[index], StatusRejected)
+			continue
+		}
+
+		report.Rows[index].ID = ids[0]
+		report.set(report.Rows[index], StatusCreated)
+	}
+
+	return nil
+}
+
+func (imp *Importer) insert(ctx context.Context, rows []*Row, batch []int) ([]int64, error) {
+	ids := make([]int64, len(batch))
+
+	err := imp.Tx.Run(ctx, "import", imp.Create.BeginTx, func(ctx context.Context, tx books.Tx) error {
+		for i, index := range batch {
+			read := &books.ReadEntry{}
+
+			if err := imp.Create.Insert(ctx, tx, rows[index].Entry, read); err != nil {
+				return err
+			}
+			ids[i] = read.Book.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
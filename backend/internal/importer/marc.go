@@ -0,0 +1,40 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/marc"
+)
+
+// ReadMARC turns ISO 2709 or MARCXML records into rows, numbered from 1.
+func ReadMARC(r io.Reader, xml bool) ([]*Row, error) {
+	var rd marc.RecordReader = marc.NewReader(r)
+	if xml {
+		rd = marc.NewXMLReader(r)
+	}
+
+	rows := []*Row{}
+
+	for position := 1; ; position++ {
+		rec, err := rd.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", position, err)
+		}
+
+		rows = append(rows, &Row{
+			Line:  position,
+			Entry: marc.ToEntry(rec),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("the file does not contain any MARC record")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,208 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrMalformedRecord = errors.New("malformed ISO 2709 record")
+
+// Reader decodes a stream of ISO 2709 records.
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns io.EOF once the stream is exhausted.
+func (rd *Reader) Next() (*Record, error) {
+	//skip stray line breaks
+	for {
+		b, err := rd.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != '\n' && b[0] != '\r' {
+			break
+		}
+		rd.r.Discard(1)
+	}
+
+	head, err := rd.r.Peek(5)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	length, ok := digits(head)
+	if !ok || length < leaderLength+1 {
+		return nil, fmt.Errorf("%w: invalid record length %q", ErrMalformedRecord, head)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(rd.r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return Unmarshal(data)
+}
+
+// Unmarshal decodes a single ISO 2709 record.
+func Unmarshal(data []byte) (*Record, error) {
+	if len(data) < leaderLength+1 || data[len(data)-1] != recordTerminator {
+		return nil, fmt.Errorf("%w: missing record terminator", ErrMalformedRecord)
+	}
+
+	rec := &Record{Leader: string(data[:leaderLength])}
+
+	base, ok := digits(data[12:17])
+	if !ok || base <= leaderLength || base > len(data) {
+		return nil, fmt.Errorf("%w: invalid base address", ErrMalformedRecord)
+	}
+
+	directory := data[leaderLength : base-1]
+	if len(directory)%directoryEntry != 0 {
+		return nil, fmt.Errorf("%w: invalid directory length", ErrMalformedRecord)
+	}
+
+	for i := 0; i < len(directory); i += directoryEntry {
+		entry := directory[i : i+directoryEntry]
+
+		tag := string(entry[0:3])
+		length, ok1 := digits(entry[3:7])
+		start, ok2 := digits(entry[7:12])
+		if !ok1 || !ok2 || length < 1 || base+start+length > len(data) {
+			return nil, fmt.Errorf("%w: invalid directory entry for tag %s", ErrMalformedRecord, tag)
+		}
+
+		// drop the field terminator
+		raw := data[base+start : base+start+length-1]
+
+		field := &Field{Tag: tag}
+
+		if field.IsControl() {
+			field.Value = string(raw)
+			rec.Fields = append(rec.Fields, field)
+			continue
+		}
+
+		if len(raw) < 2 {
+			return nil, fmt.Errorf("%w: missing indicators in tag %s", ErrMalformedRecord, tag)
+		}
+
+		field.Ind1, field.Ind2 = raw[0], raw[1]
+
+		for _, chunk := range bytes.Split(raw[2:], []byte{subfieldDelim}) {
+			if len(chunk) == 0 {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: chunk[0], Value: string(chunk[1:])})
+		}
+
+		rec.Fields = append(rec.Fields, field)
+	}
+
+	return rec, nil
+}
+
+// Marshal encodes the record as ISO 2709.
+func Marshal(rec *Record) ([]byte, error) {
+	var directory, body bytes.Buffer
+
+	for _, field := range rec.Fields {
+		if len(field.Tag) != 3 {
+			return nil, fmt.Errorf("marc: invalid tag %q", field.Tag)
+		}
+
+		start := body.Len()
+
+		if field.IsControl() {
+			body.WriteString(field.Value)
+		} else {
+			body.WriteByte(indicator(field.Ind1))
+			body.WriteByte(indicator(field.Ind2))
+			for _, sf := range field.Subfields {
+				body.WriteByte(subfieldDelim)
+				body.WriteByte(sf.Code)
+				body.WriteString(sf.Value)
+			}
+		}
+		body.WriteByte(fieldTerminator)
+
+		length := body.Len() - start
+		if length > 9999 || start > 99999 {
+			return nil, fmt.Errorf("marc: field %s does not fit in an ISO 2709 record", field.Tag)
+		}
+
+		fmt.Fprintf(&directory, "%s%04d%05d", field.Tag, length, start)
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	total := base + body.Len() + 1
+	if total > 99999 {
+		return nil, errors.New("marc: record longer than 99999 bytes")
+	}
+
+	leader := []byte(rec.Leader)
+	if len(leader) != leaderLength {
+		leader = []byte(defaultLeader)
+	}
+	copy(leader[0:5], fmt.Sprintf("%05d", total))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	out := make([]byte, 0, total)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, body.Bytes()...)
+	out = append(out, recordTerminator)
+
+	return out, nil
+}
+
+// digits parses unsigned leader and directory numbers, signs and blanks included.
+func digits(b []byte) (int, bool) {
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, len(b) > 0
+}
+
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
+
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (wr *Writer) Write(rec *Record) error {
+	data, err := Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	_, err = wr.w.Write(data)
+	return err
+}
@@ -0,0 +1,177 @@
+package marc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+// extra subject headings are dropped
+const maxGenres = 5
+
+var (
+	yearRX  = regexp.MustCompile(`\d{4}`)
+	pagesRX = regexp.MustCompile(`\d+`)
+)
+
+// FromEntry maps a catalogue entry to a bibliographic record.
+func FromEntry(entry *books.ReadEntry) *Record {
+	rec := NewRecord()
+
+	rec.AddControlField("001", strconv.FormatInt(entry.Book.ID, 10))
+	if !entry.LastModified.IsZero() {
+		rec.AddControlField("005", entry.LastModified.UTC().Format("20060102150405.0"))
+	}
+	rec.AddControlField("008", fixedData(entry))
+
+	if entry.Book.ISBN != "" {
+		rec.AddDataField("020", ' ', ' ', Subfield{'a', entry.Book.ISBN})
+	}
+
+	// 245 ind1 says whether a main entry (1XX) exists
+	titleInd1 := byte('0')
+
+	for index, name := range entry.List.Name {
+		if index == 0 {
+			rec.AddDataField("100", '1', ' ', Subfield{'a', name}, Subfield{'e', "author"})
+			titleInd1 = '1'
+			continue
+		}
+		rec.AddDataField("700", '1', ' ', Subfield{'a', name}, Subfield{'e', "author"})
+	}
+
+	rec.AddDataField("245", titleInd1, '0', Subfield{'a', entry.Book.Title})
+
+	rec.AddDataField("264", ' ', '1',
+		Subfield{'b', entry.Book.Publisher},
+		Subfield{'c', strconv.Itoa(int(entry.Book.Year))},
+	)
+
+	rec.AddDataField("300", ' ', ' ', Subfield{'a', fmt.Sprintf("%d pages", entry.Book.PageCount)})
+
+	for _, genre := range entry.Book.Genres {
+		rec.AddDataField("655", ' ', '7', Subfield{'a', genre}, Subfield{'2', "local"})
+	}
+
+	// 655 would sort before 700 in a well-formed record
+	sortFields(rec)
+
+	return rec
+}
+
+// 008 for books, with the date entered and a single known date.
+func fixedData(entry *books.ReadEntry) string {
+	entered := entry.LastModified
+	if entered.IsZero() {
+		entered = time.Now()
+	}
+
+	data := []byte(strings.Repeat("|", 40))
+	copy(data[0:6], entered.UTC().Format("060102"))
+	data[6] = 's'
+	copy(data[7:11], fmt.Sprintf("%04d", entry.Book.Year))
+	copy(data[11:15], "    ")
+	copy(data[15:18], "xx ")
+	copy(data[35:38], "und")
+
+	return string(data)
+}
+
+func sortFields(rec *Record) {
+	fields := rec.Fields
+	for i := 1; i < len(fields); i++ {
+		for j := i; j > 0 && fields[j].Tag < fields[j-1].Tag; j-- {
+			fields[j], fields[j-1] = fields[j-1], fields[j]
+		}
+	}
+}
+
+// ToEntry reads the fields FromEntry writes, the entry is not validated.
+func ToEntry(rec *Record) *books.CreateBookEntry {
+	entry := &books.CreateBookEntry{
+		Book:    &books.Book{},
+		Authors: &books.Authors{},
+	}
+
+	if f := rec.First("020"); f != nil {
+		// "0306406152 (pbk.)" style qualifiers follow the number
+		isbn, _, _ := strings.Cut(strings.TrimSpace(f.Subfield('a')), " ")
+		entry.Book.ISBN = books.NormalizeISBN(isbn)
+	}
+
+	if f := rec.First("245"); f != nil {
+		title := trimPunctuation(f.Subfield('a'))
+		if subtitle := trimPunctuation(f.Subfield('b')); subtitle != "" {
+			title += " : " + subtitle
+		}
+		entry.Book.Title = title
+	}
+
+	publication := rec.First("264")
+	if publication == nil {
+		publication = rec.First("260")
+	}
+	if publication != nil {
+		entry.Book.Publisher = trimPunctuation(publication.Subfield('b'))
+		if year := yearRX.FindString(publication.Subfield('c')); year != "" {
+			n, _ := strconv.Atoi(year)
+			entry.Book.Year = int32(n)
+		}
+	}
+
+	if f := rec.First("008"); entry.Book.Year == 0 && f != nil && len(f.Value) >= 11 {
+		if n, err := strconv.Atoi(f.Value[7:11]); err == nil {
+			entry.Book.Year = int32(n)
+		}
+	}
+
+	if f := rec.First("300"); f != nil {
+		if pages := pagesRX.FindString(f.Subfield('a')); pages != "" {
+			n, _ := strconv.Atoi(pages)
+			entry.Book.PageCount = int32(n)
+		}
+	}
+
+	for _, tag := range []string{"100", "700"} {
+		for _, f := range rec.FieldsByTag(tag) {
+			if name := trimPunctuation(f.Subfield('a')); name != "" {
+				entry.Authors.List = append(entry.Authors.List, name)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range []string{"655", "650"} {
+		for _, f := range rec.FieldsByTag(tag) {
+			genre := trimPunctuation(f.Subfield('a'))
+			if genre == "" || seen[genre] || len(entry.Book.Genres) == maxGenres {
+				continue
+			}
+			seen[genre] = true
+			entry.Book.Genres = append(entry.Book.Genres, genre)
+		}
+	}
+
+	return entry
+}
+
+// trimPunctuation removes trailing ISBD punctuation ("Dune /").
+func trimPunctuation(s string) string {
+	s = strings.TrimSpace(s)
+	for len(s) > 0 {
+		last := s[len(s)-1]
+		if last != '/' && last != ':' && last != ';' && last != ',' && last != '=' && last != '.' && last != ' ' {
+			break
+		}
+		// keep the period of initials such as "Frank A."
+		if last == '.' && len(s) > 2 && s[len(s)-3] == ' ' {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package marc_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/marc"
+)
+
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestISO2709RoundTrip(t *testing.T) {
+	data := readFile(t, "testdata/books.mrc")
+
+	records, err := marc.ReadAll(marc.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if title := records[1].First("245").Subfield('a'); title != "Cien años de soledad /" {
+		t.Errorf("got title %q", title)
+	}
+
+	var out bytes.Buffer
+	w := marc.NewWriter(&out)
+	for _, rec := range records {
+		if err := w.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("the records changed on the way back:\n%q\n%q", out.Bytes(), data)
+	}
+}
+
+func TestMARCXMLRoundTrip(t *testing.T) {
+	records, err := marc.ReadAll(marc.NewXMLReader(bytes.NewReader(readFile(t, "testdata/books.xml"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	w := marc.NewXMLWriter(&out)
+	for _, rec := range records {
+		if err := w.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := marc.ReadAll(marc.NewXMLReader(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, records) {
+		t.Errorf("the records changed on the way back through MARCXML")
+	}
+
+	// both samples hold the same records, the leaders aside
+	iso, err := marc.ReadAll(marc.NewReader(bytes.NewReader(readFile(t, "testdata/books.mrc"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range iso {
+		iso[i].Leader = records[i].Leader
+	}
+	if !reflect.DeepEqual(iso, records) {
+		t.Errorf("the ISO 2709 and MARCXML samples differ")
+	}
+}
+
+func TestUnmarshalMalformed(t *testing.T) {
+	rec := marc.NewRecord()
+	rec.AddControlField("001", "1")
+	rec.AddDataField("245", '1', '0', marc.Subfield{Code: 'a', Value: "Dune"})
+
+	valid, err := marc.Marshal(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the directory starts right after the leader, 12 bytes per field
+	tests := []struct {
+		name  string
+		patch func(data []byte)
+	}{
+		{"negative start", func(data []byte) { copy(data[24+12+7:], "-0099") }},
+		{"signed length", func(data []byte) { copy(data[24+12+3:], "+010") }},
+		{"start past the end", func(data []byte) { copy(data[24+12+7:], "99999") }},
+		{"length past the end", func(data []byte) { copy(data[24+3:], "9999") }},
+		{"blank start", func(data []byte) { copy(data[24+7:], "   00") }},
+		{"negative base", func(data []byte) { copy(data[12:], "-0001") }},
+		{"base past the end", func(data []byte) { copy(data[12:], "99999") }},
+		{"no terminator", func(data []byte) { data[len(data)-1] = ' ' }},
+	}
+
+	for _, tt := range tests {
+		data := append([]byte{}, valid...)
+		tt.patch(data)
+
+		if _, err := marc.Unmarshal(data); !errors.Is(err, marc.ErrMalformedRecord) {
+			t.Errorf("%s: got %v, want a malformed record error", tt.name, err)
+		}
+	}
+
+	if _, err := marc.NewReader(bytes.NewReader([]byte("-0030nam"))).Next(); !errors.Is(err, marc.ErrMalformedRecord) {
+		t.Errorf("signed record length: got %v, want a malformed record error", err)
+	}
+}
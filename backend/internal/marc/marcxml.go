@@ -0,0 +1,187 @@
+package marc
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+// XMLRecord is the MARCXML form of a record.
+type XMLRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Xmlns         string            `xml:"xmlns,attr,omitempty"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+func ToXML(rec *Record) *XMLRecord {
+	x := &XMLRecord{Leader: rec.Leader}
+
+	for _, f := range rec.Fields {
+		if f.IsControl() {
+			x.ControlFields = append(x.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+			continue
+		}
+
+		df := xmlDataField{Tag: f.Tag, Ind1: string(indicator(f.Ind1)), Ind2: string(indicator(f.Ind2))}
+		for _, sf := range f.Subfields {
+			df.Subfields = append(df.Subfields, xmlSubfield{Code: string(sf.Code), Value: sf.Value})
+		}
+		x.DataFields = append(x.DataFields, df)
+	}
+
+	return x
+}
+
+func FromXML(x *XMLRecord) (*Record, error) {
+	rec := &Record{Leader: x.Leader}
+
+	for _, cf := range x.ControlFields {
+		rec.Fields = append(rec.Fields, &Field{Tag: cf.Tag, Value: cf.Value})
+	}
+
+	for _, df := range x.DataFields {
+		if len(df.Ind1) > 1 || len(df.Ind2) > 1 {
+			return nil, fmt.Errorf("marcxml: invalid indicators in tag %s", df.Tag)
+		}
+
+		field := &Field{Tag: df.Tag, Ind1: firstByte(df.Ind1), Ind2: firstByte(df.Ind2)}
+		for _, sf := range df.Subfields {
+			if len(sf.Code) != 1 {
+				return nil, fmt.Errorf("marcxml: invalid subfield code %q in tag %s", sf.Code, df.Tag)
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: sf.Code[0], Value: sf.Value})
+		}
+		rec.Fields = append(rec.Fields, field)
+	}
+
+	return rec, nil
+}
+
+func firstByte(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
+
+// XMLReader reads a <record> or the records of a <collection>.
+type XMLReader struct {
+	dec *xml.Decoder
+}
+
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{dec: xml.NewDecoder(r)}
+}
+
+func (rd *XMLReader) Next() (*Record, error) {
+	for {
+		tok, err := rd.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var x XMLRecord
+		if err := rd.dec.DecodeElement(&x, &start); err != nil {
+			return nil, err
+		}
+
+		return FromXML(&x)
+	}
+}
+
+// XMLWriter writes a <collection> document, Close must be called to end it.
+type XMLWriter struct {
+	w       io.Writer
+	enc     *xml.Encoder
+	started bool
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	return &XMLWriter{w: w, enc: xml.NewEncoder(w)}
+}
+
+func (wr *XMLWriter) start() error {
+	if wr.started {
+		return nil
+	}
+	wr.started = true
+
+	if _, err := io.WriteString(wr.w, xml.Header); err != nil {
+		return err
+	}
+
+	return wr.enc.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "collection"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}},
+	})
+}
+
+func (wr *XMLWriter) Write(rec *Record) error {
+	if err := wr.start(); err != nil {
+		return err
+	}
+	return wr.enc.Encode(ToXML(rec))
+}
+
+func (wr *XMLWriter) Flush() error {
+	return wr.enc.Flush()
+}
+
+func (wr *XMLWriter) Close() error {
+	if err := wr.start(); err != nil {
+		return err
+	}
+
+	if err := wr.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+		return err
+	}
+
+	return wr.enc.Flush()
+}
+
+// RecordReader is implemented by both the ISO 2709 and the MARCXML readers.
+type RecordReader interface {
+	Next() (*Record, error)
+}
+
+func ReadAll(rd RecordReader) ([]*Record, error) {
+	records := []*Record{}
+
+	for {
+		rec, err := rd.Next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
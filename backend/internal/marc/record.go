@@ -0,0 +1,80 @@
+package marc
+
+import (
+	"strings"
+)
+
+const (
+	leaderLength     = 24
+	directoryEntry   = 12
+	subfieldDelim    = 0x1F
+	fieldTerminator  = 0x1E
+	recordTerminator = 0x1D
+)
+
+// defaultLeader is a new UTF-8 monograph, length and base address come later.
+const defaultLeader = "00000nam a2200000 i 4500"
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// Field is a control field (001 to 009) or a data field.
+type Field struct {
+	Tag       string
+	Value     string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+func (f *Field) IsControl() bool {
+	return strings.HasPrefix(f.Tag, "00")
+}
+
+// Subfield returns the first subfield with the given code.
+func (f *Field) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+type Record struct {
+	Leader string
+	Fields []*Field
+}
+
+func NewRecord() *Record {
+	return &Record{Leader: defaultLeader}
+}
+
+func (r *Record) AddControlField(tag, value string) {
+	r.Fields = append(r.Fields, &Field{Tag: tag, Value: value})
+}
+
+func (r *Record) AddDataField(tag string, ind1, ind2 byte, subfields ...Subfield) {
+	r.Fields = append(r.Fields, &Field{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: subfields})
+}
+
+func (r *Record) FieldsByTag(tag string) []*Field {
+	fields := []*Field{}
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func (r *Record) First(tag string) *Field {
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+type authors struct {
+	s *Store
+}
+
+func (a authors) BeginTx(ctx context.Context) (books.Tx, error) {
+	return a.s.BeginTx(ctx)
+}
+
+// linkedBooks are the live books of an author in id order.
+func (c *catalogue) linkedBooks(hash string) []*book {
+	return c.live(func(b *book) bool {
+		return hasString(b.authors, hash)
+	})
+}
+
+// AuthorGet fills read like the SQL model.
+func (a authors) AuthorGet(ctx context.Context, tx books.Tx, read *books.ReadAuthorEntry) error {
+	c, err := a.s.view(tx)
+	if err != nil {
+		return err
+	}
+
+	hash, ok := c.authorIDs[read.Author.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	linked := c.linkedBooks(hash)
+	if len(linked) == 0 {
+		return sql.ErrNoRows
+	}
+
+	author := c.authors[hash]
+	read.Author.Identifier = author.Identifier
+	read.Author.Name = author.Name
+	read.Author.Books_authored = author.Books_authored
+	read.LastModified = author.updatedAt
+	read.BookList = books.ReadBookList{}
+
+	for _, b := range linked {
+		read.BookList.ID = append(read.BookList.ID, b.ID)
+		read.BookList.Hash = append(read.BookList.Hash, b.Hash)
+		read.BookList.Title = append(read.BookList.Title, b.Title)
+		read.BookList.Publisher = append(read.BookList.Publisher, b.Publisher)
+		read.BookList.Year = append(read.BookList.Year, b.Year)
+		read.BookList.PageCount = append(read.BookList.PageCount, b.PageCount)
+
+		if b.updatedAt.After(read.LastModified) {
+			read.LastModified = b.updatedAt
+		}
+	}
+
+	return nil
+}
+
+func (a authors) ListAuthors(ctx context.Context, filters internal.Filters) ([]*books.AuthorSummary, internal.Metadata, error) {
+	c := a.s.committed()
+
+	summaries := []*books.AuthorSummary{}
+	for _, author := range c.authors {
+		if author.Books_authored > 0 {
+			summaries = append(summaries, &books.AuthorSummary{
+				ID:            author.ID,
+				Name:          author.Name,
+				BooksAuthored: author.Books_authored,
+			})
+		}
+	}
+
+	summaries, metadata, err := page(summaries, filters, compareAuthors, func(s *books.AuthorSummary) int64 { return s.ID })
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return summaries, metadata, nil
+}
+
+func compareAuthors(column string, a, b *books.AuthorSummary) int {
+	switch column {
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	case "books_authored":
+		return compareInts(a.BooksAuthored, b.BooksAuthored)
+	}
+	return compareInts(a.ID, b.ID)
+}
+
+func (a authors) BooksByAuthors(ctx context.Context, authorIDs []int64) (map[int64][]*books.ReadEntry, error) {
+	c := a.s.committed()
+
+	byAuthor := make(map[int64][]*books.ReadEntry, len(authorIDs))
+
+	for _, id := range authorIDs {
+		hash, ok := c.authorIDs[id]
+		if !ok {
+			continue
+		}
+
+		for _, b := range c.linkedBooks(hash) {
+			byAuthor[id] = append(byAuthor[id], c.entry(b))
+		}
+	}
+
+	return byAuthor, nil
+}
+
+// MergeAuthors moves the books of from over to into and drops from.
+func (a authors) MergeAuthors(ctx context.Context, t books.Tx, into, from int64) (*books.ReadAuthor, error) {
+	tx, err := a.s.unwrap(t)
+	if err != nil {
+		return nil, err
+	}
+	data := tx.data
+
+	sourceHash, ok := data.authorIDs[from]
+	if !ok {
+		return nil, books.ErrNotFound
+	}
+	targetHash, ok := data.authorIDs[into]
+	if !ok {
+		return nil, books.ErrNotFound
+	}
+
+	source := data.authors[sourceHash]
+	target := data.authors[targetHash]
+	before := target.ReadAuthor
+
+	ids := make([]int64, 0, len(data.books))
+	for id := range data.books {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var count int32
+
+	for _, id := range ids {
+		b := data.books[id]
+
+		if hasString(b.authors, sourceHash) {
+			b.updatedAt = tx.now
+
+			linked := make([]string, 0, len(b.authors))
+			for _, hash := range b.authors {
+				switch {
+				case hash != sourceHash:
+					linked = append(linked, hash)
+				case !hasString(b.authors, targetHash):
+					linked = append(linked, targetHash)
+				}
+			}
+			b.authors = linked
+		}
+
+		if !b.deleted() && hasString(b.authors, targetHash) {
+			count++
+		}
+	}
+
+	target.Books_authored = count
+	target.updatedAt = tx.now
+
+	delete(data.authors, sourceHash)
+	delete(data.authorIDs, from)
+
+	merged := target.ReadAuthor
+
+	err = tx.record(ctx, authorEntity, target.ID, audit.ActionMerge,
+		map[string]books.ReadAuthor{"into": before, "from": source.ReadAuthor},
+		&merged,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &merged, nil
+}
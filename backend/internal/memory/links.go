@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+type links struct {
+	s *Store
+}
+
+func (l links) BeginTx(ctx context.Context) (books.Tx, error) {
+	return l.s.BeginTx(ctx)
+}
+
+func (l links) Linked(ctx context.Context, tx books.Tx, bookHash string) ([]string, error) {
+	c, err := l.s.view(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := c.books[c.bookIDs[bookHash]]
+	if !ok {
+		return []string{}, nil
+	}
+
+	return append([]string{}, b.authors...), nil
+}
+
+// Link appends the authors a book is not linked to yet.
+func (l links) Link(ctx context.Context, t books.Tx, bookHash string, authorHashes []string) error {
+	tx, err := l.s.unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	b, ok := tx.data.books[tx.data.bookIDs[bookHash]]
+	if !ok {
+		return fmt.Errorf("%w: book %s", books.ErrNotFound, bookHash)
+	}
+
+	linked := append([]string{}, b.authors...)
+	for _, hash := range authorHashes {
+		if _, ok := tx.data.authors[hash]; !ok {
+			return fmt.Errorf("%w: author %s", books.ErrNotFound, hash)
+		}
+		if !hasString(linked, hash) {
+			linked = append(linked, hash)
+		}
+	}
+
+	b.authors = linked
+	return nil
+}
+
+func (l links) Unlink(ctx context.Context, t books.Tx, bookHash string, authorHashes []string) error {
+	tx, err := l.s.unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	b, ok := tx.data.books[tx.data.bookIDs[bookHash]]
+	if !ok {
+		return nil
+	}
+
+	linked := make([]string, 0, len(b.authors))
+	for _, hash := range b.authors {
+		if !hasString(authorHashes, hash) {
+			linked = append(linked, hash)
+		}
+	}
+
+	b.authors = linked
+	return nil
+}
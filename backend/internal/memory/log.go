@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/users"
+)
+
+type revisions struct {
+	s *Store
+}
+
+// List returns the revisions of a book, newest first.
+func (r revisions) List(ctx context.Context, bookID int64) ([]*books.Revision, error) {
+	stored := r.s.committed().revisions[bookID]
+
+	list := make([]*books.Revision, len(stored))
+	for i, revision := range stored {
+		copied := *revision
+		list[len(stored)-1-i] = &copied
+	}
+
+	return list, nil
+}
+
+func (r revisions) Get(ctx context.Context, bookID int64, version int32) (*books.Revision, error) {
+	for _, revision := range r.s.committed().revisions[bookID] {
+		if revision.Version == version {
+			copied := *revision
+			return &copied, nil
+		}
+	}
+
+	return nil, books.ErrNotFound
+}
+
+func (r revisions) Previous(ctx context.Context, bookID int64, version int32) (*books.Revision, error) {
+	var previous *books.Revision
+	for _, revision := range r.s.committed().revisions[bookID] {
+		if revision.Version < version && (previous == nil || revision.Version > previous.Version) {
+			previous = revision
+		}
+	}
+
+	if previous == nil {
+		return nil, books.ErrNotFound
+	}
+
+	copied := *previous
+	return &copied, nil
+}
+
+type auditLog struct {
+	s *Store
+}
+
+func (l auditLog) List(ctx context.Context, filter audit.Filter, filters internal.Filters) ([]*audit.Entry, internal.Metadata, error) {
+	entries := []*audit.Entry{}
+
+	for _, entry := range l.s.committed().audit {
+		if (filter.Actor != "" && entry.Actor != filter.Actor) ||
+			(filter.Entity != "" && entry.Entity != filter.Entity) ||
+			(filter.EntityID != 0 && entry.EntityID != filter.EntityID) ||
+			(filter.Action != "" && entry.Action != filter.Action) ||
+			(filter.RequestID != "" && entry.RequestID != filter.RequestID) ||
+			(!filter.Since.IsZero() && entry.CreatedAt.Before(filter.Since)) ||
+			(!filter.Until.IsZero() && !entry.CreatedAt.Before(filter.Until)) {
+			continue
+		}
+
+		copied := *entry
+		copied.Before = bytes.Clone(entry.Before)
+		copied.After = bytes.Clone(entry.After)
+		entries = append(entries, &copied)
+	}
+
+	entries, metadata, err := page(entries, filters, compareEntries, func(e *audit.Entry) int64 { return e.ID })
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return entries, metadata, nil
+}
+
+func compareEntries(column string, a, b *audit.Entry) int {
+	if column == "created_at" {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+	return compareInts(a.ID, b.ID)
+}
+
+type userList struct {
+	s *Store
+}
+
+func (l userList) Create(ctx context.Context, user *users.User, tokenHash string) error {
+	return l.s.run(ctx, func(tx *Tx) error {
+		for _, existing := range tx.data.users {
+			if existing.Name == user.Name {
+				return users.ErrDuplicateName
+			}
+		}
+
+		tx.data.lastUser++
+		user.ID = tx.data.lastUser
+
+		copied := *user
+		tx.data.users[tokenHash] = &copied
+		return nil
+	})
+}
+
+func (l userList) ByTokenHash(ctx context.Context, tokenHash string) (*users.User, error) {
+	user, ok := l.s.committed().users[tokenHash]
+	if !ok {
+		return nil, users.ErrNotFound
+	}
+
+	copied := *user
+	return &copied, nil
+}
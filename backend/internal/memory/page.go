@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+)
+
+// page sorts items like the SQL queries and cuts out the requested page.
+func page[T any](items []T, filters internal.Filters, compare func(column string, a, b T) int, id func(T) int64) ([]T, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	descending := filters.SortDirection() == "DESC"
+
+	sort.SliceStable(items, func(i, j int) bool {
+		c := compare(column, items[i], items[j])
+		if descending {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return id(items[i]) < id(items[j])
+	})
+
+	total := len(items)
+	start := min(max(filters.Offset(), 0), total)
+	end := min(start+max(filters.Limit(), 0), total)
+
+	//an empty page has no count, as in SQL
+	if start == end {
+		return items[:0], internal.Metadata{}, nil
+	}
+
+	return items[start:end], internal.CalculateMetadata(total, filters.Page, filters.PageSize), nil
+}
+
+func compareInts[T int64 | int32](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// contains matches like ILIKE '%term%'.
+func contains(s, term string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(term))
+}
@@ -0,0 +1,449 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+type reader struct {
+	s *Store
+}
+
+// Get fills read like the SQL model, Authors is left to the caller.
+func (r reader) Get(ctx context.Context, tx books.Tx, read *books.ReadEntry) error {
+	c, err := r.s.view(tx)
+	if err != nil {
+		return err
+	}
+
+	b := c.books[read.Book.ID]
+	if !c.visible(b) {
+		return sql.ErrNoRows
+	}
+
+	entry := c.entry(b)
+	read.Book = entry.Book
+	read.List = entry.List
+	read.LastModified = entry.LastModified
+
+	return nil
+}
+
+func (r reader) GetMany(ctx context.Context, ids []int64) ([]*books.ReadEntry, error) {
+	c := r.s.committed()
+
+	entries := make([]*books.ReadEntry, 0, len(ids))
+	seen := map[int64]bool{}
+
+	for _, id := range ids {
+		b := c.books[id]
+		if !c.visible(b) || seen[id] {
+			continue
+		}
+		seen[id] = true
+		entries = append(entries, c.entry(b))
+	}
+
+	return entries, nil
+}
+
+// live returns the visible books matching keep in id order.
+func (c *catalogue) live(keep func(b *book) bool) []*book {
+	matched := []*book{}
+	for _, b := range c.books {
+		if c.visible(b) && keep(b) {
+			matched = append(matched, b)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ID < matched[j].ID
+	})
+
+	return matched
+}
+
+func (c *catalogue) authorNames(b *book) []string {
+	names := make([]string, len(b.authors))
+	for i, hash := range b.authors {
+		names[i] = c.authors[hash].Name
+	}
+	return names
+}
+
+func hasString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (r reader) List(ctx context.Context, filter books.ListFilter, filters internal.Filters) ([]*books.ReadEntry, internal.Metadata, error) {
+	c := r.s.committed()
+	query := strings.TrimSpace(filter.Query)
+
+	matched := c.live(func(b *book) bool {
+		if query != "" && !contains(b.Title, query) && !contains(b.Publisher, query) {
+			found := false
+			for _, name := range c.authorNames(b) {
+				found = found || contains(name, query)
+			}
+			if !found {
+				return false
+			}
+		}
+
+		if filter.Genre != "" && !hasString(b.Genres, filter.Genre) {
+			return false
+		}
+
+		if filter.AuthorID != 0 && !hasString(b.authors, c.authorIDs[filter.AuthorID]) {
+			return false
+		}
+
+		return true
+	})
+
+	matched, metadata, err := page(matched, filters, compareBooks, func(b *book) int64 { return b.ID })
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	entries := make([]*books.ReadEntry, len(matched))
+	for i, b := range matched {
+		entries[i] = c.entry(b)
+	}
+
+	return entries, metadata, nil
+}
+
+func compareBooks(column string, a, b *book) int {
+	switch column {
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "year":
+		return compareInts(a.Year, b.Year)
+	case "created_at":
+		return a.createdAt.Compare(b.createdAt)
+	}
+	return compareInts(a.ID, b.ID)
+}
+
+func (r reader) GenreCounts(ctx context.Context) ([]*books.GenreCount, error) {
+	c := r.s.committed()
+
+	counts := map[string]int{}
+	for _, b := range c.live(func(*book) bool { return true }) {
+		for _, genre := range b.Genres {
+			counts[genre]++
+		}
+	}
+
+	genres := make([]*books.GenreCount, 0, len(counts))
+	for genre, count := range counts {
+		genres = append(genres, &books.GenreCount{Genre: genre, Books: count})
+	}
+
+	sort.Slice(genres, func(i, j int) bool {
+		return genres[i].Genre < genres[j].Genre
+	})
+
+	return genres, nil
+}
+
+func (r reader) Stream(ctx context.Context, filter books.ExportFilter, fn func(*books.ReadEntry) error) error {
+	c := r.s.committed()
+
+	matched := c.live(func(b *book) bool {
+		return (filter.Genre == "" || hasString(b.Genres, filter.Genre)) &&
+			(filter.Publisher == "" || b.Publisher == filter.Publisher) &&
+			(filter.YearFrom == 0 || b.Year >= filter.YearFrom) &&
+			(filter.YearTo == 0 || b.Year <= filter.YearTo)
+	})
+
+	for _, b := range matched {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entry := c.entry(b)
+		if !filter.ModifiedSince.IsZero() && entry.LastModified.Before(filter.ModifiedSince) {
+			continue
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r reader) Search(ctx context.Context, cond books.Condition, limit, offset int) ([]*books.ReadEntry, int, error) {
+	c := r.s.committed()
+
+	match, err := c.condition(cond)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matched := c.live(match)
+	total := len(matched)
+
+	matched = matched[min(offset, total):min(offset+limit, total)]
+
+	entries := make([]*books.ReadEntry, len(matched))
+	for i, b := range matched {
+		entries[i] = c.entry(b)
+	}
+
+	return entries, total, nil
+}
+
+// condition compiles a search into a predicate over the books.
+func (c *catalogue) condition(cond books.Condition) (func(b *book) bool, error) {
+	switch cond := cond.(type) {
+	case books.And:
+		l, r, err := c.pair(cond.Left, cond.Right)
+		if err != nil {
+			return nil, err
+		}
+		return func(b *book) bool { return l(b) && r(b) }, nil
+
+	case books.Or:
+		l, r, err := c.pair(cond.Left, cond.Right)
+		if err != nil {
+			return nil, err
+		}
+		return func(b *book) bool { return l(b) || r(b) }, nil
+
+	case books.Not:
+		inner, err := c.condition(cond.Condition)
+		if err != nil {
+			return nil, err
+		}
+		return func(b *book) bool { return !inner(b) }, nil
+
+	case books.Everything:
+		return func(*book) bool { return true }, nil
+
+	case books.TextMatch:
+		re := cond.Pattern.Regexp()
+		return c.text(cond.Field, re.MatchString)
+
+	case books.TextCompare:
+		if !cond.Op.Ordering() {
+			break
+		}
+		value := strings.ToLower(cond.Value)
+		return c.text(cond.Field, func(s string) bool {
+			return cond.Op.Compare(strings.Compare(strings.ToLower(s), value))
+		})
+
+	case books.YearCompare:
+		if !cond.Op.Valid() {
+			break
+		}
+		return func(b *book) bool {
+			return cond.Op.Compare(compareInts(int64(b.Year), int64(cond.Year)))
+		}, nil
+
+	case books.ISBNIs:
+		return func(b *book) bool { return b.ISBN == cond.ISBN }, nil
+	}
+
+	return nil, fmt.Errorf("%w: %#v", books.ErrUnsupported, cond)
+}
+
+func (c *catalogue) pair(left, right books.Condition) (func(b *book) bool, func(b *book) bool, error) {
+	l, err := c.condition(left)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := c.condition(right)
+	return l, r, err
+}
+
+// text applies match to any value of a field.
+func (c *catalogue) text(field books.TextField, match func(string) bool) (func(b *book) bool, error) {
+	switch field {
+	case books.FieldTitle:
+		return func(b *book) bool { return match(b.Title) }, nil
+	case books.FieldPublisher:
+		return func(b *book) bool { return match(b.Publisher) }, nil
+	case books.FieldCreator:
+		return func(b *book) bool { return slices.ContainsFunc(c.authorNames(b), match) }, nil
+	case books.FieldSubject:
+		return func(b *book) bool { return slices.ContainsFunc(b.Genres, match) }, nil
+	}
+
+	return nil, fmt.Errorf("%w: field %d", books.ErrUnsupported, field)
+}
+
+func (r reader) Harvest(ctx context.Context, filter books.HarvestFilter) ([]*books.HarvestRecord, error) {
+	c := r.s.committed()
+
+	ids := make([]int64, 0, len(c.books))
+	for id := range c.books {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	records := []*books.HarvestRecord{}
+
+	for _, id := range ids {
+		b := c.books[id]
+
+		if len(b.authors) == 0 || id <= filter.AfterID || (filter.ID != 0 && id != filter.ID) {
+			continue
+		}
+
+		if len(filter.Genres) > 0 {
+			overlap := false
+			for _, genre := range filter.Genres {
+				overlap = overlap || hasString(b.Genres, genre)
+			}
+			if !overlap {
+				continue
+			}
+		}
+
+		rec := &books.HarvestRecord{Entry: c.entry(b), Deleted: b.deleted()}
+		rec.Datestamp = rec.Entry.LastModified
+		if rec.Deleted {
+			rec.Datestamp = b.deletedAt
+		}
+
+		if (!filter.From.IsZero() && rec.Datestamp.Before(filter.From)) ||
+			(!filter.Until.IsZero() && rec.Datestamp.After(filter.Until)) {
+			continue
+		}
+
+		if len(records) == filter.Limit {
+			break
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// Genres lists every genre, trashed books included.
+func (r reader) Genres(ctx context.Context) ([]string, error) {
+	seen := map[string]bool{}
+	genres := []string{}
+
+	for _, b := range r.s.committed().books {
+		for _, genre := range b.Genres {
+			if !seen[genre] {
+				seen[genre] = true
+				genres = append(genres, genre)
+			}
+		}
+	}
+
+	sort.Strings(genres)
+
+	return genres, nil
+}
+
+func (r reader) EarliestDatestamp(ctx context.Context) (time.Time, error) {
+	var earliest time.Time
+
+	for _, b := range r.s.committed().books {
+		if earliest.IsZero() || b.updatedAt.Before(earliest) {
+			earliest = b.updatedAt
+		}
+	}
+
+	return earliest, nil
+}
+
+// Verify runs the checks of the SQL model, in the same order.
+func (r reader) Verify(ctx context.Context) ([]books.Problem, error) {
+	c := r.s.committed()
+
+	bookIDs := make([]int64, 0, len(c.books))
+	for id := range c.books {
+		bookIDs = append(bookIDs, id)
+	}
+	sort.Slice(bookIDs, func(i, j int) bool { return bookIDs[i] < bookIDs[j] })
+
+	authorIDs := make([]int64, 0, len(c.authorIDs))
+	for id := range c.authorIDs {
+		authorIDs = append(authorIDs, id)
+	}
+	sort.Slice(authorIDs, func(i, j int) bool { return authorIDs[i] < authorIDs[j] })
+
+	linked := map[string]int{}
+	liveLinked := map[string]int32{}
+	for _, b := range c.books {
+		for _, hash := range b.authors {
+			linked[hash]++
+			if !b.deleted() {
+				liveLinked[hash]++
+			}
+		}
+	}
+
+	problems := []books.Problem{}
+	add := func(check, entity string, id int64, detail string) {
+		problems = append(problems, books.Problem{Check: check, Entity: entity, ID: id, Detail: detail})
+	}
+
+	for _, id := range authorIDs {
+		a := c.authors[c.authorIDs[id]]
+		if count := liveLinked[a.Identifier]; a.Books_authored != count {
+			add("author_counter", authorEntity, id, fmt.Sprintf("books_authored is %d, %d books are linked", a.Books_authored, count))
+		}
+	}
+	for _, id := range authorIDs {
+		if linked[c.authorIDs[id]] == 0 {
+			add("orphan_author", authorEntity, id, "no book links to the author")
+		}
+	}
+	for _, id := range bookIDs {
+		if len(c.books[id].authors) == 0 {
+			add("book_without_authors", bookEntity, id, "the book has no authors")
+		}
+	}
+	for _, id := range bookIDs {
+		b := c.books[id]
+		found := false
+		for _, revision := range c.revisions[id] {
+			found = found || revision.Version == b.Version
+		}
+		if !found {
+			add("missing_revision", bookEntity, id, fmt.Sprintf("no revision for version %d", b.Version))
+		}
+	}
+
+	checkHash := func(check, entity string, id int64, hash, source string) {
+		sum := sha1.Sum([]byte(source))
+		if want := hex.EncodeToString(sum[:]); hash != want {
+			add(check, entity, id, fmt.Sprintf("hash %s does not match %q, expected %s", hash, source, want))
+		}
+	}
+	for _, id := range bookIDs {
+		checkHash("book_hash", bookEntity, id, c.books[id].Hash, c.books[id].Title)
+	}
+	for _, id := range authorIDs {
+		a := c.authors[c.authorIDs[id]]
+		checkHash("author_hash", authorEntity, id, a.Identifier, a.Name)
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,323 @@
+// Package memory keeps the catalogue in memory, for the tests and -storage=memory.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/users"
+)
+
+// entity names of the audit log entries, the same as the SQL models use
+const (
+	bookEntity   = "book"
+	authorEntity = "author"
+)
+
+type book struct {
+	books.ReadBook
+	//the book_author_link rows
+	authors   []string
+	createdAt time.Time
+	updatedAt time.Time
+	deletedAt time.Time
+}
+
+func (b *book) deleted() bool {
+	return !b.deletedAt.IsZero()
+}
+
+type author struct {
+	books.ReadAuthor
+	updatedAt time.Time
+}
+
+type catalogue struct {
+	books     map[int64]*book
+	bookIDs   map[string]int64
+	authors   map[string]*author
+	authorIDs map[int64]string
+	revisions map[int64][]*books.Revision
+	audit     []*audit.Entry
+	// users are keyed by the hash of their token
+	users map[string]*users.User
+
+	lastBook, lastAuthor, lastAudit, lastUser int64
+}
+
+func newCatalogue() *catalogue {
+	return &catalogue{
+		books:     map[int64]*book{},
+		bookIDs:   map[string]int64{},
+		authors:   map[string]*author{},
+		authorIDs: map[int64]string{},
+		revisions: map[int64][]*books.Revision{},
+		users:     map[string]*users.User{},
+	}
+}
+
+// clone copies everything a transaction may change in place.
+func (c *catalogue) clone() *catalogue {
+	copied := &catalogue{
+		books:      make(map[int64]*book, len(c.books)),
+		bookIDs:    make(map[string]int64, len(c.bookIDs)),
+		authors:    make(map[string]*author, len(c.authors)),
+		authorIDs:  make(map[int64]string, len(c.authorIDs)),
+		revisions:  make(map[int64][]*books.Revision, len(c.revisions)),
+		audit:      c.audit,
+		users:      make(map[string]*users.User, len(c.users)),
+		lastBook:   c.lastBook,
+		lastAuthor: c.lastAuthor,
+		lastAudit:  c.lastAudit,
+		lastUser:   c.lastUser,
+	}
+
+	for id, b := range c.books {
+		bookCopy := *b
+		copied.books[id] = &bookCopy
+	}
+	for hash, id := range c.bookIDs {
+		copied.bookIDs[hash] = id
+	}
+	for hash, a := range c.authors {
+		authorCopy := *a
+		copied.authors[hash] = &authorCopy
+	}
+	for id, hash := range c.authorIDs {
+		copied.authorIDs[id] = hash
+	}
+	for id, revisions := range c.revisions {
+		copied.revisions[id] = revisions
+	}
+	for hash, user := range c.users {
+		copied.users[hash] = user
+	}
+
+	return copied
+}
+
+// visible books are live and have an author, as the SQL joins return.
+func (c *catalogue) visible(b *book) bool {
+	return b != nil && !b.deleted() && len(b.authors) > 0
+}
+
+// entry builds the ReadEntry of a book as the SQL models read it.
+func (c *catalogue) entry(b *book) *books.ReadEntry {
+	entry := &books.ReadEntry{Book: b.ReadBook, LastModified: b.updatedAt}
+	entry.Book.Genres = append([]string(nil), b.Genres...)
+
+	for _, hash := range b.authors {
+		a := c.authors[hash]
+		entry.List.ID = append(entry.List.ID, a.ID)
+		entry.List.Name = append(entry.List.Name, a.Name)
+		entry.List.Identifier = append(entry.List.Identifier, a.Identifier)
+		entry.List.Books_authored = append(entry.List.Books_authored, a.Books_authored)
+
+		if a.updatedAt.After(entry.LastModified) {
+			entry.LastModified = a.updatedAt
+		}
+	}
+
+	entry.Authors = make([]books.ReadAuthor, len(entry.List.Name))
+	entry.Convert()
+
+	return entry
+}
+
+// Store is the in-memory catalogue.
+type Store struct {
+	mu    sync.RWMutex
+	data  *catalogue
+	write chan struct{}
+	//time.Now unless a test sets it
+	Now func() time.Time
+}
+
+func New() *Store {
+	return &Store{
+		data:  newCatalogue(),
+		write: make(chan struct{}, 1),
+		Now:   time.Now,
+	}
+}
+
+func (s *Store) committed() *catalogue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data
+}
+
+// view is what a read sees, the transaction's copy when it runs in one.
+func (s *Store) view(tx books.Tx) (*catalogue, error) {
+	if tx == nil {
+		return s.committed(), nil
+	}
+
+	t, err := s.unwrap(tx)
+	if err != nil {
+		return nil, err
+	}
+	return t.data, nil
+}
+
+func (s *Store) unwrap(tx books.Tx) (*Tx, error) {
+	t, ok := tx.(*Tx)
+	if !ok || t.store != s {
+		return nil, fmt.Errorf("%w: %T", books.ErrForeignTx, tx)
+	}
+	return t, nil
+}
+
+// BeginTx waits for the open transaction, if any, to finish.
+func (s *Store) BeginTx(ctx context.Context) (books.Tx, error) {
+	select {
+	case s.write <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return &Tx{store: s, data: s.committed().clone(), now: s.Now()}, nil
+}
+
+// Tx is a transaction of the store, its writes share one timestamp.
+type Tx struct {
+	store *Store
+	data  *catalogue
+	now   time.Time
+	done  bool
+}
+
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+
+	tx.store.mu.Lock()
+	tx.store.data = tx.data
+	tx.store.mu.Unlock()
+
+	tx.finish()
+
+	return nil
+}
+
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+
+	tx.finish()
+
+	return nil
+}
+
+func (tx *Tx) finish() {
+	tx.done = true
+	tx.data = nil
+	<-tx.store.write
+}
+
+// run is a write in a transaction of its own.
+func (s *Store) run(ctx context.Context, fn func(tx *Tx) error) error {
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx.(*Tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (tx *Tx) record(ctx context.Context, entity string, entityID int64, action string, before, after interface{}) error {
+	meta := audit.FromContext(ctx)
+
+	entry := &audit.Entry{
+		CreatedAt: tx.now,
+		Actor:     meta.Actor,
+		RequestID: meta.RequestID,
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+	}
+
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	tx.data.lastAudit++
+	entry.ID = tx.data.lastAudit
+	tx.data.audit = append(tx.data.audit, entry)
+
+	return nil
+}
+
+func (tx *Tx) revision(ctx context.Context, bookID int64, snapshot books.Snapshot) {
+	tx.data.revisions[bookID] = append(tx.data.revisions[bookID], &books.Revision{
+		BookID:    bookID,
+		Version:   snapshot.Version,
+		CreatedAt: tx.now,
+		Actor:     audit.FromContext(ctx).Actor,
+		Snapshot:  snapshot,
+	})
+}
+
+// linkAuthor adds one book to an author, creating it on the first one.
+func (tx *Tx) linkAuthor(name, hash string) *author {
+	a, ok := tx.data.authors[hash]
+	if !ok {
+		tx.data.lastAuthor++
+		a = &author{ReadAuthor: books.ReadAuthor{ID: tx.data.lastAuthor, Name: name, Identifier: hash}}
+		tx.data.authors[hash] = a
+		tx.data.authorIDs[a.ID] = hash
+	}
+
+	a.Books_authored++
+	a.updatedAt = tx.now
+
+	return a
+}
+
+// countAuthors moves the counters of the authors of b.
+func (tx *Tx) countAuthors(b *book, delta int32) error {
+	if len(b.authors) == 0 {
+		return books.ErrEditConflict
+	}
+
+	for _, hash := range b.authors {
+		a := tx.data.authors[hash]
+		a.Books_authored += delta
+		a.updatedAt = tx.now
+	}
+
+	return nil
+}
+
+// Models are the repositories of the store.
+func (s *Store) Read() books.ReadRepository          { return reader{s} }
+func (s *Store) Create() books.CreateRepository      { return creator{s} }
+func (s *Store) Update() books.UpdateRepository      { return updater{s} }
+func (s *Store) Delete() books.DeleteRepository      { return deleter{s} }
+func (s *Store) Authors() books.AuthorRepository     { return authors{s} }
+func (s *Store) Links() books.LinkRepository         { return links{s} }
+func (s *Store) Revisions() books.RevisionRepository { return revisions{s} }
+func (s *Store) Audit() audit.Repository             { return auditLog{s} }
+func (s *Store) Users() users.Repository             { return userList{s} }
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/storetest"
+)
+
+func newStore() *Store {
+	s := New()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.Now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return s
+}
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Repositories {
+		s := newStore()
+
+		return storetest.Repositories{
+			Read:      s.Read(),
+			Create:    s.Create(),
+			Update:    s.Update(),
+			Delete:    s.Delete(),
+			Authors:   s.Authors(),
+			Links:     s.Links(),
+			Revisions: s.Revisions(),
+			Audit:     s.Audit(),
+		}
+	})
+}
@@ -0,0 +1,324 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+type creator struct {
+	s *Store
+}
+
+func (c creator) BeginTx(ctx context.Context) (books.Tx, error) {
+	return c.s.BeginTx(ctx)
+}
+
+// Insert stores a normalized entry and counts its authors.
+func (c creator) Insert(ctx context.Context, t books.Tx, entry *books.CreateBookEntry, read *books.ReadEntry) error {
+	tx, err := c.s.unwrap(t)
+	if err != nil {
+		return err
+	}
+	data := tx.data
+
+	if id, ok := data.bookIDs[entry.Book.Hash]; ok {
+		return &books.DuplicateError{ID: id, Trashed: data.books[id].deleted()}
+	}
+
+	data.lastBook++
+
+	b := &book{
+		ReadBook: books.ReadBook{
+			ID:        data.lastBook,
+			Hash:      entry.Book.Hash,
+			Title:     entry.Book.Title,
+			Publisher: entry.Book.Publisher,
+			Year:      entry.Book.Year,
+			PageCount: entry.Book.PageCount,
+			Genres:    append([]string(nil), entry.Book.Genres...),
+			ISBN:      entry.Book.ISBN,
+			Version:   1,
+		},
+		createdAt: tx.now,
+		updatedAt: tx.now,
+	}
+
+	read.Book = b.ReadBook
+	read.Book.Genres = append([]string(nil), b.Genres...)
+	read.List = books.ReadAuthorList{}
+
+	for index, name := range entry.Authors.List {
+		hash := entry.Authors.Hash[index]
+		if hasString(b.authors, hash) {
+			continue
+		}
+
+		a := tx.linkAuthor(name, hash)
+		b.authors = append(b.authors, hash)
+
+		read.List.ID = append(read.List.ID, a.ID)
+		read.List.Name = append(read.List.Name, a.Name)
+		read.List.Identifier = append(read.List.Identifier, a.Identifier)
+		read.List.Books_authored = append(read.List.Books_authored, a.Books_authored)
+	}
+
+	data.books[b.ID] = b
+	data.bookIDs[b.Hash] = b.ID
+
+	snapshot := read.Snapshot()
+	tx.revision(ctx, b.ID, snapshot)
+
+	return tx.record(ctx, bookEntity, b.ID, audit.ActionInsert, nil, snapshot)
+}
+
+func (c creator) Save(ctx context.Context, entry *books.CreateBookEntry, read *books.ReadEntry) error {
+	err := c.s.run(ctx, func(tx *Tx) error {
+		return c.Insert(ctx, tx, entry, read)
+	})
+	if err != nil {
+		return err
+	}
+
+	read.Authors = make([]books.ReadAuthor, len(read.List.Name))
+	read.Convert()
+
+	return nil
+}
+
+// ExistingHashes counts trashed books too, they keep their hash.
+func (c creator) ExistingHashes(ctx context.Context, hashes []string) (map[string]bool, error) {
+	data := c.s.committed()
+	existing := map[string]bool{}
+
+	for _, hash := range hashes {
+		if _, ok := data.bookIDs[hash]; ok {
+			existing[hash] = true
+		}
+	}
+
+	return existing, nil
+}
+
+type updater struct {
+	s *Store
+}
+
+// Save writes entry over current when the version still matches.
+func (u updater) Save(ctx context.Context, entry *books.UpdateEntry, current *books.ReadEntry) error {
+	entry.HashEntries()
+
+	return u.s.run(ctx, func(tx *Tx) error {
+		data := tx.data
+
+		b := data.books[*entry.Book.ID]
+		if b == nil || b.deleted() || b.Version != *entry.Book.Version {
+			return books.ErrEditConflict
+		}
+
+		before := current.Snapshot()
+
+		isbn := b.ISBN
+		if entry.Book.ISBN != nil {
+			isbn = *entry.Book.ISBN
+		}
+
+		b.Title = *entry.Book.Title
+		b.Publisher = *entry.Book.Publisher
+		b.Year = *entry.Book.Year
+		b.PageCount = *entry.Book.PageCount
+		b.Genres = append([]string(nil), entry.Book.Genres...)
+		b.ISBN = isbn
+		b.Version++
+		b.updatedAt = tx.now
+
+		linked := []string{}
+		for index, hash := range entry.Author.Hash {
+			if hasString(linked, hash) {
+				continue
+			}
+			linked = append(linked, hash)
+
+			if !hasString(b.authors, hash) {
+				tx.linkAuthor(entry.Author.Name[index], hash)
+			}
+		}
+
+		for _, hash := range b.authors {
+			if !hasString(linked, hash) {
+				a := data.authors[hash]
+				a.Books_authored--
+				a.updatedAt = tx.now
+			}
+		}
+
+		b.authors = linked
+
+		updated := data.entry(b)
+		current.Book = updated.Book
+		current.List = updated.List
+		current.LastModified = updated.LastModified
+
+		after := entry.Snapshot(b.Version)
+		tx.revision(ctx, b.ID, after)
+
+		return tx.record(ctx, bookEntity, b.ID, audit.ActionUpdate, before, after)
+	})
+}
+
+type deleter struct {
+	s *Store
+}
+
+func (d deleter) BeginTx(ctx context.Context) (books.Tx, error) {
+	return d.s.BeginTx(ctx)
+}
+
+func (d deleter) DeleteBook(ctx context.Context, t books.Tx, id *books.DeleteID) error {
+	tx, err := d.s.unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	b := tx.data.books[id.ID]
+	if !tx.data.visible(b) {
+		return books.ErrNotFound
+	}
+
+	before := tx.data.entry(b).Snapshot()
+
+	b.deletedAt = tx.now
+	b.updatedAt = tx.now
+	id.Hash = b.Hash
+
+	if err := tx.countAuthors(b, -1); err != nil {
+		return err
+	}
+
+	return tx.record(ctx, bookEntity, b.ID, audit.ActionDelete, before, nil)
+}
+
+func (d deleter) Restore(ctx context.Context, t books.Tx, id *books.DeleteID) error {
+	tx, err := d.s.unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	b := tx.data.books[id.ID]
+	if b == nil || !b.deleted() {
+		return books.ErrNotFound
+	}
+
+	b.deletedAt = time.Time{}
+	b.updatedAt = tx.now
+	id.Hash = b.Hash
+
+	if err := tx.countAuthors(b, 1); err != nil {
+		return err
+	}
+
+	return tx.record(ctx, bookEntity, b.ID, audit.ActionRestore, nil, tx.data.entry(b).Snapshot())
+}
+
+func (d deleter) ListTrash(ctx context.Context, filters internal.Filters) ([]*books.TrashedBook, internal.Metadata, error) {
+	data := d.s.committed()
+
+	trashed := []*books.TrashedBook{}
+	for _, b := range data.books {
+		if !b.deleted() || len(b.authors) == 0 {
+			continue
+		}
+
+		trashed = append(trashed, &books.TrashedBook{
+			ID:        b.ID,
+			Hash:      b.Hash,
+			Title:     b.Title,
+			Publisher: b.Publisher,
+			Year:      b.Year,
+			PageCount: b.PageCount,
+			Genres:    append([]string(nil), b.Genres...),
+			Version:   b.Version,
+			Authors:   data.authorNames(b),
+			DeletedAt: b.deletedAt,
+		})
+	}
+
+	trashed, metadata, err := page(trashed, filters, compareTrashed, func(b *books.TrashedBook) int64 { return b.ID })
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return trashed, metadata, nil
+}
+
+func compareTrashed(column string, a, b *books.TrashedBook) int {
+	switch column {
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "deleted_at":
+		return a.DeletedAt.Compare(b.DeletedAt)
+	}
+	return compareInts(a.ID, b.ID)
+}
+
+// purgedBook is the state the audit log keeps of a purged book.
+type purgedBook struct {
+	Hash  string `json:"hash"`
+	Title string `json:"title"`
+}
+
+// purge drops a trashed book with its links and revisions.
+func (tx *Tx) purge(ctx context.Context, b *book) error {
+	delete(tx.data.books, b.ID)
+	delete(tx.data.bookIDs, b.Hash)
+	delete(tx.data.revisions, b.ID)
+
+	return tx.record(ctx, bookEntity, b.ID, audit.ActionPurge, purgedBook{Hash: b.Hash, Title: b.Title}, nil)
+}
+
+func (d deleter) Purge(ctx context.Context, t books.Tx, id *books.DeleteID) error {
+	tx, err := d.s.unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	b := tx.data.books[id.ID]
+	if b == nil || !b.deleted() {
+		return books.ErrNotFound
+	}
+
+	id.Hash = b.Hash
+
+	return tx.purge(ctx, b)
+}
+
+func (d deleter) PurgeDeletedBefore(ctx context.Context, t books.Tx, before time.Time) (int64, error) {
+	tx, err := d.s.unwrap(t)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := []*book{}
+	for _, b := range tx.data.books {
+		if b.deleted() && b.deletedAt.Before(before) {
+			expired = append(expired, b)
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].ID < expired[j].ID
+	})
+
+	for _, b := range expired {
+		if err := tx.purge(ctx, b); err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(expired)), nil
+}
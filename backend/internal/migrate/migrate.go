@@ -0,0 +1,494 @@
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+var (
+	ErrChecksumMismatch = errors.New("migrate: an applied migration was modified")
+	ErrUnknownVersion   = errors.New("migrate: unknown version")
+	ErrNoDown           = errors.New("migrate: migration has no down file")
+	ErrLockLost         = errors.New("migrate: the migration lock was lost")
+	ErrLegacyDirty      = errors.New("migrate: the golang-migrate schema_migrations is dirty")
+)
+
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// Dialect holds the bookkeeping statements, Lock and Renew take the owner and
+// the lease in seconds.
+type Dialect struct {
+	Tables string
+	Lock   string
+	Renew  string
+	Unlock string
+	// Record takes the version, name and checksum
+	Record string
+	Forget string
+	// Legacy counts the golang-migrate schema_migrations tables
+	Legacy string
+}
+
+var Postgres = Dialect{
+	Tables: `
+		CREATE TABLE IF NOT EXISTS library_schema_migrations (
+			version bigint PRIMARY KEY,
+			name text NOT NULL,
+			checksum text NOT NULL,
+			applied_at timestamp with time zone NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS library_schema_migrations_lock (
+			id integer PRIMARY KEY,
+			owner text NOT NULL,
+			expires_at timestamp with time zone NOT NULL
+		);
+	`,
+	Lock: `
+		INSERT INTO library_schema_migrations_lock(id, owner, expires_at)
+		VALUES(1, $1, NOW() + $2 * INTERVAL '1 second')
+		ON CONFLICT (id) DO UPDATE
+		SET owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE library_schema_migrations_lock.expires_at < NOW()
+		RETURNING owner
+	`,
+	Renew: `
+		UPDATE library_schema_migrations_lock
+		SET expires_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE id = 1 AND owner = $1
+	`,
+	Unlock: `DELETE FROM library_schema_migrations_lock WHERE id = 1 AND owner = $1`,
+	Record: `INSERT INTO library_schema_migrations(version, name, checksum) VALUES($1, $2, $3)`,
+	Forget: `DELETE FROM library_schema_migrations WHERE version = $1`,
+	Legacy: `
+		SELECT count(*) FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'schema_migrations' AND column_name = 'dirty'
+	`,
+}
+
+// SQLite keeps the timestamps as datetime text.
+var SQLite = Dialect{
+	Tables: `
+		CREATE TABLE IF NOT EXISTS library_schema_migrations (
+			version integer PRIMARY KEY,
+			name text NOT NULL,
+			checksum text NOT NULL,
+			applied_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS library_schema_migrations_lock (
+			id integer PRIMARY KEY,
+			owner text NOT NULL,
+			expires_at datetime NOT NULL
+		);
+	`,
+	Lock: `
+		INSERT INTO library_schema_migrations_lock(id, owner, expires_at)
+		VALUES(1, ?1, datetime('now', '+' || ?2 || ' seconds'))
+		ON CONFLICT (id) DO UPDATE
+		SET owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE library_schema_migrations_lock.expires_at < datetime('now')
+		RETURNING owner
+	`,
+	Renew: `
+		UPDATE library_schema_migrations_lock
+		SET expires_at = datetime('now', '+' || ?2 || ' seconds')
+		WHERE id = 1 AND owner = ?1
+	`,
+	Unlock: `DELETE FROM library_schema_migrations_lock WHERE id = 1 AND owner = ?1`,
+	Record: `INSERT INTO library_schema_migrations(version, name, checksum) VALUES(?1, ?2, ?3)`,
+	Forget: `DELETE FROM library_schema_migrations WHERE version = ?1`,
+	Legacy: `SELECT count(*) FROM pragma_table_info('schema_migrations') WHERE name = 'dirty'`,
+}
+
+// Migrator applies migrations, one transaction each.
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+	Dialect    Dialect
+	// LockTimeout bounds the wait for another instance to finish migrating
+	LockTimeout time.Duration
+	// LockLease is renewed every third of it
+	LockLease time.Duration
+	// OnApply sees every migration applied or rolled back
+	OnApply func(m Migration, direction string, took time.Duration)
+}
+
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{DB: db, Migrations: migrations, Dialect: Postgres, LockTimeout: time.Minute, LockLease: time.Minute}, nil
+}
+
+// Status is a migration along with what the database knows about it.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the file no longer matches the applied checksum
+	Modified bool
+}
+
+type applied struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+func (m *Migrator) ensureTables(ctx context.Context) error {
+	_, err := m.DB.ExecContext(ctx, m.Dialect.Tables)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]applied, error) {
+	rows, err := m.DB.QueryContext(ctx, `SELECT version, checksum, applied_at FROM library_schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := map[int64]applied{}
+
+	for rows.Next() {
+		var version int64
+		var a applied
+
+		if err := rows.Scan(&version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = a
+	}
+
+	return versions, rows.Err()
+}
+
+// legacyVersion reads the version golang-migrate left, if it ever ran.
+func (m *Migrator) legacyVersion(ctx context.Context) (int64, bool, error) {
+	var tables int
+	if err := m.DB.QueryRowContext(ctx, m.Dialect.Legacy).Scan(&tables); err != nil || tables == 0 {
+		return 0, false, err
+	}
+
+	var version int64
+	var dirty bool
+
+	err := m.DB.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, err
+	case dirty:
+		return 0, false, fmt.Errorf("%w at version %d", ErrLegacyDirty, version)
+	}
+
+	return version, true, nil
+}
+
+// adopt records the migrations golang-migrate applied and renames its table,
+// so that rolling everything back later does not adopt them again.
+func (m *Migrator) adopt(ctx context.Context) (map[int64]applied, error) {
+	version, ok, err := m.legacyVersion(ctx)
+	if err != nil || !ok {
+		return map[int64]applied{}, err
+	}
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE schema_migrations RENAME TO golang_schema_migrations`); err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.Migrations {
+		if migration.Version > version {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, m.Dialect.Record, migration.Version, migration.Name, migration.Checksum); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return m.applied(ctx)
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+
+	versions, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	//golang-migrate's versions until the next up adopts them
+	var legacy int64
+	if len(versions) == 0 {
+		if legacy, _, err = m.legacyVersion(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, len(m.Migrations))
+	for i, migration := range m.Migrations {
+		statuses[i] = Status{Migration: migration, Applied: migration.Version <= legacy}
+
+		if a, ok := versions[migration.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = a.appliedAt
+			statuses[i].Modified = a.checksum != migration.Checksum
+		}
+	}
+
+	return statuses, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if len(m.Migrations) == 0 {
+		return nil, nil
+	}
+	return m.Goto(ctx, m.Migrations[len(m.Migrations)-1].Version)
+}
+
+// Down rolls back the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.locked(ctx, func(ctx context.Context, versions map[int64]applied) error {
+		for i := len(m.Migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.Migrations[i]
+			if _, ok := versions[migration.Version]; !ok {
+				continue
+			}
+
+			if err := m.run(ctx, migration, DirectionDown); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Goto applies or rolls back migrations up to version, 0 rolling back everything.
+func (m *Migrator) Goto(ctx context.Context, version int64) ([]Migration, error) {
+	known := version == 0
+	for _, migration := range m.Migrations {
+		known = known || migration.Version == version
+	}
+	if !known {
+		return nil, fmt.Errorf("%w %d", ErrUnknownVersion, version)
+	}
+
+	var done []Migration
+
+	err := m.locked(ctx, func(ctx context.Context, versions map[int64]applied) error {
+		for i := len(m.Migrations) - 1; i >= 0; i-- {
+			migration := m.Migrations[i]
+			if _, ok := versions[migration.Version]; !ok || migration.Version <= version {
+				continue
+			}
+
+			if err := m.run(ctx, migration, DirectionDown); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+
+		for _, migration := range m.Migrations {
+			if _, ok := versions[migration.Version]; ok || migration.Version > version {
+				continue
+			}
+
+			if err := m.run(ctx, migration, DirectionUp); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// locked runs fn holding the migration lock, losing it cancels ctx.
+func (m *Migrator) locked(ctx context.Context, fn func(context.Context, map[int64]applied) error) error {
+	if err := m.ensureTables(ctx); err != nil {
+		return err
+	}
+
+	ctx, release, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	versions, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(versions) == 0 {
+		if versions, err = m.adopt(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, migration := range m.Migrations {
+		if a, ok := versions[migration.Version]; ok && a.checksum != migration.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+
+	if err := fn(ctx, versions); err != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, ErrLockLost) {
+			return cause
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *Migrator) run(ctx context.Context, migration Migration, direction string) error {
+	script := migration.Up
+	if direction == DirectionDown {
+		script = migration.Down
+		if script == "" {
+			return fmt.Errorf("%w: %d_%s", ErrNoDown, migration.Version, migration.Name)
+		}
+	}
+
+	start := time.Now()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migrate: %d_%s %s: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	if direction == DirectionUp {
+		_, err = tx.ExecContext(ctx, m.Dialect.Record, migration.Version, migration.Name, migration.Checksum)
+	} else {
+		_, err = tx.ExecContext(ctx, m.Dialect.Forget, migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if m.OnApply != nil {
+		m.OnApply(migration, direction, time.Since(start))
+	}
+
+	return nil
+}
+
+// lock leases the row of library_schema_migrations_lock, CockroachDB ignores
+// pg_advisory_lock. The context ends with ErrLockLost.
+func (m *Migrator) lock(ctx context.Context) (context.Context, func(), error) {
+	b := make([]byte, 8)
+	rand.Read(b)
+	owner := hex.EncodeToString(b)
+
+	lease := int(max(m.LockLease, time.Second).Seconds())
+
+	wait, cancel := context.WithTimeout(ctx, m.LockTimeout)
+	defer cancel()
+
+	for {
+		var holder string
+		err := m.DB.QueryRowContext(wait, m.Dialect.Lock, owner, lease).Scan(&holder)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, err
+		}
+
+		select {
+		case <-wait.Done():
+			return nil, nil, fmt.Errorf("migrate: waiting for another instance to finish migrating: %w", wait.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	held, lost := context.WithCancelCause(ctx)
+	stop := make(chan struct{})
+	renewed := make(chan struct{})
+
+	go func() {
+		defer close(renewed)
+
+		ticker := time.NewTicker(time.Duration(lease) * time.Second / 3)
+		defer ticker.Stop()
+
+		renewedAt := time.Now()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-held.Done():
+				return
+			case <-ticker.C:
+			}
+
+			result, err := m.DB.ExecContext(held, m.Dialect.Renew, owner, lease)
+			if err != nil {
+				//retry until the lease runs out
+				if time.Since(renewedAt) >= time.Duration(lease)*time.Second {
+					lost(fmt.Errorf("%w: %v", ErrLockLost, err))
+					return
+				}
+				continue
+			}
+			if n, err := result.RowsAffected(); err == nil && n == 0 {
+				lost(ErrLockLost)
+				return
+			}
+			renewedAt = time.Now()
+		}
+	}()
+
+	return held, func() {
+		close(stop)
+		<-renewed
+		lost(nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		m.DB.ExecContext(ctx, m.Dialect.Unlock, owner)
+	}, nil
+}
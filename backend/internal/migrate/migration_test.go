@@ -0,0 +1,287 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/sqlite"
+	"github.com/3WDeveloper-GM/library_app/migrations"
+)
+
+func TestLoadEmbedded(t *testing.T) {
+	for name, fsys := range map[string]fs.FS{"cockroachdb": migrations.FS, "sqlite": migrations.SQLite} {
+		loaded, err := Load(fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded) == 0 {
+			t.Fatalf("no %s migrations embedded", name)
+		}
+
+		for i, m := range loaded {
+			if m.Version != int64(i+1) {
+				t.Errorf("%s migration %d has version %d, the versions should be contiguous", name, i, m.Version)
+			}
+			if m.Down == "" {
+				t.Errorf("%s migration %d (%s) has no down file", name, m.Version, m.Name)
+			}
+		}
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite.Open(sqlite.Scheme + ":" + filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := New(db, migrations.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Dialect = SQLite
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(m.Migrations) {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(m.Migrations))
+	}
+
+	if applied, err = m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("a second up applied %d migrations: %v", len(applied), err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if !s.Applied || s.Modified || s.AppliedAt.IsZero() {
+			t.Errorf("status of %d = %+v, want applied", s.Version, s)
+		}
+	}
+
+	if _, err := m.Down(ctx, len(m.Migrations)); err != nil {
+		t.Fatal(err)
+	}
+
+	var tables int
+	err = db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'books'`).Scan(&tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Error("the books table is left after rolling everything back")
+	}
+}
+
+// The lease outlives its length while renewed, and the holder learns when
+// another instance takes it over.
+func TestLockRenewal(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite.Open(sqlite.Scheme + ":" + filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	newMigrator := func() *Migrator {
+		m, err := New(db, migrations.SQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Dialect = SQLite
+		m.LockLease = time.Second
+		m.LockTimeout = 200 * time.Millisecond
+		return m
+	}
+
+	holder, other := newMigrator(), newMigrator()
+	if err := holder.ensureTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	held, release, err := holder.lock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(2200 * time.Millisecond)
+
+	if _, _, err := other.lock(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v taking a renewed lock, want a timeout", err)
+	}
+
+	if _, err := db.Exec(`UPDATE library_schema_migrations_lock SET owner = 'someone else'`); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-held.Done():
+		if !errors.Is(context.Cause(held), ErrLockLost) {
+			t.Errorf("got cause %v, want ErrLockLost", context.Cause(held))
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("the holder did not notice the lock was taken over")
+	}
+	release()
+
+	if _, err := db.Exec(`DELETE FROM library_schema_migrations_lock`); err != nil {
+		t.Fatal(err)
+	}
+	_, release, err = other.lock(ctx)
+	if err != nil {
+		t.Fatalf("got %v taking a released lock", err)
+	}
+	release()
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_second.up.sql":   {Data: []byte("SELECT 2;")},
+		"000001_first.up.sql":    {Data: []byte("SELECT 1;")},
+		"000001_first.down.sql":  {Data: []byte("SELECT -1;")},
+		"000010_tenth.up.sql":    {Data: []byte("SELECT 10;")},
+		"000010_tenth.down.sql":  {Data: []byte("SELECT -10;")},
+		"000002_second.down.sql": {Data: []byte("SELECT -2;")},
+	}
+
+	loaded, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var versions []int64
+	for _, m := range loaded {
+		versions = append(versions, m.Version)
+	}
+	if len(versions) != 3 || versions[0] != 1 || versions[1] != 2 || versions[2] != 10 {
+		t.Fatalf("versions = %v, want [1 2 10]", versions)
+	}
+
+	if loaded[0].Name != "first" || loaded[0].Up != "SELECT 1;" || loaded[0].Down != "SELECT -1;" {
+		t.Errorf("first migration = %+v", loaded[0])
+	}
+	if loaded[0].Checksum == "" || loaded[0].Checksum == loaded[1].Checksum {
+		t.Errorf("checksums %q and %q should differ", loaded[0].Checksum, loaded[1].Checksum)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "bad name",
+			fsys: fstest.MapFS{"create_books.sql": {Data: []byte("SELECT 1;")}},
+			want: "is not named",
+		},
+		{
+			name: "zero version",
+			fsys: fstest.MapFS{"000000_init.up.sql": {Data: []byte("SELECT 1;")}},
+			want: "invalid version",
+		},
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"000001_books.up.sql":   {Data: []byte("SELECT 1;")},
+				"000001_authors.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			want: "is used by both",
+		},
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{"000001_books.down.sql": {Data: []byte("SELECT 1;")}},
+			want: "missing or empty up file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// A database golang-migrate brought to version 1 only gets the later
+// migrations, and a dirty one is left alone.
+func TestAdoptGolangMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	open := func(version int, dirty bool) (*sql.DB, *Migrator) {
+		db, err := sqlite.Open(sqlite.Scheme + ":" + filepath.Join(t.TempDir(), "library.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		m, err := New(db, migrations.SQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Dialect = SQLite
+
+		if _, err := db.Exec(m.Migrations[0].Up); err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec(`CREATE TABLE schema_migrations (version uint64, dirty bool);
+			INSERT INTO schema_migrations VALUES (?1, ?2)`, version, dirty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db, m
+	}
+
+	db, m := open(1, false)
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !statuses[0].Applied || statuses[1].Applied {
+		t.Errorf("got statuses %+v before adopting, want only version 1 applied", statuses)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(m.Migrations)-1 || applied[0].Version != 2 {
+		t.Fatalf("applied %v, want every migration after 1", applied)
+	}
+
+	if _, err := m.Down(ctx, len(m.Migrations)); err != nil {
+		t.Fatal(err)
+	}
+	if applied, err = m.Up(ctx); err != nil || len(applied) != len(m.Migrations) {
+		t.Fatalf("applied %d migrations after rolling everything back: %v", len(applied), err)
+	}
+
+	var legacy int
+	if err := db.QueryRow(`SELECT count(*) FROM golang_schema_migrations`).Scan(&legacy); err != nil || legacy != 1 {
+		t.Errorf("got %d rows in the renamed golang-migrate table: %v", legacy, err)
+	}
+
+	_, m = open(1, true)
+	if _, err := m.Up(ctx); !errors.Is(err, ErrLegacyDirty) {
+		t.Errorf("got %v on a dirty database, want ErrLegacyDirty", err)
+	}
+}
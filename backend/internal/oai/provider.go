@@ -0,0 +1,494 @@
+package oai
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/dublincore"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/marc"
+)
+
+// records per ListIdentifiers or ListRecords response
+const pageSize = 100
+
+const genreSetPrefix = "genre"
+
+var formats = []MetadataFormat{
+	{
+		Prefix:    "oai_dc",
+		Schema:    dublincore.OAISchema,
+		Namespace: dublincore.OAINamespace,
+	},
+	{
+		Prefix:    "marcxml",
+		Schema:    "http://www.loc.gov/standards/marcxml/schema/MARC21slim.xsd",
+		Namespace: marc.Namespace,
+	},
+}
+
+// allowed arguments per verb, true when required
+var verbs = map[string]map[string]bool{
+	"Identify":            {},
+	"ListMetadataFormats": {"identifier": false},
+	"ListSets":            {"resumptionToken": false},
+	"GetRecord":           {"identifier": true, "metadataPrefix": true},
+	"ListIdentifiers":     {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+	"ListRecords":         {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+}
+
+type Provider struct {
+	Read           books.ReadRepository
+	RepositoryName string
+	// RepositoryIdentifier is the namespace of the oai identifiers
+	RepositoryIdentifier string
+	AdminEmail           string
+}
+
+// Handle reports protocol errors inside the response.
+func (p *Provider) Handle(ctx context.Context, baseURL string, args url.Values) (*Response, error) {
+	res := newResponse(time.Now(), baseURL)
+
+	if errs := checkArguments(args); len(errs) > 0 {
+		res.Errors = errs
+		return res, nil
+	}
+
+	verb := args.Get("verb")
+
+	res.Request = Request{
+		Verb:            verb,
+		Identifier:      args.Get("identifier"),
+		MetadataPrefix:  args.Get("metadataPrefix"),
+		From:            args.Get("from"),
+		Until:           args.Get("until"),
+		Set:             args.Get("set"),
+		ResumptionToken: args.Get("resumptionToken"),
+		BaseURL:         baseURL,
+	}
+
+	var err error
+
+	switch verb {
+	case "Identify":
+		err = p.identify(ctx, res)
+	case "ListMetadataFormats":
+		err = p.listMetadataFormats(ctx, res, args)
+	case "ListSets":
+		err = p.listSets(ctx, res, args)
+	case "GetRecord":
+		err = p.getRecord(ctx, res, args)
+	case "ListIdentifiers", "ListRecords":
+		err = p.list(ctx, res, verb, args)
+	}
+
+	if oaiErr, ok := err.(Error); ok {
+		if oaiErr.Code == BadArgument {
+			res.Request = Request{BaseURL: baseURL}
+		}
+		res.Errors = []Error{oaiErr}
+		return res, nil
+	}
+
+	return res, err
+}
+
+func checkArguments(args url.Values) []Error {
+	verb := args["verb"]
+	if len(verb) != 1 {
+		return []Error{{Code: BadVerb, Message: "the verb argument must be given exactly once"}}
+	}
+
+	allowed, ok := verbs[verb[0]]
+	if !ok {
+		return []Error{{Code: BadVerb, Message: fmt.Sprintf("%q is not an OAI-PMH verb", verb[0])}}
+	}
+
+	errs := []Error{}
+
+	for key, values := range args {
+		if key == "verb" {
+			continue
+		}
+		if _, ok := allowed[key]; !ok {
+			errs = append(errs, Error{Code: BadArgument, Message: fmt.Sprintf("illegal argument %q", key)})
+			continue
+		}
+		if len(values) > 1 {
+			errs = append(errs, Error{Code: BadArgument, Message: fmt.Sprintf("argument %q is repeated", key)})
+		}
+	}
+
+	// the resumption token is an exclusive argument
+	if args.Has("resumptionToken") {
+		if len(args) > 2 {
+			errs = append(errs, Error{Code: BadArgument, Message: "resumptionToken cannot be combined with other arguments"})
+		}
+		return errs
+	}
+
+	for key, required := range allowed {
+		if required && !args.Has(key) {
+			errs = append(errs, Error{Code: BadArgument, Message: fmt.Sprintf("missing required argument %q", key)})
+		}
+	}
+
+	return errs
+}
+
+func (p *Provider) identify(ctx context.Context, res *Response) error {
+	earliest, err := p.Read.EarliestDatestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if earliest.IsZero() {
+		earliest = time.Now()
+	}
+
+	res.Identify = &Identify{
+		RepositoryName:    p.RepositoryName,
+		BaseURL:           res.Request.BaseURL,
+		ProtocolVersion:   ProtocolVersion,
+		AdminEmail:        p.AdminEmail,
+		EarliestDatestamp: formatDatestamp(earliest),
+		// purging the trash forgets the deletion
+		DeletedRecord: "transient",
+		Granularity:   Granularity,
+	}
+
+	return nil
+}
+
+func (p *Provider) listMetadataFormats(ctx context.Context, res *Response, args url.Values) error {
+	if args.Has("identifier") {
+		if _, err := p.fetch(ctx, args.Get("identifier")); err != nil {
+			return err
+		}
+	}
+
+	res.ListMetadataFormats = &ListMetadataFormats{Formats: formats}
+
+	return nil
+}
+
+func (p *Provider) listSets(ctx context.Context, res *Response, args url.Values) error {
+	// every set fits in one response
+	if args.Has("resumptionToken") {
+		return Error{Code: BadResumptionToken, Message: "the set list is never split"}
+	}
+
+	genres, err := p.Read.Genres(ctx)
+	if err != nil {
+		return err
+	}
+
+	sets := []Set{{Spec: genreSetPrefix, Name: "Genres"}}
+	seen := make(map[string]bool)
+
+	for _, genre := range genres {
+		spec := genreSetSpec(genre)
+		if spec == "" || seen[spec] {
+			continue
+		}
+		seen[spec] = true
+		sets = append(sets, Set{Spec: spec, Name: genre})
+	}
+
+	res.ListSets = &ListSets{Sets: sets}
+
+	return nil
+}
+
+func (p *Provider) getRecord(ctx context.Context, res *Response, args url.Values) error {
+	prefix := args.Get("metadataPrefix")
+	if !knownFormat(prefix) {
+		return cannotDisseminate(prefix)
+	}
+
+	rec, err := p.fetch(ctx, args.Get("identifier"))
+	if err != nil {
+		return err
+	}
+
+	res.GetRecord = &GetRecord{Record: p.record(rec, prefix)}
+
+	return nil
+}
+
+func (p *Provider) fetch(ctx context.Context, identifier string) (*books.HarvestRecord, error) {
+	notFound := Error{Code: IDDoesNotExist, Message: fmt.Sprintf("%q is unknown in this repository", identifier)}
+
+	id, ok := p.parseIdentifier(identifier)
+	if !ok {
+		return nil, notFound
+	}
+
+	records, err := p.Read.Harvest(ctx, books.HarvestFilter{ID: id, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, notFound
+	}
+
+	return records[0], nil
+}
+
+func (p *Provider) list(ctx context.Context, res *Response, verb string, args url.Values) error {
+	t := token{
+		MetadataPrefix: args.Get("metadataPrefix"),
+		From:           args.Get("from"),
+		Until:          args.Get("until"),
+		Set:            args.Get("set"),
+	}
+
+	resumed := args.Has("resumptionToken")
+	if resumed {
+		var err error
+		t, err = decodeToken(args.Get("resumptionToken"))
+		if err != nil {
+			return Error{Code: BadResumptionToken, Message: "the resumption token is invalid"}
+		}
+	}
+
+	if !knownFormat(t.MetadataPrefix) {
+		return cannotDisseminate(t.MetadataPrefix)
+	}
+
+	from, until, err := parseRange(t.From, t.Until)
+	if err != nil {
+		return err
+	}
+
+	filter := books.HarvestFilter{
+		From:    from,
+		Until:   until,
+		AfterID: t.AfterID,
+		//one more row tells if a page follows
+		Limit: pageSize + 1,
+	}
+
+	if t.Set != "" {
+		filter.Genres, err = p.resolveSet(ctx, t.Set)
+		if err != nil {
+			return err
+		}
+	}
+
+	records := []*books.HarvestRecord{}
+	if t.Set == "" || len(filter.Genres) > 0 {
+		records, err = p.Read.Harvest(ctx, filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(records) == 0 {
+		if resumed {
+			return Error{Code: BadResumptionToken, Message: "the resumption token no longer matches any record"}
+		}
+		return Error{Code: NoRecordsMatch, Message: "no records match the request"}
+	}
+
+	var next *ResumptionToken
+
+	if len(records) > pageSize {
+		records = records[:pageSize]
+
+		following := t
+		following.AfterID = records[len(records)-1].Entry.Book.ID
+		following.Cursor = t.Cursor + pageSize
+
+		next = &ResumptionToken{Cursor: t.Cursor, Value: following.encode()}
+	} else if resumed {
+		// an empty token ends a list that was split
+		next = &ResumptionToken{Cursor: t.Cursor}
+	}
+
+	if verb == "ListIdentifiers" {
+		headers := make([]Header, len(records))
+		for i, rec := range records {
+			headers[i] = p.header(rec)
+		}
+		res.ListIdentifiers = &ListIdentifiers{Headers: headers, ResumptionToken: next}
+		return nil
+	}
+
+	list := make([]Record, len(records))
+	for i, rec := range records {
+		list[i] = p.record(rec, t.MetadataPrefix)
+	}
+	res.ListRecords = &ListRecords{Records: list, ResumptionToken: next}
+
+	return nil
+}
+
+// resolveSet maps a set spec back to its genres.
+func (p *Provider) resolveSet(ctx context.Context, spec string) ([]string, error) {
+	genres, err := p.Read.Genres(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec == genreSetPrefix {
+		return genres, nil
+	}
+
+	matching := []string{}
+	for _, genre := range genres {
+		if genreSetSpec(genre) == spec {
+			matching = append(matching, genre)
+		}
+	}
+
+	return matching, nil
+}
+
+func (p *Provider) header(rec *books.HarvestRecord) Header {
+	h := Header{
+		Identifier: p.identifier(rec.Entry.Book.ID),
+		Datestamp:  formatDatestamp(rec.Datestamp),
+		SetSpecs:   []string{},
+	}
+
+	if rec.Deleted {
+		h.Status = "deleted"
+	}
+
+	seen := make(map[string]bool)
+	for _, genre := range rec.Entry.Book.Genres {
+		spec := genreSetSpec(genre)
+		if spec != "" && !seen[spec] {
+			seen[spec] = true
+			h.SetSpecs = append(h.SetSpecs, spec)
+		}
+	}
+
+	return h
+}
+
+func (p *Provider) record(rec *books.HarvestRecord, prefix string) Record {
+	r := Record{Header: p.header(rec)}
+
+	if rec.Deleted {
+		return r
+	}
+
+	switch prefix {
+	case "marcxml":
+		x := marc.ToXML(marc.FromEntry(rec.Entry))
+		x.Xmlns = marc.Namespace
+		r.Metadata = &Metadata{Payload: x}
+	default:
+		r.Metadata = &Metadata{Payload: dublincore.NewOAIDC(dublincore.FromEntry(rec.Entry))}
+	}
+
+	return r
+}
+
+func (p *Provider) identifier(id int64) string {
+	return fmt.Sprintf("oai:%s:%d", p.RepositoryIdentifier, id)
+}
+
+func (p *Provider) parseIdentifier(identifier string) (int64, bool) {
+	raw, ok := strings.CutPrefix(identifier, "oai:"+p.RepositoryIdentifier+":")
+	if !ok {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// genreSetSpec turns "Science Fiction" into genre:science-fiction.
+func genreSetSpec(genre string) string {
+	var b strings.Builder
+
+	dash := false
+	for _, c := range strings.ToLower(genre) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return ""
+	}
+
+	return genreSetPrefix + ":" + slug
+}
+
+func knownFormat(prefix string) bool {
+	for _, f := range formats {
+		if f.Prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+func cannotDisseminate(prefix string) Error {
+	return Error{Code: CannotDisseminateFormat, Message: fmt.Sprintf("metadata format %q is not supported", prefix)}
+}
+
+// parseRange reads from and until, which must share a granularity.
+func parseRange(fromArg, untilArg string) (time.Time, time.Time, error) {
+	from, fromDay, err := parseDatestamp(fromArg)
+	if err != nil {
+		return time.Time{}, time.Time{}, Error{Code: BadArgument, Message: "from is not a valid datestamp"}
+	}
+
+	until, untilDay, err := parseDatestamp(untilArg)
+	if err != nil {
+		return time.Time{}, time.Time{}, Error{Code: BadArgument, Message: "until is not a valid datestamp"}
+	}
+
+	if fromArg != "" && untilArg != "" {
+		if fromDay != untilDay {
+			return time.Time{}, time.Time{}, Error{Code: BadArgument, Message: "from and until must have the same granularity"}
+		}
+		if from.After(until) {
+			return time.Time{}, time.Time{}, Error{Code: BadArgument, Message: "from must not be later than until"}
+		}
+	}
+
+	if !until.IsZero() {
+		if untilDay {
+			until = until.Add(24*time.Hour - time.Nanosecond)
+		} else {
+			until = until.Add(time.Second - time.Nanosecond)
+		}
+	}
+
+	return from, until, nil
+}
+
+func parseDatestamp(s string) (time.Time, bool, error) {
+	if s == "" {
+		return time.Time{}, false, nil
+	}
+
+	if t, err := time.Parse(dayLayout, s); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(datestampLayout, s)
+	return t, false, err
+}
@@ -0,0 +1,155 @@
+package oai
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/memory"
+)
+
+func TestToken(t *testing.T) {
+	want := token{MetadataPrefix: "oai_dc", From: "2024-01-01", Set: "genre:fantasy", AfterID: 100, Cursor: 100}
+
+	got, err := decodeToken(want.encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("got token %+v, want %+v", got, want)
+	}
+
+	for _, s := range []string{
+		"not base64!",
+		"bm90IGpzb24",
+		token{AfterID: 100}.encode(),
+		token{MetadataPrefix: "oai_dc", AfterID: -1}.encode(),
+		token{MetadataPrefix: "oai_dc", Cursor: -100}.encode(),
+	} {
+		if _, err := decodeToken(s); err == nil {
+			t.Errorf("decodeToken(%q) succeeded", s)
+		}
+	}
+}
+
+func newProvider(t *testing.T, count int) *Provider {
+	t.Helper()
+
+	store := memory.New()
+
+	for i := 0; i < count; i++ {
+		entry := &books.CreateBookEntry{
+			Book: &books.Book{
+				Title:     fmt.Sprintf("Volume %d", i+1),
+				Publisher: "Ace",
+				Year:      1965,
+				PageCount: 412,
+				Genres:    []string{"science fiction"},
+			},
+			Authors: &books.Authors{List: []string{"Frank Herbert"}},
+		}
+		entry.Normalize()
+
+		if err := store.Create().Save(context.Background(), entry, &books.ReadEntry{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &Provider{
+		Read:                 store.Read(),
+		RepositoryName:       "Library",
+		RepositoryIdentifier: "library.example.org",
+	}
+}
+
+func handle(t *testing.T, p *Provider, args url.Values) *Response {
+	t.Helper()
+
+	res, err := p.Handle(context.Background(), "http://library.example.org/v1/oai", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestListResumption(t *testing.T) {
+	p := newProvider(t, 2*pageSize+50)
+
+	seen := make(map[string]bool)
+	cursors := []int{}
+	args := url.Values{"verb": {"ListIdentifiers"}, "metadataPrefix": {"oai_dc"}}
+
+	for {
+		res := handle(t, p, args)
+		if len(res.Errors) != 0 {
+			t.Fatalf("got errors %v", res.Errors)
+		}
+
+		for _, h := range res.ListIdentifiers.Headers {
+			if seen[h.Identifier] {
+				t.Errorf("%s is listed twice", h.Identifier)
+			}
+			seen[h.Identifier] = true
+		}
+
+		next := res.ListIdentifiers.ResumptionToken
+		if next == nil {
+			t.Fatal("a split list must end with an empty token")
+		}
+		cursors = append(cursors, next.Cursor)
+		if next.Value == "" {
+			break
+		}
+
+		args = url.Values{"verb": {"ListIdentifiers"}, "resumptionToken": {next.Value}}
+	}
+
+	if len(seen) != 2*pageSize+50 {
+		t.Errorf("got %d identifiers, want %d", len(seen), 2*pageSize+50)
+	}
+	if want := []int{0, pageSize, 2 * pageSize}; fmt.Sprint(cursors) != fmt.Sprint(want) {
+		t.Errorf("got cursors %v, want %v", cursors, want)
+	}
+}
+
+func TestListWithoutResumption(t *testing.T) {
+	p := newProvider(t, 3)
+
+	res := handle(t, p, url.Values{"verb": {"ListRecords"}, "metadataPrefix": {"oai_dc"}})
+	if len(res.Errors) != 0 {
+		t.Fatalf("got errors %v", res.Errors)
+	}
+	if len(res.ListRecords.Records) != 3 {
+		t.Errorf("got %d records, want 3", len(res.ListRecords.Records))
+	}
+	if res.ListRecords.ResumptionToken != nil {
+		t.Errorf("a list that fits one page got the token %+v", res.ListRecords.ResumptionToken)
+	}
+}
+
+func TestBadResumptionToken(t *testing.T) {
+	p := newProvider(t, 3)
+
+	tests := []struct {
+		name string
+		args url.Values
+		code string
+	}{
+		{"garbage", url.Values{"verb": {"ListRecords"}, "resumptionToken": {"garbage"}}, BadResumptionToken},
+		{"past the last record", url.Values{"verb": {"ListRecords"}, "resumptionToken": {token{MetadataPrefix: "oai_dc", AfterID: 3, Cursor: pageSize}.encode()}}, BadResumptionToken},
+		{"unknown format", url.Values{"verb": {"ListRecords"}, "resumptionToken": {token{MetadataPrefix: "mods"}.encode()}}, CannotDisseminateFormat},
+		{"with other arguments", url.Values{"verb": {"ListRecords"}, "metadataPrefix": {"oai_dc"}, "resumptionToken": {token{MetadataPrefix: "oai_dc"}.encode()}}, BadArgument},
+		{"on the set list", url.Values{"verb": {"ListSets"}, "resumptionToken": {"anything"}}, BadResumptionToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := handle(t, p, tt.args)
+			if len(res.Errors) != 1 || res.Errors[0].Code != tt.code {
+				t.Errorf("got errors %v, want %s", res.Errors, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package oai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// token carries the whole state of a list request.
+type token struct {
+	MetadataPrefix string `json:"p"`
+	From           string `json:"f,omitempty"`
+	Until          string `json:"u,omitempty"`
+	Set            string `json:"s,omitempty"`
+	AfterID        int64  `json:"a"`
+	Cursor         int    `json:"c"`
+}
+
+func (t token) encode() string {
+	js, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(js)
+}
+
+func decodeToken(s string) (token, error) {
+	var t token
+
+	js, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, err
+	}
+
+	if err := json.Unmarshal(js, &t); err != nil {
+		return t, err
+	}
+
+	if t.MetadataPrefix == "" || t.AfterID < 0 || t.Cursor < 0 {
+		return t, errors.New("incomplete token")
+	}
+
+	return t, nil
+}
@@ -0,0 +1,190 @@
+package opds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+const (
+	atomNamespace       = "http://www.w3.org/2005/Atom"
+	dctermsNamespace    = "http://purl.org/dc/terms/"
+	opdsNamespace       = "http://opds-spec.org/2010/catalog"
+	openSearchNamespace = "http://a9.com/-/spec/opensearch/1.1/"
+	threadNamespace     = "http://purl.org/syndication/thread/1.0"
+
+	AtomNavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	AtomAcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	AtomEntryType       = "application/atom+xml;type=entry;profile=opds-catalog"
+	OpenSearchType      = "application/opensearchdescription+xml"
+)
+
+type atomFeed struct {
+	XMLName      xml.Name    `xml:"feed"`
+	Xmlns        string      `xml:"xmlns,attr"`
+	DC           string      `xml:"xmlns:dc,attr"`
+	OPDS         string      `xml:"xmlns:opds,attr"`
+	OpenSearch   string      `xml:"xmlns:opensearch,attr"`
+	Thr          string      `xml:"xmlns:thr,attr"`
+	ID           string      `xml:"id"`
+	Title        string      `xml:"title"`
+	Updated      string      `xml:"updated"`
+	TotalResults int         `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage int         `xml:"opensearch:itemsPerPage,omitempty"`
+	Links        []atomLink  `xml:"link"`
+	Entries      []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+	Count int    `xml:"thr:count,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Term   string `xml:"term,attr"`
+	Label  string `xml:"label,attr,omitempty"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomPerson   `xml:"author"`
+	Publisher  string         `xml:"dc:publisher,omitempty"`
+	Issued     string         `xml:"dc:issued,omitempty"`
+	Identifier string         `xml:"dc:identifier,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Content    *atomContent   `xml:"content"`
+	Links      []atomLink     `xml:"link"`
+}
+
+func atomType(l Link) string {
+	if !l.Feed {
+		return l.Type
+	}
+	if l.Kind == AcquisitionKind {
+		return AtomAcquisitionType
+	}
+	return AtomNavigationType
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func atomLinks(links []Link) []atomLink {
+	out := make([]atomLink, 0, len(links))
+	for _, l := range links {
+		out = append(out, atomLink{Rel: l.Rel, Href: l.Href, Type: atomType(l), Title: l.Title})
+	}
+	return out
+}
+
+// WriteAtom renders the feed as an OPDS 1.2 Atom document.
+func WriteAtom(w io.Writer, f *Feed) error {
+	feed := atomFeed{
+		Xmlns:        atomNamespace,
+		DC:           dctermsNamespace,
+		OPDS:         opdsNamespace,
+		OpenSearch:   openSearchNamespace,
+		Thr:          threadNamespace,
+		ID:           f.ID,
+		Title:        f.Title,
+		Updated:      atomTime(f.updated()),
+		TotalResults: f.TotalItems,
+		ItemsPerPage: f.ItemsPerPage,
+		Links:        atomLinks(f.Links),
+	}
+
+	for _, nav := range f.Navigation {
+		updated := nav.Updated
+		if updated.IsZero() {
+			updated = f.updated()
+		}
+
+		rel := nav.Rel
+		if rel == "" {
+			rel = RelSubsection
+		}
+
+		link := atomLink{Rel: rel, Href: nav.Href, Type: atomType(Link{Feed: true, Kind: nav.Kind}), Count: nav.Count}
+
+		entry := atomEntry{
+			ID:      nav.ID,
+			Title:   nav.Title,
+			Updated: atomTime(updated),
+			Links:   []atomLink{link},
+		}
+		if nav.Count > 0 {
+			entry.Content = &atomContent{Type: "text", Value: fmt.Sprintf("%d books", nav.Count)}
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	for _, pub := range f.Publications {
+		feed.Entries = append(feed.Entries, atomPublication(f, pub))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(feed)
+}
+
+func atomPublication(f *Feed, pub *books.ReadEntry) atomEntry {
+	entry := atomEntry{
+		ID:        bookURN(pub),
+		Title:     pub.Book.Title,
+		Updated:   atomTime(pub.LastModified),
+		Publisher: pub.Book.Publisher,
+		Issued:    strconv.Itoa(int(pub.Book.Year)),
+	}
+
+	if pub.Book.ISBN != "" {
+		entry.Identifier = "urn:isbn:" + pub.Book.ISBN
+	}
+
+	for _, name := range pub.List.Name {
+		entry.Authors = append(entry.Authors, atomPerson{Name: name})
+	}
+
+	for _, genre := range pub.Book.Genres {
+		entry.Categories = append(entry.Categories, atomCategory{Term: genre, Label: genre})
+	}
+
+	if pub.Book.PageCount > 0 {
+		entry.Content = &atomContent{Type: "text", Value: fmt.Sprintf("%d pages", pub.Book.PageCount)}
+	}
+
+	if f.BookLinks != nil {
+		entry.Links = atomLinks(f.BookLinks(pub))
+	}
+
+	return entry
+}
+
+func bookURN(entry *books.ReadEntry) string {
+	return "urn:library:book:" + strconv.FormatInt(entry.Book.ID, 10)
+}
@@ -0,0 +1,88 @@
+package opds
+
+import (
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+const (
+	NavigationKind  = "navigation"
+	AcquisitionKind = "acquisition"
+)
+
+// link relations defined by OPDS
+const (
+	RelStart      = "start"
+	RelSelf       = "self"
+	RelUp         = "up"
+	RelSearch     = "search"
+	RelFirst      = "first"
+	RelPrevious   = "previous"
+	RelNext       = "next"
+	RelLast       = "last"
+	RelSubsection = "subsection"
+	RelNew        = "http://opds-spec.org/sort/new"
+	RelBorrow     = "http://opds-spec.org/acquisition/borrow"
+	RelAlternate  = "alternate"
+)
+
+// Link is the same for both versions.
+type Link struct {
+	Rel       string
+	Href      string
+	Type      string
+	Title     string
+	Templated bool
+	// Feed marks links to other catalogue feeds, Kind says which
+	Feed bool
+	Kind string
+}
+
+// Navigation is an entry of a navigation feed pointing to a sub feed.
+type Navigation struct {
+	ID      string
+	Title   string
+	Href    string
+	Kind    string
+	Count   int
+	Rel     string
+	Updated time.Time
+}
+
+// Feed is the version neutral catalogue feed.
+type Feed struct {
+	ID           string
+	Title        string
+	Kind         string
+	Updated      time.Time
+	Links        []Link
+	Navigation   []Navigation
+	Publications []*books.ReadEntry
+	TotalItems   int
+	ItemsPerPage int
+	CurrentPage  int
+	// BookLinks returns the links of a publication
+	BookLinks func(entry *books.ReadEntry) []Link
+}
+
+// NewFeed returns an empty feed.
+func NewFeed(id, title, kind string) *Feed {
+	return &Feed{ID: id, Title: title, Kind: kind}
+}
+
+func (f *Feed) AddPublications(entries []*books.ReadEntry) {
+	for _, entry := range entries {
+		if entry.LastModified.After(f.Updated) {
+			f.Updated = entry.LastModified
+		}
+	}
+	f.Publications = append(f.Publications, entries...)
+}
+
+func (f *Feed) updated() time.Time {
+	if f.Updated.IsZero() {
+		return time.Now()
+	}
+	return f.Updated
+}
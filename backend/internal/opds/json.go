@@ -0,0 +1,145 @@
+package opds
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+const JSONType = "application/opds+json"
+
+type jsonLink struct {
+	Rel        string          `json:"rel,omitempty"`
+	Href       string          `json:"href"`
+	Type       string          `json:"type,omitempty"`
+	Title      string          `json:"title,omitempty"`
+	Templated  bool            `json:"templated,omitempty"`
+	Properties *jsonProperties `json:"properties,omitempty"`
+}
+
+type jsonProperties struct {
+	NumberOfItems int `json:"numberOfItems,omitempty"`
+}
+
+type jsonFeedMetadata struct {
+	Title         string    `json:"title"`
+	Modified      time.Time `json:"modified"`
+	NumberOfItems int       `json:"numberOfItems,omitempty"`
+	ItemsPerPage  int       `json:"itemsPerPage,omitempty"`
+	CurrentPage   int       `json:"currentPage,omitempty"`
+}
+
+type jsonContributor struct {
+	Name string `json:"name"`
+}
+
+type jsonSubject struct {
+	Name string `json:"name"`
+}
+
+type jsonPublicationMetadata struct {
+	Type          string            `json:"@type"`
+	Identifier    string            `json:"identifier"`
+	Title         string            `json:"title"`
+	Author        []jsonContributor `json:"author,omitempty"`
+	Publisher     string            `json:"publisher,omitempty"`
+	Published     string            `json:"published,omitempty"`
+	Modified      time.Time         `json:"modified"`
+	Subject       []jsonSubject     `json:"subject,omitempty"`
+	NumberOfPages int32             `json:"numberOfPages,omitempty"`
+}
+
+type jsonPublication struct {
+	Metadata jsonPublicationMetadata `json:"metadata"`
+	Links    []jsonLink              `json:"links"`
+}
+
+type jsonFeed struct {
+	Metadata     jsonFeedMetadata  `json:"metadata"`
+	Links        []jsonLink        `json:"links"`
+	Navigation   []jsonLink        `json:"navigation,omitempty"`
+	Publications []jsonPublication `json:"publications,omitempty"`
+}
+
+func jsonLinks(links []Link) []jsonLink {
+	out := make([]jsonLink, 0, len(links))
+	for _, l := range links {
+		typ := l.Type
+		if l.Feed {
+			typ = JSONType
+		}
+		out = append(out, jsonLink{Rel: l.Rel, Href: l.Href, Type: typ, Title: l.Title, Templated: l.Templated})
+	}
+	return out
+}
+
+// WriteJSON renders the feed as an OPDS 2.0 document.
+func WriteJSON(w io.Writer, f *Feed) error {
+	feed := jsonFeed{
+		Metadata: jsonFeedMetadata{
+			Title:         f.Title,
+			Modified:      f.updated().UTC().Truncate(time.Second),
+			NumberOfItems: f.TotalItems,
+			ItemsPerPage:  f.ItemsPerPage,
+			CurrentPage:   f.CurrentPage,
+		},
+		Links: jsonLinks(f.Links),
+	}
+
+	for _, nav := range f.Navigation {
+		link := jsonLink{Href: nav.Href, Type: JSONType, Title: nav.Title, Rel: nav.Rel}
+		if nav.Count > 0 {
+			link.Properties = &jsonProperties{NumberOfItems: nav.Count}
+		}
+		feed.Navigation = append(feed.Navigation, link)
+	}
+
+	// an acquisition feed always has the publications collection
+	if f.Kind == AcquisitionKind {
+		feed.Publications = []jsonPublication{}
+	}
+
+	for _, pub := range f.Publications {
+		feed.Publications = append(feed.Publications, jsonPublicationOf(f, pub))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(feed)
+}
+
+func jsonPublicationOf(f *Feed, pub *books.ReadEntry) jsonPublication {
+	p := jsonPublication{
+		Metadata: jsonPublicationMetadata{
+			Type:          "http://schema.org/Book",
+			Identifier:    bookURN(pub),
+			Title:         pub.Book.Title,
+			Publisher:     pub.Book.Publisher,
+			Published:     strconv.Itoa(int(pub.Book.Year)),
+			Modified:      pub.LastModified.UTC().Truncate(time.Second),
+			NumberOfPages: pub.Book.PageCount,
+		},
+		Links: []jsonLink{},
+	}
+
+	if pub.Book.ISBN != "" {
+		p.Metadata.Identifier = "urn:isbn:" + pub.Book.ISBN
+	}
+
+	for _, name := range pub.List.Name {
+		p.Metadata.Author = append(p.Metadata.Author, jsonContributor{Name: name})
+	}
+
+	for _, genre := range pub.Book.Genres {
+		p.Metadata.Subject = append(p.Metadata.Subject, jsonSubject{Name: genre})
+	}
+
+	if f.BookLinks != nil {
+		p.Links = jsonLinks(f.BookLinks(pub))
+	}
+
+	return p
+}
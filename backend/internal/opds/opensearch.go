@@ -0,0 +1,49 @@
+package opds
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type openSearchDescription struct {
+	XMLName        xml.Name        `xml:"OpenSearchDescription"`
+	Xmlns          string          `xml:"xmlns,attr"`
+	ShortName      string          `xml:"ShortName"`
+	Description    string          `xml:"Description"`
+	InputEncoding  string          `xml:"InputEncoding"`
+	OutputEncoding string          `xml:"OutputEncoding"`
+	URLs           []openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// OpenSearch caps the short name at 16 characters
+const maxShortName = 16
+
+// WriteOpenSearch writes the description, template holds {searchTerms}.
+func WriteOpenSearch(w io.Writer, name, template string) error {
+	shortName := []rune(name)
+	if len(shortName) > maxShortName {
+		shortName = shortName[:maxShortName]
+	}
+
+	desc := openSearchDescription{
+		Xmlns:          openSearchNamespace,
+		ShortName:      string(shortName),
+		Description:    "Search the " + name + " by title, author or publisher",
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URLs:           []openSearchURL{{Type: AtomAcquisitionType, Template: template}},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(desc)
+}
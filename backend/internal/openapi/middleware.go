@@ -0,0 +1,72 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// ValidateRequests checks json bodies against the schema of their operation.
+func (d *Document) ValidateRequests(maxBytes int64, fail func(w http.ResponseWriter, r *http.Request, errors map[string]string), tooLarge func(w http.ResponseWriter, r *http.Request, limit int64)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op, ok := d.Operation(r.Method, r.URL.Path)
+			if !ok || op.RequestBody == nil || r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			media, ok := op.RequestBody.Content["application/json"]
+			if !ok || !isJSON(r.Header.Get("Content-Type")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if maxBytes > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					tooLarge(w, r, maxBytesErr.Limit)
+					return
+				}
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+
+			var value interface{}
+			if dec.Decode(&value) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if errs := d.Validate(media.Schema, value); len(errs) > 0 {
+				fail(w, r, errs)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isJSON accepts a missing Content-Type too.
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || mediaType == "text/json")
+}
@@ -0,0 +1,112 @@
+// Package openapi embeds the OpenAPI 3 description of the API.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed docs.html
+var DocsPage []byte
+
+// Document holds the parts of the specification the server looks at.
+type Document struct {
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type Operation struct {
+	OperationID string       `json:"operationId"`
+	RequestBody *RequestBody `json:"requestBody"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Enum                 []interface{}      `json:"enum"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties interface{}        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	OneOf                []*Schema          `json:"oneOf"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MaxLength            *int               `json:"maxLength"`
+}
+
+func Load() (*Document, error) {
+	doc := &Document{}
+	if err := json.Unmarshal(Spec, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// MustLoad is for the router setup.
+func MustLoad() *Document {
+	doc, err := Load()
+	if err != nil {
+		panic("openapi: embedded specification is invalid: " + err.Error())
+	}
+	return doc
+}
+
+// Resolve follows a local $ref, other schemas are returned as they are.
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+// Operation finds the operation serving method and path.
+func (d *Document) Operation(method, path string) (*Operation, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for template, operations := range d.Paths {
+		if !matchTemplate(strings.Split(strings.Trim(template, "/"), "/"), segments) {
+			continue
+		}
+		op, ok := operations[strings.ToLower(method)]
+		if ok {
+			return op, true
+		}
+	}
+
+	return nil, false
+}
+
+func matchTemplate(template, segments []string) bool {
+	if len(template) != len(segments) {
+		return false
+	}
+	for i, part := range template {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != segments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validate checks a decoded json value, errors are keyed by dotted path.
+func (d *Document) Validate(s *Schema, value interface{}) map[string]string {
+	errs := make(map[string]string)
+	d.validate(s, value, "", errs)
+	return errs
+}
+
+func (d *Document) validate(s *Schema, value interface{}, path string, errs map[string]string) {
+	s = d.Resolve(s)
+	if s == nil {
+		return
+	}
+
+	key := path
+	if key == "" {
+		key = "body"
+	}
+
+	if len(s.OneOf) > 0 {
+		for _, option := range s.OneOf {
+			if len(d.Validate(option, value)) == 0 {
+				return
+			}
+		}
+		errs[key] = "does not match any of the allowed shapes"
+		return
+	}
+
+	if value == nil {
+		// a schema without a type takes anything, null included
+		if !s.Nullable && s.Type != "" {
+			errs[key] = "must not be null"
+		}
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			errs[key] = "must be an object"
+			return
+		}
+		d.validateObject(s, object, path, errs)
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			errs[key] = "must be an array"
+			return
+		}
+		for i, item := range items {
+			d.validate(s.Items, item, fmt.Sprintf("%s[%d]", key, i), errs)
+		}
+
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			errs[key] = "must be a string"
+			return
+		}
+		if s.MaxLength != nil && len(text) > *s.MaxLength {
+			errs[key] = fmt.Sprintf("must not be longer than %d bytes", *s.MaxLength)
+		}
+
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			errs[key] = "must be a number"
+			return
+		}
+		if s.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				errs[key] = "must be an integer"
+				return
+			}
+		}
+		f, _ := number.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			errs[key] = fmt.Sprintf("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			errs[key] = fmt.Sprintf("must be at most %v", *s.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs[key] = "must be a boolean"
+		}
+	}
+
+	if len(s.Enum) > 0 && errs[key] == "" {
+		for _, allowed := range s.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				return
+			}
+		}
+		errs[key] = "must be one of " + enumList(s.Enum)
+	}
+}
+
+func (d *Document) validateObject(s *Schema, object map[string]interface{}, path string, errs map[string]string) {
+	join := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			errs[join(name)] = "must be provided"
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := s.Properties[name]; ok {
+			d.validate(property, object[name], join(name), errs)
+			continue
+		}
+
+		switch extra := s.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				errs[join(name)] = "is not a known field"
+			}
+		case map[string]interface{}:
+			raw, _ := json.Marshal(extra)
+			additional := &Schema{}
+			if json.Unmarshal(raw, additional) == nil {
+				d.validate(additional, object[name], join(name), errs)
+			}
+		}
+	}
+}
+
+func enumList(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ", ")
+}
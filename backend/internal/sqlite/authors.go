@@ -0,0 +1,275 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+type authors struct {
+	s *Store
+}
+
+func (a authors) BeginTx(ctx context.Context) (books.Tx, error) {
+	return a.s.BeginTx(ctx)
+}
+
+// AuthorGet fills read like the SQL model.
+func (a authors) AuthorGet(ctx context.Context, tx books.Tx, read *books.ReadAuthorEntry) error {
+	q, err := a.s.query(tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	SELECT author_id, name, books_authored, updated_at
+	FROM authors
+	WHERE id = $1
+	`
+
+	err = q.QueryRowContext(ctx, query, read.Author.ID).Scan(
+		&read.Author.Identifier,
+		&read.Author.Name,
+		&read.Author.Books_authored,
+		timestamp{&read.LastModified},
+	)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	SELECT b.id, b.book_id, b.title, b.publisher, b.year, b.page_count, b.updated_at
+	FROM book_author_link bal
+	JOIN books b ON b.book_id = bal.book_id
+	WHERE bal.author_id = $1 AND b.deleted_at IS NULL
+	ORDER BY b.id
+	`
+
+	rows, err := q.QueryContext(ctx, query, read.Author.Identifier)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	read.BookList = books.ReadBookList{}
+
+	for rows.Next() {
+		var b books.ReadBook
+		var updatedAt time.Time
+
+		if err := rows.Scan(&b.ID, &b.Hash, &b.Title, &b.Publisher, &b.Year, &b.PageCount, timestamp{&updatedAt}); err != nil {
+			return err
+		}
+
+		read.BookList.ID = append(read.BookList.ID, b.ID)
+		read.BookList.Hash = append(read.BookList.Hash, b.Hash)
+		read.BookList.Title = append(read.BookList.Title, b.Title)
+		read.BookList.Publisher = append(read.BookList.Publisher, b.Publisher)
+		read.BookList.Year = append(read.BookList.Year, b.Year)
+		read.BookList.PageCount = append(read.BookList.PageCount, b.PageCount)
+
+		if updatedAt.After(read.LastModified) {
+			read.LastModified = updatedAt
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(read.BookList.ID) == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (a authors) ListAuthors(ctx context.Context, filters internal.Filters) ([]*books.AuthorSummary, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), id, name, books_authored
+	FROM authors
+	WHERE books_authored > 0
+	ORDER BY %s %s, id ASC
+	LIMIT $1 OFFSET $2
+	`, column, filters.SortDirection())
+
+	rows, err := a.s.DB.QueryContext(ctx, query, filters.Limit(), filters.Offset())
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	summaries := []*books.AuthorSummary{}
+
+	for rows.Next() {
+		var author books.AuthorSummary
+
+		err := rows.Scan(&totalRecords, &author.ID, &author.Name, &author.BooksAuthored)
+		if err != nil {
+			return nil, internal.Metadata{}, err
+		}
+
+		summaries = append(summaries, &author)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return summaries, internal.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+func (a authors) BooksByAuthors(ctx context.Context, authorIDs []int64) (map[int64][]*books.ReadEntry, error) {
+	query := `
+	SELECT x.id, ` + bookColumns + `
+	FROM
+		authors x
+	JOIN
+		book_author_link xl ON xl.author_id = x.author_id
+	JOIN
+		books b ON b.book_id = xl.book_id
+	WHERE
+		x.id IN (SELECT value FROM json_each($1)) AND b.deleted_at IS NULL
+	ORDER BY
+		x.id, b.id
+	`
+
+	rows, err := a.s.DB.QueryContext(ctx, query, idList(authorIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byAuthor := make(map[int64][]*books.ReadEntry, len(authorIDs))
+	entries := []*books.ReadEntry{}
+
+	for rows.Next() {
+		var authorID int64
+		entry := &books.ReadEntry{}
+
+		if err := rows.Scan(append([]interface{}{&authorID}, bookDest(entry)...)...); err != nil {
+			return nil, err
+		}
+
+		byAuthor[authorID] = append(byAuthor[authorID], entry)
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	return byAuthor, fillAuthors(ctx, a.s.DB, entries)
+}
+
+// MergeAuthors moves the books of from over to into and drops from.
+func (a authors) MergeAuthors(ctx context.Context, t books.Tx, into, from int64) (*books.ReadAuthor, error) {
+	tx, err := unwrap(t)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := getAuthor(ctx, tx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := getAuthor(ctx, tx, into)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE books
+		SET updated_at = ` + now + `
+		WHERE book_id IN (
+			SELECT book_id FROM book_author_link WHERE author_id = $1
+		)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, source.Identifier); err != nil {
+		return nil, err
+	}
+
+	query = `
+		DELETE FROM book_author_link
+		WHERE author_id = $1 AND book_id IN (
+			SELECT book_id FROM book_author_link WHERE author_id = $2
+		)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, source.Identifier, target.Identifier); err != nil {
+		return nil, err
+	}
+
+	query = `
+		UPDATE book_author_link
+		SET author_id = $2
+		WHERE author_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, source.Identifier, target.Identifier); err != nil {
+		return nil, err
+	}
+
+	before := *target
+
+	query = `
+		UPDATE authors
+		SET books_authored = (
+			SELECT count(*)
+			FROM book_author_link bal
+			JOIN books b ON b.book_id = bal.book_id
+			WHERE bal.author_id = $1 AND b.deleted_at IS NULL
+		), updated_at = ` + now + `
+		WHERE author_id = $1
+		RETURNING books_authored
+	`
+
+	if err := tx.QueryRowContext(ctx, query, target.Identifier).Scan(&target.Books_authored); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM authors WHERE author_id = $1`, source.Identifier); err != nil {
+		return nil, err
+	}
+
+	err = audit.Record(ctx, tx, authorEntity, target.ID, audit.ActionMerge,
+		map[string]books.ReadAuthor{"into": before, "from": *source},
+		target,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+func getAuthor(ctx context.Context, tx *sql.Tx, id int64) (*books.ReadAuthor, error) {
+	query := `
+		SELECT id, name, author_id, books_authored
+		FROM authors
+		WHERE id = $1
+	`
+
+	author := &books.ReadAuthor{}
+
+	err := tx.QueryRowContext(ctx, query, id).Scan(&author.ID, &author.Name, &author.Identifier, &author.Books_authored)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return author, nil
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+type links struct {
+	s *Store
+}
+
+func (l links) BeginTx(ctx context.Context) (books.Tx, error) {
+	return l.s.BeginTx(ctx)
+}
+
+func (l links) Linked(ctx context.Context, tx books.Tx, bookHash string) ([]string, error) {
+	q, err := l.s.query(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT author_id FROM book_author_link WHERE book_id = $1 ORDER BY id`, bookHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, rows.Err()
+}
+
+func (l links) Link(ctx context.Context, t books.Tx, bookHash string, authorHashes []string) error {
+	tx, err := unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range authorHashes {
+		if err := linkAuthor(ctx, tx, bookHash, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l links) Unlink(ctx context.Context, t books.Tx, bookHash string, authorHashes []string) error {
+	tx, err := unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM book_author_link WHERE book_id = $1 AND author_id IN (SELECT value FROM json_each($2))`
+
+	_, err = tx.ExecContext(ctx, query, bookHash, stringList(authorHashes))
+	return err
+}
@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+type revisions struct {
+	s *Store
+}
+
+func (r revisions) List(ctx context.Context, bookID int64) ([]*books.Revision, error) {
+	query := `
+	SELECT book_id, version, created_at, actor, snapshot
+	FROM book_revisions
+	WHERE book_id = $1
+	ORDER BY version DESC
+	`
+
+	rows, err := r.s.DB.QueryContext(ctx, query, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*books.Revision{}
+
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r revisions) Get(ctx context.Context, bookID int64, version int32) (*books.Revision, error) {
+	query := `
+	SELECT book_id, version, created_at, actor, snapshot
+	FROM book_revisions
+	WHERE book_id = $1 AND version = $2
+	`
+
+	revision, err := scanRevision(r.s.DB.QueryRowContext(ctx, query, bookID, version))
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return revision, nil
+}
+
+func (r revisions) Previous(ctx context.Context, bookID int64, version int32) (*books.Revision, error) {
+	query := `
+	SELECT book_id, version, created_at, actor, snapshot
+	FROM book_revisions
+	WHERE book_id = $1 AND version < $2
+	ORDER BY version DESC
+	LIMIT 1
+	`
+
+	revision, err := scanRevision(r.s.DB.QueryRowContext(ctx, query, bookID, version))
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return revision, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRevision(row rowScanner) (*books.Revision, error) {
+	var revision books.Revision
+	var snapshot string
+
+	err := row.Scan(
+		&revision.BookID,
+		&revision.Version,
+		timestamp{&revision.CreatedAt},
+		&revision.Actor,
+		&snapshot,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(snapshot), &revision.Snapshot); err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+type auditLog struct {
+	s *Store
+}
+
+func (l auditLog) List(ctx context.Context, filter audit.Filter, filters internal.Filters) ([]*audit.Entry, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		count(*) OVER(), id, created_at, actor, request_id, entity, entity_id, action, before, after
+	FROM
+		audit_log
+	WHERE
+		($1 = '' OR actor = $1)
+		AND ($2 = '' OR entity = $2)
+		AND ($3 = 0 OR entity_id = $3)
+		AND ($4 = '' OR action = $4)
+		AND ($5 = '' OR request_id = $5)
+		AND ($6 IS NULL OR created_at >= $6)
+		AND ($7 IS NULL OR created_at < $7)
+	ORDER BY
+		%s %s, id ASC
+	LIMIT $8 OFFSET $9
+	`, column, filters.SortDirection())
+
+	args := []interface{}{
+		filter.Actor,
+		filter.Entity,
+		filter.EntityID,
+		filter.Action,
+		filter.RequestID,
+		nullTime(filter.Since),
+		nullTime(filter.Until),
+		filters.Limit(),
+		filters.Offset(),
+	}
+
+	rows, err := l.s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	entries := []*audit.Entry{}
+
+	for rows.Next() {
+		var entry audit.Entry
+		var before, after []byte
+
+		err := rows.Scan(
+			&totalRecords,
+			&entry.ID,
+			timestamp{&entry.CreatedAt},
+			&entry.Actor,
+			&entry.RequestID,
+			&entry.Entity,
+			&entry.EntityID,
+			&entry.Action,
+			&before,
+			&after,
+		)
+		if err != nil {
+			return nil, internal.Metadata{}, err
+		}
+
+		entry.Before = before
+		entry.After = after
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return entries, internal.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
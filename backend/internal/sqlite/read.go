@@ -0,0 +1,564 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+type reader struct {
+	s *Store
+}
+
+const bookColumns = `b.id, b.book_id, b.title, b.publisher, b.year, b.page_count, b.genres, b.isbn, b.version, b.updated_at`
+
+// linked keeps the books with at least one author.
+const linked = `EXISTS (SELECT 1 FROM book_author_link bal WHERE bal.book_id = b.book_id)`
+
+// bookDest scans bookColumns.
+func bookDest(entry *books.ReadEntry) []interface{} {
+	return []interface{}{
+		&entry.Book.ID,
+		&entry.Book.Hash,
+		&entry.Book.Title,
+		&entry.Book.Publisher,
+		&entry.Book.Year,
+		&entry.Book.PageCount,
+		(*stringList)(&entry.Book.Genres),
+		&entry.Book.ISBN,
+		&entry.Book.Version,
+		timestamp{&entry.LastModified},
+	}
+}
+
+type linkedAuthor struct {
+	books.ReadAuthor
+	updatedAt time.Time
+}
+
+// authorsOf reads the authors of the books in link order.
+func authorsOf(ctx context.Context, q querier, hashes []string) (map[string][]linkedAuthor, error) {
+	js, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT bal.book_id, a.id, a.name, a.author_id, a.books_authored, a.updated_at
+	FROM book_author_link bal
+	JOIN authors a ON a.author_id = bal.author_id
+	WHERE bal.book_id IN (SELECT value FROM json_each($1))
+	ORDER BY bal.id
+	`
+
+	rows, err := q.QueryContext(ctx, query, string(js))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byBook := map[string][]linkedAuthor{}
+
+	for rows.Next() {
+		var hash string
+		var a linkedAuthor
+
+		err := rows.Scan(&hash, &a.ID, &a.Name, &a.Identifier, &a.Books_authored, timestamp{&a.updatedAt})
+		if err != nil {
+			return nil, err
+		}
+
+		byBook[hash] = append(byBook[hash], a)
+	}
+
+	return byBook, rows.Err()
+}
+
+// fillAuthors does the array_agg part of the SQL models.
+func fillAuthors(ctx context.Context, q querier, entries []*books.ReadEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	hashes := make([]string, len(entries))
+	for i, entry := range entries {
+		hashes[i] = entry.Book.Hash
+	}
+
+	byBook, err := authorsOf(ctx, q, hashes)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entry.List = books.ReadAuthorList{}
+
+		for _, a := range byBook[entry.Book.Hash] {
+			entry.List.ID = append(entry.List.ID, a.ID)
+			entry.List.Name = append(entry.List.Name, a.Name)
+			entry.List.Identifier = append(entry.List.Identifier, a.Identifier)
+			entry.List.Books_authored = append(entry.List.Books_authored, a.Books_authored)
+
+			if a.updatedAt.After(entry.LastModified) {
+				entry.LastModified = a.updatedAt
+			}
+		}
+
+		entry.Authors = make([]books.ReadAuthor, len(entry.List.Name))
+		entry.Convert()
+	}
+
+	return nil
+}
+
+func (r reader) Get(ctx context.Context, tx books.Tx, read *books.ReadEntry) error {
+	q, err := r.s.query(tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	SELECT ` + bookColumns + `
+	FROM books b
+	WHERE b.id = $1 AND b.deleted_at IS NULL AND ` + linked
+
+	if err := q.QueryRowContext(ctx, query, read.Book.ID).Scan(bookDest(read)...); err != nil {
+		return err
+	}
+
+	return fillAuthors(ctx, q, []*books.ReadEntry{read})
+}
+
+func (r reader) GetMany(ctx context.Context, ids []int64) ([]*books.ReadEntry, error) {
+	query := `
+	SELECT ` + bookColumns + `
+	FROM books b
+	WHERE b.id IN (SELECT value FROM json_each($1)) AND b.deleted_at IS NULL AND ` + linked
+
+	found, err := r.books(ctx, query, idList(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]*books.ReadEntry, len(found))
+	for _, entry := range found {
+		byID[entry.Book.ID] = entry
+	}
+
+	entries := make([]*books.ReadEntry, 0, len(byID))
+	for _, id := range ids {
+		if entry, ok := byID[id]; ok {
+			entries = append(entries, entry)
+			delete(byID, id)
+		}
+	}
+
+	return entries, nil
+}
+
+// books runs a query over bookColumns and fills the authors of the result.
+func (r reader) books(ctx context.Context, query string, args ...interface{}) ([]*books.ReadEntry, error) {
+	rows, err := r.s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*books.ReadEntry{}
+
+	for rows.Next() {
+		entry := &books.ReadEntry{}
+		if err := rows.Scan(bookDest(entry)...); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	return entries, fillAuthors(ctx, r.s.DB, entries)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// LIKE is case insensitive for ASCII only, ILIKE also folds other letters.
+func (r reader) List(ctx context.Context, filter books.ListFilter, filters internal.Filters) ([]*books.ReadEntry, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), %s
+	FROM
+		books b
+	WHERE
+		b.deleted_at IS NULL AND %s
+		AND ($1 = '' OR b.title LIKE $1 ESCAPE '\' OR b.publisher LIKE $1 ESCAPE '\' OR EXISTS (
+			SELECT 1 FROM book_author_link l JOIN authors au ON au.author_id = l.author_id
+			WHERE l.book_id = b.book_id AND au.name LIKE $1 ESCAPE '\'
+		))
+		AND ($2 = '' OR EXISTS (SELECT 1 FROM json_each(b.genres) WHERE value = $2))
+		AND ($3 = 0 OR EXISTS (
+			SELECT 1 FROM book_author_link l JOIN authors au ON au.author_id = l.author_id
+			WHERE l.book_id = b.book_id AND au.id = $3
+		))
+	ORDER BY
+		b.%s %s, b.id ASC
+	LIMIT $4 OFFSET $5
+	`, bookColumns, linked, column, filters.SortDirection())
+
+	pattern := ""
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		pattern = "%" + likeEscaper.Replace(q) + "%"
+	}
+
+	args := []interface{}{pattern, filter.Genre, filter.AuthorID, filters.Limit(), filters.Offset()}
+
+	rows, err := r.s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	entries := []*books.ReadEntry{}
+
+	for rows.Next() {
+		entry := &books.ReadEntry{}
+
+		if err := rows.Scan(append([]interface{}{&totalRecords}, bookDest(entry)...)...); err != nil {
+			return nil, internal.Metadata{}, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	rows.Close()
+
+	if err := fillAuthors(ctx, r.s.DB, entries); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	return entries, internal.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+func (r reader) GenreCounts(ctx context.Context) ([]*books.GenreCount, error) {
+	query := `
+	SELECT g.value, count(*)
+	FROM books b, json_each(b.genres) g
+	WHERE b.deleted_at IS NULL
+	GROUP BY g.value
+	ORDER BY g.value
+	`
+
+	rows, err := r.s.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []*books.GenreCount{}
+
+	for rows.Next() {
+		var genre books.GenreCount
+		if err := rows.Scan(&genre.Genre, &genre.Books); err != nil {
+			return nil, err
+		}
+		genres = append(genres, &genre)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+// streamBatch is how many books Stream reads per transaction.
+const streamBatch = 500
+
+func (r reader) Stream(ctx context.Context, filter books.ExportFilter, fn func(*books.ReadEntry) error) error {
+	query := `
+	SELECT ` + bookColumns + `
+	FROM
+		books b
+	WHERE
+		b.deleted_at IS NULL AND ` + linked + `
+		AND ($1 = '' OR EXISTS (SELECT 1 FROM json_each(b.genres) WHERE value = $1))
+		AND ($2 = '' OR b.publisher = $2)
+		AND ($3 = 0 OR b.year >= $3)
+		AND ($4 = 0 OR b.year <= $4)
+		AND ($5 IS NULL OR b.updated_at >= $5 OR EXISTS (
+			SELECT 1 FROM book_author_link l JOIN authors au ON au.author_id = l.author_id
+			WHERE l.book_id = b.book_id AND au.updated_at >= $5
+		))
+		AND b.id > $6
+	ORDER BY
+		b.id
+	LIMIT $7
+	`
+
+	var afterID int64
+
+	for {
+		entries, err := r.books(ctx, query,
+			filter.Genre,
+			filter.Publisher,
+			filter.YearFrom,
+			filter.YearTo,
+			nullTime(filter.ModifiedSince),
+			afterID,
+			streamBatch,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		if len(entries) < streamBatch {
+			return nil
+		}
+		afterID = entries[len(entries)-1].Book.ID
+	}
+}
+
+func (r reader) Harvest(ctx context.Context, filter books.HarvestFilter) ([]*books.HarvestRecord, error) {
+	query := `
+	SELECT * FROM (
+		SELECT
+			` + bookColumns + `,
+			COALESCE(b.deleted_at, max(b.updated_at, (
+				SELECT max(au.updated_at)
+				FROM book_author_link l JOIN authors au ON au.author_id = l.author_id
+				WHERE l.book_id = b.book_id
+			))) AS datestamp,
+			b.deleted_at IS NOT NULL AS deleted
+		FROM
+			books b
+		WHERE
+			` + linked + `
+			AND ($1 = 0 OR b.id = $1)
+			AND (json_array_length($2) = 0 OR EXISTS (
+				SELECT 1 FROM json_each(b.genres) g WHERE g.value IN (SELECT value FROM json_each($2))
+			))
+			AND b.id > $3
+	) h
+	WHERE
+		($4 IS NULL OR h.datestamp >= $4)
+		AND ($5 IS NULL OR h.datestamp <= $5)
+	ORDER BY
+		h.id
+	LIMIT $6
+	`
+
+	args := []interface{}{
+		filter.ID,
+		stringList(filter.Genres),
+		filter.AfterID,
+		nullTime(filter.From),
+		nullTime(filter.Until),
+		filter.Limit,
+	}
+
+	rows, err := r.s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*books.HarvestRecord{}
+	entries := []*books.ReadEntry{}
+
+	for rows.Next() {
+		rec := &books.HarvestRecord{Entry: &books.ReadEntry{}}
+
+		dest := append(bookDest(rec.Entry), timestamp{&rec.Datestamp}, &rec.Deleted)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, rec)
+		entries = append(entries, rec.Entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	return records, fillAuthors(ctx, r.s.DB, entries)
+}
+
+func (r reader) Genres(ctx context.Context) ([]string, error) {
+	query := `
+	SELECT DISTINCT g.value AS genre
+	FROM books b, json_each(b.genres) g
+	ORDER BY genre
+	`
+
+	rows, err := r.s.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []string{}
+
+	for rows.Next() {
+		var genre string
+		if err := rows.Scan(&genre); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+func (r reader) EarliestDatestamp(ctx context.Context) (time.Time, error) {
+	var earliest time.Time
+
+	err := r.s.DB.QueryRowContext(ctx, `SELECT MIN(updated_at) FROM books`).Scan(timestamp{&earliest})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return earliest, nil
+}
+
+// the checks of the SQL model, in the same order
+var verifyChecks = []struct {
+	name   string
+	entity string
+	query  string
+}{
+	{
+		name:   "author_counter",
+		entity: authorEntity,
+		query: `
+		SELECT a.id, printf('books_authored is %d, %d books are linked', a.books_authored, count(b.id))
+		FROM authors a
+		LEFT JOIN book_author_link bal ON bal.author_id = a.author_id
+		LEFT JOIN books b ON b.book_id = bal.book_id AND b.deleted_at IS NULL
+		GROUP BY a.id, a.books_authored
+		HAVING a.books_authored != count(b.id)
+		ORDER BY a.id`,
+	},
+	{
+		name:   "orphan_author",
+		entity: authorEntity,
+		query: `
+		SELECT a.id, 'no book links to the author'
+		FROM authors a
+		WHERE NOT EXISTS (SELECT 1 FROM book_author_link bal WHERE bal.author_id = a.author_id)
+		ORDER BY a.id`,
+	},
+	{
+		name:   "book_without_authors",
+		entity: bookEntity,
+		query: `
+		SELECT b.id, 'the book has no authors'
+		FROM books b
+		WHERE NOT EXISTS (SELECT 1 FROM book_author_link bal WHERE bal.book_id = b.book_id)
+		ORDER BY b.id`,
+	},
+	{
+		name:   "missing_revision",
+		entity: bookEntity,
+		query: `
+		SELECT b.id, printf('no revision for version %d', b.version)
+		FROM books b
+		WHERE NOT EXISTS (
+			SELECT 1 FROM book_revisions r WHERE r.book_id = b.id AND r.version = b.version
+		)
+		ORDER BY b.id`,
+	},
+	{
+		name:   "book_hash",
+		entity: bookEntity,
+		query:  `SELECT id, book_id, title FROM books ORDER BY id`,
+	},
+	{
+		name:   "author_hash",
+		entity: authorEntity,
+		query:  `SELECT id, author_id, name FROM authors ORDER BY id`,
+	},
+}
+
+// Verify runs the consistency checks.
+func (r reader) Verify(ctx context.Context) ([]books.Problem, error) {
+	problems := []books.Problem{}
+
+	for _, check := range verifyChecks {
+		rows, err := r.s.DB.QueryContext(ctx, check.query)
+		if err != nil {
+			return nil, fmt.Errorf("verify %s: %w", check.name, err)
+		}
+
+		for rows.Next() {
+			problem := books.Problem{Check: check.name, Entity: check.entity}
+
+			found, err := scanProblem(rows, strings.HasSuffix(check.name, "_hash"), &problem)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if found {
+				problems = append(problems, problem)
+			}
+		}
+
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return problems, nil
+}
+
+// see books.HashEntries
+func scanProblem(rows *sql.Rows, hashed bool, problem *books.Problem) (bool, error) {
+	if !hashed {
+		return true, rows.Scan(&problem.ID, &problem.Detail)
+	}
+
+	var hash, source string
+	if err := rows.Scan(&problem.ID, &hash, &source); err != nil {
+		return false, err
+	}
+
+	sum := sha1.Sum([]byte(source))
+	want := hex.EncodeToString(sum[:])
+	if hash == want {
+		return false, nil
+	}
+
+	problem.Detail = fmt.Sprintf("hash %s does not match %q, expected %s", hash, source, want)
+
+	return true, nil
+}
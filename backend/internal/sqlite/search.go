@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+)
+
+const (
+	creatorExists = `EXISTS (SELECT 1 FROM book_author_link l JOIN authors au ON au.author_id = l.author_id WHERE l.book_id = b.book_id AND %s)`
+	subjectExists = `EXISTS (SELECT 1 FROM json_each(b.genres) g WHERE %s)`
+)
+
+// searchSQL writes a Condition over the books table aliased b.
+type searchSQL struct {
+	args []interface{}
+}
+
+func (s *searchSQL) arg(value interface{}) string {
+	s.args = append(s.args, value)
+	return fmt.Sprintf("$%d", len(s.args))
+}
+
+func (s *searchSQL) where(cond books.Condition) (string, error) {
+	switch c := cond.(type) {
+	case books.And:
+		return s.pair(c.Left, " AND ", c.Right)
+	case books.Or:
+		return s.pair(c.Left, " OR ", c.Right)
+	case books.Not:
+		inner, err := s.where(c.Condition)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + inner + ")", nil
+	case books.Everything:
+		return "1", nil
+	case books.TextMatch:
+		return s.text(c.Field, func(col string) string {
+			return col + " LIKE " + s.arg(c.Pattern.Like()) + ` ESCAPE '\'`
+		})
+	case books.TextCompare:
+		if !c.Op.Ordering() {
+			break
+		}
+		return s.text(c.Field, func(col string) string {
+			return "lower(" + col + ") " + string(c.Op) + " lower(" + s.arg(c.Value) + ")"
+		})
+	case books.YearCompare:
+		if !c.Op.Valid() {
+			break
+		}
+		return "b.year " + string(c.Op) + " " + s.arg(c.Year), nil
+	case books.ISBNIs:
+		return "b.isbn = " + s.arg(c.ISBN), nil
+	}
+
+	return "", fmt.Errorf("%w: %#v", books.ErrUnsupported, cond)
+}
+
+func (s *searchSQL) pair(left books.Condition, op string, right books.Condition) (string, error) {
+	l, err := s.where(left)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := s.where(right)
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + l + op + r + ")", nil
+}
+
+func (s *searchSQL) text(field books.TextField, match func(col string) string) (string, error) {
+	switch field {
+	case books.FieldTitle:
+		return match("b.title"), nil
+	case books.FieldPublisher:
+		return match("b.publisher"), nil
+	case books.FieldCreator:
+		return fmt.Sprintf(creatorExists, match("au.name")), nil
+	case books.FieldSubject:
+		return fmt.Sprintf(subjectExists, match("g.value")), nil
+	}
+
+	return "", fmt.Errorf("%w: field %d", books.ErrUnsupported, field)
+}
+
+func (r reader) Search(ctx context.Context, cond books.Condition, limit, offset int) ([]*books.ReadEntry, int, error) {
+	search := &searchSQL{}
+
+	where, err := search.where(cond)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), %s
+	FROM books b
+	WHERE b.deleted_at IS NULL AND %s AND %s
+	ORDER BY b.id
+	LIMIT $%d OFFSET $%d
+	`, bookColumns, linked, where, len(search.args)+1, len(search.args)+2)
+
+	args := append(append([]interface{}{}, search.args...), limit, offset)
+
+	rows, err := r.s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	total := 0
+	entries := []*books.ReadEntry{}
+
+	for rows.Next() {
+		entry := &books.ReadEntry{}
+
+		if err := rows.Scan(append([]interface{}{&total}, bookDest(entry)...)...); err != nil {
+			return nil, 0, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+
+	//a page past the end has no rows to carry the total
+	if len(entries) == 0 && offset > 0 {
+		count := fmt.Sprintf(`SELECT count(*) FROM books b WHERE b.deleted_at IS NULL AND %s AND %s`, linked, where)
+		if err := r.s.DB.QueryRowContext(ctx, count, search.args...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := fillAuthors(ctx, r.s.DB, entries); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
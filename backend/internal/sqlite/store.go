@@ -0,0 +1,194 @@
+// Package sqlite keeps the catalogue in a SQLite file.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/users"
+
+	_ "modernc.org/sqlite"
+)
+
+// Scheme selects this store in a dsn, as in sqlite:library.db.
+const Scheme = "sqlite"
+
+// entity names of the audit log entries, the same as the SQL models use
+const (
+	bookEntity   = "book"
+	authorEntity = "author"
+)
+
+func IsDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, Scheme+":")
+}
+
+// Open opens the database file of dsn.
+func Open(dsn string) (*sql.DB, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme != Scheme {
+		return nil, fmt.Errorf("sqlite: invalid dsn %q", dsn)
+	}
+
+	path := u.Opaque
+	if path == "" {
+		path = u.Host + u.Path
+	}
+	if path == "" {
+		return nil, fmt.Errorf("sqlite: the dsn %q has no path", dsn)
+	}
+
+	query := u.Query()
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	if query.Get("_txlock") == "" {
+		query.Set("_txlock", "immediate")
+	}
+
+	return sql.Open("sqlite", "file:"+path+"?"+query.Encode())
+}
+
+// Store is the catalogue kept in a SQLite database.
+type Store struct {
+	DB *sql.DB
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{DB: db}
+}
+
+func (s *Store) BeginTx(ctx context.Context) (books.Tx, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// unwrap returns the *sql.Tx of a transaction begun by the store.
+func unwrap(tx books.Tx) (*sql.Tx, error) {
+	sqltx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", books.ErrForeignTx, tx)
+	}
+	return sqltx, nil
+}
+
+// querier is either the database or a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// query reads in tx when there is one.
+func (s *Store) query(tx books.Tx) (querier, error) {
+	if tx == nil {
+		return s.DB, nil
+	}
+	return unwrap(tx)
+}
+
+// now is NOW() of the SQL models, the layout below being the one written.
+const now = `strftime('%Y-%m-%dT%H:%M:%fZ', 'now')`
+
+const timeLayout = "2006-01-02T15:04:05.000Z"
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// nullTime is a timestamp argument, NULL for the zero time.
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(t), Valid: true}
+}
+
+// timestamp scans a text timestamp, NULL gives the zero time.
+type timestamp struct {
+	t *time.Time
+}
+
+func (ts timestamp) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*ts.t = time.Time{}
+		return nil
+	case time.Time:
+		*ts.t = v
+		return nil
+	case string:
+		return ts.parse(v)
+	case []byte:
+		return ts.parse(string(v))
+	}
+	return fmt.Errorf("sqlite: cannot scan %T into a timestamp", src)
+}
+
+func (ts timestamp) parse(s string) error {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return err
+	}
+	*ts.t = t
+	return nil
+}
+
+// stringList is a text[] column kept as a json array.
+type stringList []string
+
+func (l stringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+
+	js, err := json.Marshal([]string(l))
+	return string(js), err
+}
+
+func (l *stringList) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case []byte:
+		return json.Unmarshal(v, l)
+	}
+	return fmt.Errorf("sqlite: cannot scan %T into a list", src)
+}
+
+// idList passes ids to json_each, the ANY($1) of the SQL models.
+func idList(ids []int64) string {
+	js, _ := json.Marshal(ids)
+	return string(js)
+}
+
+// notFound turns sql.ErrNoRows into books.ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return books.ErrNotFound
+	}
+	return err
+}
+
+// Models are the repositories of the store.
+func (s *Store) Read() books.ReadRepository          { return reader{s} }
+func (s *Store) Create() books.CreateRepository      { return creator{s} }
+func (s *Store) Update() books.UpdateRepository      { return updater{s} }
+func (s *Store) Delete() books.DeleteRepository      { return deleter{s} }
+func (s *Store) Authors() books.AuthorRepository     { return authors{s} }
+func (s *Store) Links() books.LinkRepository         { return links{s} }
+func (s *Store) Revisions() books.RevisionRepository { return revisions{s} }
+func (s *Store) Audit() audit.Repository             { return auditLog{s} }
+func (s *Store) Users() users.Repository             { return &users.UserModel{DB: s.DB} }
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/migrate"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/storetest"
+	"github.com/3WDeveloper-GM/library_app/migrations"
+)
+
+func open(t *testing.T) *sql.DB {
+	db, err := Open(Scheme + ":" + filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrate.New(db, migrations.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Dialect = migrate.SQLite
+
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Repositories {
+		s := New(open(t))
+
+		return storetest.Repositories{
+			Read:      s.Read(),
+			Create:    s.Create(),
+			Update:    s.Update(),
+			Delete:    s.Delete(),
+			Authors:   s.Authors(),
+			Links:     s.Links(),
+			Revisions: s.Revisions(),
+			Audit:     s.Audit(),
+		}
+	})
+}
+
+func TestAuditLogAppendOnly(t *testing.T) {
+	db := open(t)
+
+	_, err := db.Exec(`INSERT INTO audit_log(actor, entity, entity_id, action) VALUES('admin', 'book', 1, 'insert')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{
+		`UPDATE audit_log SET actor = 'someone else'`,
+		`DELETE FROM audit_log`,
+	} {
+		if _, err := db.Exec(query); err == nil || !strings.Contains(err.Error(), "append only") {
+			t.Errorf("%s: got %v, want the append only error", query, err)
+		}
+	}
+}
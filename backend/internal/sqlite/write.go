@@ -0,0 +1,585 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+type creator struct {
+	s *Store
+}
+
+func (c creator) BeginTx(ctx context.Context) (books.Tx, error) {
+	return c.s.BeginTx(ctx)
+}
+
+// upsertAuthor creates the author or counts one more book for it.
+func upsertAuthor(ctx context.Context, tx *sql.Tx, name, hash string) (books.ReadAuthor, error) {
+	query := `
+		INSERT INTO authors(name, author_id, books_authored)
+		VALUES($1, $2, 1)
+		ON CONFLICT (author_id) DO UPDATE
+		SET books_authored = books_authored + 1, updated_at = ` + now + `
+		RETURNING id, name, author_id, books_authored
+	`
+
+	var a books.ReadAuthor
+	err := tx.QueryRowContext(ctx, query, name, hash).Scan(&a.ID, &a.Name, &a.Identifier, &a.Books_authored)
+
+	return a, err
+}
+
+func linkAuthor(ctx context.Context, tx *sql.Tx, bookHash, authorHash string) error {
+	query := `
+		INSERT INTO book_author_link(book_id, author_id)
+		VALUES($1, $2)
+		ON CONFLICT (book_id, author_id) DO NOTHING
+	`
+
+	_, err := tx.ExecContext(ctx, query, bookHash, authorHash)
+	return err
+}
+
+// duplicate returns a books.DuplicateError when a book holds hash already.
+func duplicate(ctx context.Context, tx *sql.Tx, hash string) error {
+	var id int64
+	var trashed bool
+
+	err := tx.QueryRowContext(ctx, `SELECT id, deleted_at IS NOT NULL FROM books WHERE book_id = $1`, hash).Scan(&id, &trashed)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	return &books.DuplicateError{ID: id, Trashed: trashed}
+}
+
+func (c creator) Insert(ctx context.Context, t books.Tx, entry *books.CreateBookEntry, read *books.ReadEntry) error {
+	tx, err := unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	if err := duplicate(ctx, tx, entry.Book.Hash); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO books(book_id, title, publisher, year, page_count, genres, isbn)
+		VALUES($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, book_id, title, publisher, year, page_count, genres, isbn, version
+	`
+
+	args := []interface{}{
+		entry.Book.Hash,
+		entry.Book.Title,
+		entry.Book.Publisher,
+		entry.Book.Year,
+		entry.Book.PageCount,
+		stringList(entry.Book.Genres),
+		entry.Book.ISBN,
+	}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&read.Book.ID,
+		&read.Book.Hash,
+		&read.Book.Title,
+		&read.Book.Publisher,
+		&read.Book.Year,
+		&read.Book.PageCount,
+		(*stringList)(&read.Book.Genres),
+		&read.Book.ISBN,
+		&read.Book.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	read.List = books.ReadAuthorList{}
+	seen := map[string]bool{}
+
+	//a name given twice is linked once
+	for index, name := range entry.Authors.List {
+		hash := entry.Authors.Hash[index]
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		a, err := upsertAuthor(ctx, tx, name, hash)
+		if err != nil {
+			return err
+		}
+
+		if err := linkAuthor(ctx, tx, entry.Book.Hash, hash); err != nil {
+			return err
+		}
+
+		read.List.ID = append(read.List.ID, a.ID)
+		read.List.Name = append(read.List.Name, a.Name)
+		read.List.Identifier = append(read.List.Identifier, a.Identifier)
+		read.List.Books_authored = append(read.List.Books_authored, a.Books_authored)
+	}
+
+	snapshot := read.Snapshot()
+
+	if err := recordRevision(ctx, tx, read.Book.ID, snapshot); err != nil {
+		return err
+	}
+
+	return audit.Record(ctx, tx, bookEntity, read.Book.ID, audit.ActionInsert, nil, snapshot)
+}
+
+func (c creator) Save(ctx context.Context, entry *books.CreateBookEntry, read *books.ReadEntry) error {
+	tx, err := c.s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := c.Insert(ctx, tx, entry, read); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	read.Authors = make([]books.ReadAuthor, len(read.List.Name))
+	read.Convert()
+
+	return nil
+}
+
+func (c creator) ExistingHashes(ctx context.Context, hashes []string) (map[string]bool, error) {
+	js, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT book_id FROM books
+		WHERE book_id IN (SELECT value FROM json_each($1))
+	`
+
+	rows, err := c.s.DB.QueryContext(ctx, query, string(js))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		existing[hash] = true
+	}
+
+	return existing, rows.Err()
+}
+
+func recordRevision(ctx context.Context, tx *sql.Tx, bookID int64, snapshot books.Snapshot) error {
+	js, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO book_revisions(book_id, version, actor, snapshot)
+		VALUES($1, $2, $3, $4)
+	`
+
+	_, err = tx.ExecContext(ctx, query, bookID, snapshot.Version, audit.FromContext(ctx).Actor, string(js))
+	return err
+}
+
+type updater struct {
+	s *Store
+}
+
+// Save writes entry over current when the version still matches.
+func (u updater) Save(ctx context.Context, entry *books.UpdateEntry, current *books.ReadEntry) error {
+	entry.HashEntries()
+
+	tx, err := u.s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before := current.Snapshot()
+
+	query := `
+		UPDATE books
+		SET title = $1, publisher = $2, year = $3, page_count = $4, genres = $5, isbn = COALESCE($8, isbn), version = version + 1, updated_at = ` + now + `
+		WHERE id = $6 AND version = $7 AND deleted_at IS NULL
+		RETURNING book_id, version
+	`
+
+	args := []interface{}{
+		entry.Book.Title,
+		entry.Book.Publisher,
+		entry.Book.Year,
+		entry.Book.PageCount,
+		stringList(entry.Book.Genres),
+		entry.Book.ID,
+		entry.Book.Version,
+		entry.Book.ISBN,
+	}
+
+	var hash string
+	var version int32
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&hash, &version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return books.ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	kept, err := linkedAuthors(ctx, tx, hash)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		UPDATE authors
+		SET books_authored = books_authored - 1, updated_at = ` + now + `
+		WHERE author_id IN (SELECT author_id FROM book_author_link WHERE book_id = $1)
+			AND author_id NOT IN (SELECT value FROM json_each($2))
+	`
+
+	if _, err := tx.ExecContext(ctx, query, hash, stringList(entry.Author.Hash)); err != nil {
+		return err
+	}
+
+	//only the new authors count one more book
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_author_link WHERE book_id = $1`, hash); err != nil {
+		return err
+	}
+
+	for index, name := range entry.Author.Name {
+		authorHash := entry.Author.Hash[index]
+
+		if !kept[authorHash] {
+			if _, err := upsertAuthor(ctx, tx, name, authorHash); err != nil {
+				return err
+			}
+			kept[authorHash] = true
+		}
+
+		if err := linkAuthor(ctx, tx, hash, authorHash); err != nil {
+			return err
+		}
+	}
+
+	updated := &books.ReadEntry{Book: books.ReadBook{ID: *entry.Book.ID}}
+	if err := (reader{u.s}).Get(ctx, tx, updated); err != nil {
+		return err
+	}
+
+	after := entry.Snapshot(version)
+
+	if err := recordRevision(ctx, tx, updated.Book.ID, after); err != nil {
+		return err
+	}
+
+	if err := audit.Record(ctx, tx, bookEntity, updated.Book.ID, audit.ActionUpdate, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	current.Book = updated.Book
+	current.List = updated.List
+	current.LastModified = updated.LastModified
+
+	return nil
+}
+
+// linkedAuthors are the hashes of the authors linked to a book.
+func linkedAuthors(ctx context.Context, tx *sql.Tx, bookHash string) (map[string]bool, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT author_id FROM book_author_link WHERE book_id = $1`, bookHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	linked := map[string]bool{}
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		linked[hash] = true
+	}
+
+	return linked, rows.Err()
+}
+
+type deleter struct {
+	s *Store
+}
+
+func (d deleter) BeginTx(ctx context.Context) (books.Tx, error) {
+	return d.s.BeginTx(ctx)
+}
+
+// DeleteBook only tombstones the book, see the SQL model.
+func (d deleter) DeleteBook(ctx context.Context, t books.Tx, id *books.DeleteID) error {
+	tx, err := unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	before := &books.ReadEntry{Book: books.ReadBook{ID: id.ID}}
+	if err := (reader{d.s}).Get(ctx, tx, before); err != nil {
+		return notFound(err)
+	}
+
+	query := `
+		UPDATE books
+		SET deleted_at = ` + now + `, updated_at = ` + now + `
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING book_id
+	`
+
+	if err := tx.QueryRowContext(ctx, query, id.ID).Scan(&id.Hash); err != nil {
+		return notFound(err)
+	}
+
+	if err := changeAuthorCounts(ctx, tx, id.Hash, -1); err != nil {
+		return err
+	}
+
+	return audit.Record(ctx, tx, bookEntity, id.ID, audit.ActionDelete, before.Snapshot(), nil)
+}
+
+func (d deleter) Restore(ctx context.Context, t books.Tx, id *books.DeleteID) error {
+	tx, err := unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE books
+		SET deleted_at = NULL, updated_at = ` + now + `
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING book_id
+	`
+
+	if err := tx.QueryRowContext(ctx, query, id.ID).Scan(&id.Hash); err != nil {
+		return notFound(err)
+	}
+
+	if err := changeAuthorCounts(ctx, tx, id.Hash, 1); err != nil {
+		return err
+	}
+
+	after := &books.ReadEntry{Book: books.ReadBook{ID: id.ID}}
+	if err := (reader{d.s}).Get(ctx, tx, after); err != nil {
+		return err
+	}
+
+	return audit.Record(ctx, tx, bookEntity, id.ID, audit.ActionRestore, nil, after.Snapshot())
+}
+
+func changeAuthorCounts(ctx context.Context, tx *sql.Tx, bookHash string, delta int) error {
+	query := `
+		UPDATE authors
+		SET books_authored = books_authored + $2, updated_at = ` + now + `
+		WHERE author_id IN (
+			SELECT author_id FROM book_author_link WHERE book_id = $1
+		)
+	`
+
+	result, err := tx.ExecContext(ctx, query, bookHash, delta)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return books.ErrEditConflict
+	}
+
+	return nil
+}
+
+func (d deleter) ListTrash(ctx context.Context, filters internal.Filters) ([]*books.TrashedBook, internal.Metadata, error) {
+	column, err := filters.SortColumn()
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		count(*) OVER(),
+		b.id,
+		b.book_id,
+		b.title,
+		b.publisher,
+		b.year,
+		b.page_count,
+		b.genres,
+		b.version,
+		b.deleted_at
+	FROM
+		books b
+	WHERE
+		b.deleted_at IS NOT NULL AND %s
+	ORDER BY
+		b.%s %s, b.id ASC
+	LIMIT $1 OFFSET $2
+	`, linked, column, filters.SortDirection())
+
+	rows, err := d.s.DB.QueryContext(ctx, query, filters.Limit(), filters.Offset())
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	trashed := []*books.TrashedBook{}
+	hashes := []string{}
+
+	for rows.Next() {
+		var book books.TrashedBook
+
+		err := rows.Scan(
+			&totalRecords,
+			&book.ID,
+			&book.Hash,
+			&book.Title,
+			&book.Publisher,
+			&book.Year,
+			&book.PageCount,
+			(*stringList)(&book.Genres),
+			&book.Version,
+			timestamp{&book.DeletedAt},
+		)
+		if err != nil {
+			return nil, internal.Metadata{}, err
+		}
+
+		trashed = append(trashed, &book)
+		hashes = append(hashes, book.Hash)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, internal.Metadata{}, err
+	}
+	rows.Close()
+
+	byBook, err := authorsOf(ctx, d.s.DB, hashes)
+	if err != nil {
+		return nil, internal.Metadata{}, err
+	}
+
+	for _, book := range trashed {
+		for _, a := range byBook[book.Hash] {
+			book.Authors = append(book.Authors, a.Name)
+		}
+	}
+
+	return trashed, internal.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// purgedBook is the state the audit log keeps of a purged book.
+type purgedBook struct {
+	id    int64
+	Hash  string `json:"hash"`
+	Title string `json:"title"`
+}
+
+// Purge removes a trashed book for good.
+func (d deleter) Purge(ctx context.Context, t books.Tx, id *books.DeleteID) error {
+	tx, err := unwrap(t)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM books
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING book_id, title
+	`
+
+	purged := purgedBook{id: id.ID}
+
+	if err := tx.QueryRowContext(ctx, query, id.ID).Scan(&purged.Hash, &purged.Title); err != nil {
+		return notFound(err)
+	}
+
+	id.Hash = purged.Hash
+
+	return audit.Record(ctx, tx, bookEntity, id.ID, audit.ActionPurge, purged, nil)
+}
+
+func (d deleter) PurgeDeletedBefore(ctx context.Context, t books.Tx, before time.Time) (int64, error) {
+	tx, err := unwrap(t)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		DELETE FROM books
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		RETURNING id, book_id, title
+	`
+
+	rows, err := tx.QueryContext(ctx, query, formatTime(before))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	purged := []purgedBook{}
+
+	for rows.Next() {
+		var book purgedBook
+
+		if err := rows.Scan(&book.id, &book.Hash, &book.Title); err != nil {
+			return 0, err
+		}
+
+		purged = append(purged, book)
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	for _, book := range purged {
+		err = audit.Record(ctx, tx, bookEntity, book.id, audit.ActionPurge, book, nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(purged)), nil
+}
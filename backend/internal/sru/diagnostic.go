@@ -0,0 +1,48 @@
+package sru
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const diagnosticPrefix = "info:srw/diagnostic/1/"
+
+// diagnostic codes from the SRU diagnostics list
+const (
+	DiagGeneral                 = 1
+	DiagUnsupportedOperation    = 4
+	DiagUnsupportedVersion      = 5
+	DiagUnsupportedParamValue   = 6
+	DiagMissingParameter        = 7
+	DiagUnsupportedParameter    = 8
+	DiagQuerySyntax             = 10
+	DiagUnsupportedIndex        = 16
+	DiagUnsupportedRelation     = 19
+	DiagUnsupportedRelModifier  = 20
+	DiagEmptyTerm               = 27
+	DiagInvalidTerm             = 36
+	DiagUnsupportedBoolean      = 37
+	DiagUnsupportedBoolModifier = 46
+	DiagFirstRecordOutOfRange   = 61
+	DiagUnknownSchema           = 66
+	DiagSortUnsupported         = 80
+)
+
+// Diagnostic is an SRU error reported inside the response.
+type Diagnostic struct {
+	Code    int
+	Details string
+	Message string
+}
+
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("sru diagnostic %d: %s", d.Code, d.Message)
+}
+
+func (d *Diagnostic) URI() string {
+	return diagnosticPrefix + strconv.Itoa(d.Code)
+}
+
+func diagnose(code int, details, format string, args ...interface{}) *Diagnostic {
+	return &Diagnostic{Code: code, Details: details, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,135 @@
+package sru
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	explainNamespace = "http://explain.z3950.org/dtd/2.0/"
+	dcContextSet     = "info:srw/cql-context-set/1/dc-v1.1"
+	cqlContextSet    = "info:srw/cql-context-set/1/cql-v1.2"
+)
+
+type zeerex struct {
+	XMLName    xml.Name         `xml:"zr:explain"`
+	NS         string           `xml:"xmlns:zr,attr"`
+	ServerInfo zeerexServerInfo `xml:"zr:serverInfo"`
+	Database   zeerexDatabase   `xml:"zr:databaseInfo"`
+	IndexInfo  zeerexIndexInfo  `xml:"zr:indexInfo"`
+	SchemaInfo []zeerexSchema   `xml:"zr:schemaInfo>zr:schema"`
+	ConfigInfo []zeerexDefault  `xml:"zr:configInfo>zr:default"`
+}
+
+type zeerexServerInfo struct {
+	Protocol  string `xml:"protocol,attr"`
+	Version   string `xml:"version,attr"`
+	Transport string `xml:"transport,attr"`
+	Host      string `xml:"zr:host"`
+	Port      string `xml:"zr:port"`
+	Database  string `xml:"zr:database"`
+}
+
+type zeerexDatabase struct {
+	Title string `xml:"zr:title"`
+}
+
+type zeerexIndexInfo struct {
+	Sets    []zeerexSet   `xml:"zr:set"`
+	Indexes []zeerexIndex `xml:"zr:index"`
+}
+
+type zeerexSet struct {
+	Name       string `xml:"name,attr"`
+	Identifier string `xml:"identifier,attr"`
+}
+
+type zeerexIndex struct {
+	Title string        `xml:"zr:title"`
+	Map   zeerexNameMap `xml:"zr:map>zr:name"`
+}
+
+type zeerexNameMap struct {
+	Set  string `xml:"set,attr"`
+	Name string `xml:",chardata"`
+}
+
+type zeerexSchema struct {
+	Identifier string `xml:"identifier,attr"`
+	Name       string `xml:"name,attr"`
+	Location   string `xml:"location,attr"`
+	Title      string `xml:"zr:title"`
+}
+
+type zeerexDefault struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (s *Service) explain(baseURL string, diag *Diagnostic) *ExplainResponse {
+	u, _ := url.Parse(baseURL)
+	if u == nil {
+		u = &url.URL{}
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	z := &zeerex{
+		NS: explainNamespace,
+		ServerInfo: zeerexServerInfo{
+			Protocol:  "SRU",
+			Version:   Version,
+			Transport: u.Scheme,
+			Host:      u.Hostname(),
+			Port:      port,
+			Database:  strings.TrimPrefix(u.Path, "/"),
+		},
+		Database: zeerexDatabase{Title: s.DatabaseTitle},
+		IndexInfo: zeerexIndexInfo{
+			Sets: []zeerexSet{
+				{Name: "dc", Identifier: dcContextSet},
+				{Name: "cql", Identifier: cqlContextSet},
+			},
+		},
+		ConfigInfo: []zeerexDefault{
+			{Type: "numberOfRecords", Value: strconv.Itoa(defaultMaximumRecords)},
+			{Type: "maximumRecords", Value: strconv.Itoa(maxMaximumRecords)},
+		},
+	}
+
+	for _, index := range Indexes() {
+		set, name, _ := strings.Cut(index, ".")
+		z.IndexInfo.Indexes = append(z.IndexInfo.Indexes, zeerexIndex{
+			Title: name,
+			Map:   zeerexNameMap{Set: set, Name: name},
+		})
+	}
+
+	for _, sc := range schemas {
+		z.SchemaInfo = append(z.SchemaInfo, zeerexSchema{Identifier: sc.Identifier, Name: sc.Name, Location: sc.Location, Title: sc.Title})
+	}
+
+	res := &ExplainResponse{
+		NS:      Namespace,
+		Version: Version,
+		Record: &Record{
+			Schema:   explainNamespace,
+			Escaping: "xml",
+			Data:     RecordData{Payload: z},
+		},
+	}
+
+	if diag != nil {
+		res.Diagnostics = newDiagnostics(diag)
+	}
+
+	return res
+}
@@ -0,0 +1,67 @@
+package sru
+
+import (
+	"encoding/xml"
+)
+
+const (
+	Namespace           = "http://docs.oasis-open.org/ns/search-ws/sruResponse"
+	diagnosticNamespace = "http://docs.oasis-open.org/ns/search-ws/diagnostic"
+	Version             = "2.0"
+	exactCount          = "info:srw/vocabulary/resultCountPrecision/1/exact"
+)
+
+type SearchRetrieveResponse struct {
+	XMLName              xml.Name     `xml:"sru:searchRetrieveResponse"`
+	NS                   string       `xml:"xmlns:sru,attr"`
+	Version              string       `xml:"sru:version"`
+	NumberOfRecords      int          `xml:"sru:numberOfRecords"`
+	Records              *Records     `xml:"sru:records"`
+	NextRecordPosition   int          `xml:"sru:nextRecordPosition,omitempty"`
+	Diagnostics          *Diagnostics `xml:"sru:diagnostics"`
+	ResultCountPrecision string       `xml:"sru:resultCountPrecision,omitempty"`
+}
+
+type ExplainResponse struct {
+	XMLName     xml.Name     `xml:"sru:explainResponse"`
+	NS          string       `xml:"xmlns:sru,attr"`
+	Version     string       `xml:"sru:version"`
+	Record      *Record      `xml:"sru:record"`
+	Diagnostics *Diagnostics `xml:"sru:diagnostics"`
+}
+
+type Records struct {
+	Records []Record `xml:"sru:record"`
+}
+
+type Record struct {
+	Schema   string     `xml:"sru:recordSchema"`
+	Escaping string     `xml:"sru:recordXMLEscaping"`
+	Data     RecordData `xml:"sru:recordData"`
+	Position int        `xml:"sru:recordPosition,omitempty"`
+}
+
+// RecordData holds the payload as xml or as escaped text.
+type RecordData struct {
+	Payload interface{}
+	Text    string `xml:",chardata"`
+}
+
+type Diagnostics struct {
+	NS          string             `xml:"xmlns:diag,attr"`
+	Diagnostics []diagnosticRecord `xml:"diag:diagnostic"`
+}
+
+type diagnosticRecord struct {
+	URI     string `xml:"diag:uri"`
+	Details string `xml:"diag:details,omitempty"`
+	Message string `xml:"diag:message"`
+}
+
+func newDiagnostics(diags ...*Diagnostic) *Diagnostics {
+	d := &Diagnostics{NS: diagnosticNamespace}
+	for _, diag := range diags {
+		d.Diagnostics = append(d.Diagnostics, diagnosticRecord{URI: diag.URI(), Details: diag.Details, Message: diag.Message})
+	}
+	return d
+}
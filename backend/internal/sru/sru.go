@@ -0,0 +1,267 @@
+package sru
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/cql"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/dublincore"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/marc"
+)
+
+const (
+	defaultMaximumRecords = 10
+	maxMaximumRecords     = 100
+)
+
+type schema struct {
+	Name       string
+	Identifier string
+	Title      string
+	Location   string
+}
+
+var schemas = []schema{
+	{Name: "dc", Identifier: "info:srw/schema/1/dc-v1.1", Title: "Dublin Core", Location: "http://www.loc.gov/standards/sru/recordSchemas/dc-schema.xsd"},
+	{Name: "marcxml", Identifier: "info:srw/schema/1/marcxml-v1.1", Title: "MARC21 XML", Location: "http://www.loc.gov/standards/marcxml/schema/MARC21slim.xsd"},
+}
+
+func lookupSchema(name string) (schema, bool) {
+	for _, s := range schemas {
+		if s.Name == name || s.Identifier == name {
+			return s, true
+		}
+	}
+	return schema{}, false
+}
+
+// parameters of searchRetrieve and explain
+var parameters = map[string]bool{
+	"operation":         true,
+	"version":           true,
+	"query":             true,
+	"queryType":         true,
+	"startRecord":       true,
+	"maximumRecords":    true,
+	"recordSchema":      true,
+	"recordXMLEscaping": true,
+	"recordPacking":     true,
+	"sortKeys":          true,
+	"stylesheet":        true,
+	"httpAccept":        true,
+}
+
+type Service struct {
+	Read          books.ReadRepository
+	DatabaseTitle string
+}
+
+// Handle runs searchRetrieve, or explain without a query.
+func (s *Service) Handle(ctx context.Context, baseURL string, args url.Values) (interface{}, error) {
+	operation := args.Get("operation")
+	if operation == "" {
+		operation = "explain"
+		if args.Has("query") {
+			operation = "searchRetrieve"
+		}
+	}
+
+	if diag := checkParameters(args); diag != nil {
+		if operation == "explain" {
+			return s.explain(baseURL, diag), nil
+		}
+		return failed(diag), nil
+	}
+
+	switch operation {
+	case "searchRetrieve":
+		return s.searchRetrieve(ctx, args)
+	case "explain":
+		return s.explain(baseURL, nil), nil
+	}
+
+	return failed(diagnose(DiagUnsupportedOperation, operation, "operation %q is not supported", operation)), nil
+}
+
+func checkParameters(args url.Values) *Diagnostic {
+	for key, values := range args {
+		if strings.HasPrefix(key, "x-") {
+			continue
+		}
+		if !parameters[key] {
+			return diagnose(DiagUnsupportedParameter, key, "parameter %q is not supported", key)
+		}
+		if len(values) > 1 {
+			return diagnose(DiagUnsupportedParamValue, key, "parameter %q is repeated", key)
+		}
+	}
+
+	if version := args.Get("version"); version != "" && version != Version {
+		return diagnose(DiagUnsupportedVersion, Version, "version %q is not supported", version)
+	}
+
+	if args.Has("sortKeys") {
+		return diagnose(DiagSortUnsupported, "", "sorting is not supported")
+	}
+
+	return nil
+}
+
+func failed(diags ...*Diagnostic) *SearchRetrieveResponse {
+	return &SearchRetrieveResponse{
+		NS:          Namespace,
+		Version:     Version,
+		Diagnostics: newDiagnostics(diags...),
+	}
+}
+
+func (s *Service) searchRetrieve(ctx context.Context, args url.Values) (interface{}, error) {
+	query := args.Get("query")
+	if query == "" {
+		return failed(diagnose(DiagMissingParameter, "query", "the query parameter is required")), nil
+	}
+
+	if queryType := args.Get("queryType"); queryType != "" && queryType != "cql" {
+		return failed(diagnose(DiagUnsupportedParamValue, "queryType", "only cql queries are supported")), nil
+	}
+
+	start, diag := intParameter(args, "startRecord", 1, 1)
+	if diag != nil {
+		return failed(diag), nil
+	}
+
+	maximum, diag := intParameter(args, "maximumRecords", defaultMaximumRecords, 0)
+	if diag != nil {
+		return failed(diag), nil
+	}
+	if maximum > maxMaximumRecords {
+		maximum = maxMaximumRecords
+	}
+
+	recordSchema, ok := lookupSchema(args.Get("recordSchema"))
+	if !args.Has("recordSchema") {
+		recordSchema, ok = schemas[0], true
+	}
+	if !ok {
+		return failed(diagnose(DiagUnknownSchema, args.Get("recordSchema"), "record schema %q is not known", args.Get("recordSchema"))), nil
+	}
+
+	escaping := args.Get("recordXMLEscaping")
+	if escaping == "" {
+		escaping = "xml"
+	}
+	if escaping != "xml" && escaping != "string" {
+		return failed(diagnose(DiagUnsupportedParamValue, "recordXMLEscaping", "recordXMLEscaping must be xml or string")), nil
+	}
+
+	if packing := args.Get("recordPacking"); packing != "" && packing != "packed" {
+		return failed(diagnose(DiagUnsupportedParamValue, "recordPacking", "only packed records are supported")), nil
+	}
+
+	tree, err := cql.Parse(query)
+	if err != nil {
+		return failed(diagnose(DiagQuerySyntax, query, "%s", err.Error())), nil
+	}
+
+	cond, err := Translate(tree)
+	if err != nil {
+		var diag *Diagnostic
+		if errors.As(err, &diag) {
+			return failed(diag), nil
+		}
+		return nil, err
+	}
+
+	limit := maximum
+	if limit == 0 {
+		limit = 1
+	}
+
+	entries, total, err := s.Read.Search(ctx, cond, limit, start-1)
+	if err != nil {
+		if errors.Is(err, books.ErrUnsupported) {
+			return failed(diagnose(DiagUnsupportedOperation, "searchRetrieve", "searches are not available with this storage")), nil
+		}
+		return nil, err
+	}
+
+	res := &SearchRetrieveResponse{
+		NS:                   Namespace,
+		Version:              Version,
+		NumberOfRecords:      total,
+		ResultCountPrecision: exactCount,
+	}
+
+	if total > 0 && start > total {
+		res.Diagnostics = newDiagnostics(diagnose(DiagFirstRecordOutOfRange, strconv.Itoa(start), "startRecord is beyond the last record"))
+		return res, nil
+	}
+
+	if maximum == 0 {
+		return res, nil
+	}
+
+	res.Records = &Records{}
+
+	for i, entry := range entries {
+		rec, err := record(entry, recordSchema, escaping)
+		if err != nil {
+			return nil, err
+		}
+		rec.Position = start + i
+		res.Records.Records = append(res.Records.Records, rec)
+	}
+
+	if next := start + len(entries); next <= total {
+		res.NextRecordPosition = next
+	}
+
+	return res, nil
+}
+
+func record(entry *books.ReadEntry, s schema, escaping string) (Record, error) {
+	var payload interface{}
+
+	switch s.Name {
+	case "marcxml":
+		x := marc.ToXML(marc.FromEntry(entry))
+		x.Xmlns = marc.Namespace
+		payload = x
+	default:
+		payload = dublincore.NewSRWDC(dublincore.FromEntry(entry))
+	}
+
+	rec := Record{Schema: s.Identifier, Escaping: escaping}
+
+	if escaping == "xml" {
+		rec.Data.Payload = payload
+		return rec, nil
+	}
+
+	text, err := xml.Marshal(payload)
+	if err != nil {
+		return rec, err
+	}
+	rec.Data.Text = string(text)
+
+	return rec, nil
+}
+
+func intParameter(args url.Values, key string, defaultValue, min int) (int, *Diagnostic) {
+	raw := args.Get(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < min {
+		return 0, diagnose(DiagUnsupportedParamValue, key, "%s must be an integer of at least %d", key, min)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,246 @@
+package sru
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/cql"
+)
+
+type indexKind int
+
+const (
+	textIndex indexKind = iota
+	creatorIndex
+	subjectIndex
+	yearIndex
+	isbnIndex
+	anywhereIndex
+	allRecordsIndex
+)
+
+// indexes understood in queries, the dc context set being the default one
+var indexes = map[string]indexKind{
+	"dc.title":       textIndex,
+	"dc.publisher":   textIndex,
+	"dc.creator":     creatorIndex,
+	"dc.subject":     subjectIndex,
+	"dc.date":        yearIndex,
+	"dc.identifier":  isbnIndex,
+	cql.ServerChoice: anywhereIndex,
+	"cql.anywhere":   anywhereIndex,
+	"cql.allrecords": allRecordsIndex,
+}
+
+var textFields = map[string]books.TextField{
+	"dc.title":     books.FieldTitle,
+	"dc.publisher": books.FieldPublisher,
+}
+
+// Indexes lists the searchable indexes, for explain.
+func Indexes() []string {
+	return []string{"dc.title", "dc.creator", "dc.publisher", "dc.date", "dc.subject", "dc.identifier", "cql.serverChoice"}
+}
+
+// Translate turns a CQL tree into a books.Condition.
+func Translate(node cql.Node) (books.Condition, error) {
+	switch n := node.(type) {
+	case *cql.Boolean:
+		return boolean(n)
+	case *cql.Clause:
+		return clause(n)
+	}
+	return nil, errors.New("sru: unknown cql node")
+}
+
+func boolean(n *cql.Boolean) (books.Condition, error) {
+	if len(n.Modifiers) > 0 {
+		return nil, diagnose(DiagUnsupportedBoolModifier, n.Modifiers[0].Name, "boolean modifiers are not supported")
+	}
+
+	left, err := Translate(n.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := Translate(n.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "and":
+		return books.And{Left: left, Right: right}, nil
+	case "or":
+		return books.Or{Left: left, Right: right}, nil
+	case "not":
+		return books.And{Left: left, Right: books.Not{Condition: right}}, nil
+	}
+
+	return nil, diagnose(DiagUnsupportedBoolean, n.Op, "boolean operator %q is not supported", n.Op)
+}
+
+func clause(c *cql.Clause) (books.Condition, error) {
+	index := c.Index
+	if !strings.Contains(index, ".") {
+		index = "dc." + index
+	}
+
+	kind, ok := indexes[index]
+	if !ok {
+		return nil, diagnose(DiagUnsupportedIndex, c.Index, "index %q is not supported", c.Index)
+	}
+
+	if len(c.Modifiers) > 0 {
+		return nil, diagnose(DiagUnsupportedRelModifier, c.Modifiers[0].Name, "relation modifiers are not supported")
+	}
+
+	if kind == allRecordsIndex {
+		return books.Everything{}, nil
+	}
+
+	relation := strings.TrimPrefix(c.Relation, "cql.")
+
+	if strings.TrimSpace(c.Term) == "" {
+		return nil, diagnose(DiagEmptyTerm, "", "empty terms are not supported")
+	}
+
+	switch kind {
+	case textIndex:
+		return text(textFields[index], relation, c.Term)
+
+	case creatorIndex:
+		return text(books.FieldCreator, relation, c.Term)
+
+	case subjectIndex:
+		return text(books.FieldSubject, relation, c.Term)
+
+	case yearIndex:
+		return year(relation, c.Term)
+
+	case isbnIndex:
+		return isbn(relation, c.Term)
+
+	case anywhereIndex:
+		var any books.Condition
+		for _, field := range []books.TextField{books.FieldTitle, books.FieldPublisher, books.FieldCreator} {
+			cond, err := text(field, relation, c.Term)
+			if err != nil {
+				return nil, err
+			}
+			if any == nil {
+				any = cond
+			} else {
+				any = books.Or{Left: any, Right: cond}
+			}
+		}
+		return any, nil
+	}
+
+	return nil, diagnose(DiagUnsupportedIndex, c.Index, "index %q is not supported", c.Index)
+}
+
+// text compares a field, <> meaning that no value is equal.
+func text(field books.TextField, relation, term string) (books.Condition, error) {
+	switch relation {
+	case "=", "adj":
+		return books.TextMatch{Field: field, Pattern: pattern(term, true)}, nil
+
+	case "==", "exact":
+		return books.TextMatch{Field: field, Pattern: pattern(term, false)}, nil
+
+	case "<>":
+		return books.Not{Condition: books.TextMatch{Field: field, Pattern: pattern(term, false)}}, nil
+
+	case "all", "any":
+		var cond books.Condition
+		for _, word := range strings.Fields(term) {
+			match := books.TextMatch{Field: field, Pattern: pattern(word, true)}
+			switch {
+			case cond == nil:
+				cond = match
+			case relation == "any":
+				cond = books.Or{Left: cond, Right: match}
+			default:
+				cond = books.And{Left: cond, Right: match}
+			}
+		}
+		return cond, nil
+
+	case "<", ">", "<=", ">=":
+		return books.TextCompare{Field: field, Op: books.Comparison(relation), Value: term}, nil
+	}
+
+	return nil, diagnose(DiagUnsupportedRelation, relation, "relation %q is not supported", relation)
+}
+
+func year(relation, term string) (books.Condition, error) {
+	if relation == "within" {
+		bounds := strings.Fields(term)
+		if len(bounds) != 2 {
+			return nil, diagnose(DiagInvalidTerm, term, "within expects two years")
+		}
+		from, err1 := strconv.Atoi(bounds[0])
+		to, err2 := strconv.Atoi(bounds[1])
+		if err1 != nil || err2 != nil {
+			return nil, diagnose(DiagInvalidTerm, term, "dc.date expects a year")
+		}
+		return books.And{
+			Left:  books.YearCompare{Op: books.GreaterOrEqual, Year: from},
+			Right: books.YearCompare{Op: books.LessOrEqual, Year: to},
+		}, nil
+	}
+
+	year, err := strconv.Atoi(strings.TrimSpace(term))
+	if err != nil {
+		return nil, diagnose(DiagInvalidTerm, term, "dc.date expects a year")
+	}
+
+	switch relation {
+	case "=", "==", "exact":
+		return books.YearCompare{Op: books.Equal, Year: year}, nil
+	case "<>", "<", ">", "<=", ">=":
+		return books.YearCompare{Op: books.Comparison(relation), Year: year}, nil
+	}
+
+	return nil, diagnose(DiagUnsupportedRelation, relation, "relation %q is not supported for dc.date", relation)
+}
+
+func isbn(relation, term string) (books.Condition, error) {
+	switch relation {
+	case "=", "==", "exact":
+		term = strings.TrimPrefix(strings.ToLower(term), "urn:isbn:")
+		return books.ISBNIs{ISBN: books.NormalizeISBN(term)}, nil
+	}
+
+	return nil, diagnose(DiagUnsupportedRelation, relation, "relation %q is not supported for dc.identifier", relation)
+}
+
+// pattern keeps the CQL masking, contains unless anchored with ^.
+func pattern(term string, contains bool) books.Pattern {
+	start, end := contains, contains
+	if strings.HasPrefix(term, "^") {
+		term = term[1:]
+		start = false
+	}
+	if strings.HasSuffix(term, "^") && !strings.HasSuffix(term, `\^`) {
+		term = term[:len(term)-1]
+		end = false
+	}
+
+	//a lone trailing backslash is literal
+	if trailing := len(term) - len(strings.TrimRight(term, `\`)); trailing%2 == 1 {
+		term += `\`
+	}
+
+	if start {
+		term = "*" + term
+	}
+	if end {
+		term += "*"
+	}
+
+	return books.Pattern(term)
+}
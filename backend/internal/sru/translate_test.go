@@ -0,0 +1,106 @@
+package sru
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/cql"
+)
+
+func translate(t *testing.T, query string) (books.Condition, error) {
+	t.Helper()
+
+	node, err := cql.Parse(query)
+	if err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return Translate(node)
+}
+
+func TestTranslate(t *testing.T) {
+	title := func(p books.Pattern) books.Condition {
+		return books.TextMatch{Field: books.FieldTitle, Pattern: p}
+	}
+
+	tests := []struct {
+		query string
+		want  books.Condition
+	}{
+		{`title = dune`, title("*dune*")},
+		{`dc.title == Dune`, title("Dune")},
+		{`title exact "Dune Messiah"`, title("Dune Messiah")},
+		{`title = "^dune"`, title("dune*")},
+		{`title = "dune^"`, title("*dune")},
+		{`title = "dune\^"`, title(`*dune\^*`)},
+		{`title = "dune\\"`, title(`*dune\\*`)},
+		{`title = "50%"`, title("*50%*")},
+		{`title <> dune`, books.Not{Condition: title("dune")}},
+		{`title < m`, books.TextCompare{Field: books.FieldTitle, Op: books.Less, Value: "m"}},
+		{`title any "dune hyperion"`, books.Or{Left: title("*dune*"), Right: title("*hyperion*")}},
+		{`title all "dune messiah"`, books.And{Left: title("*dune*"), Right: title("*messiah*")}},
+		{`publisher = ace`, books.TextMatch{Field: books.FieldPublisher, Pattern: "*ace*"}},
+		{`creator = herbert`, books.TextMatch{Field: books.FieldCreator, Pattern: "*herbert*"}},
+		{`subject == "science fiction"`, books.TextMatch{Field: books.FieldSubject, Pattern: "science fiction"}},
+		{`date = 1965`, books.YearCompare{Op: books.Equal, Year: 1965}},
+		{`date >= 1965`, books.YearCompare{Op: books.GreaterOrEqual, Year: 1965}},
+		{`date within "1960 1970"`, books.And{
+			Left:  books.YearCompare{Op: books.GreaterOrEqual, Year: 1960},
+			Right: books.YearCompare{Op: books.LessOrEqual, Year: 1970},
+		}},
+		{`identifier = "urn:ISBN:0-441-01359-7"`, books.ISBNIs{ISBN: books.NormalizeISBN("0-441-01359-7")}},
+		{`cql.allRecords = 1`, books.Everything{}},
+		{`dune`, books.Or{
+			Left:  books.Or{Left: title("*dune*"), Right: books.TextMatch{Field: books.FieldPublisher, Pattern: "*dune*"}},
+			Right: books.TextMatch{Field: books.FieldCreator, Pattern: "*dune*"},
+		}},
+
+		{`title = a and date = 1 or title = b`, books.Or{
+			Left:  books.And{Left: title("*a*"), Right: books.YearCompare{Op: books.Equal, Year: 1}},
+			Right: title("*b*"),
+		}},
+		{`title = a not title = b`, books.And{Left: title("*a*"), Right: books.Not{Condition: title("*b*")}}},
+	}
+
+	for _, tt := range tests {
+		got, err := translate(t, tt.query)
+		if err != nil {
+			t.Errorf("%s: %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %#v\nwant %#v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestTranslateDiagnostics(t *testing.T) {
+	tests := []struct {
+		query string
+		code  int
+	}{
+		{`dc.format = book`, DiagUnsupportedIndex},
+		{`bath.isbn = 1`, DiagUnsupportedIndex},
+		{`title = a and isbn = 1`, DiagUnsupportedIndex},
+		{`title encloses dune`, DiagUnsupportedRelation},
+		{`title within "a b"`, DiagUnsupportedRelation},
+		{`identifier < 1`, DiagUnsupportedRelation},
+		{`date any 1965`, DiagUnsupportedRelation},
+		{`date = nineteen`, DiagInvalidTerm},
+		{`date within 1965`, DiagInvalidTerm},
+		{`title = ""`, DiagEmptyTerm},
+		{`title =/cql.nowhere dune`, DiagUnsupportedRelModifier},
+		{`a prox b`, DiagUnsupportedBoolean},
+		{`a and/x b`, DiagUnsupportedBoolModifier},
+	}
+
+	for _, tt := range tests {
+		_, err := translate(t, tt.query)
+
+		var diag *Diagnostic
+		if !errors.As(err, &diag) || diag.Code != tt.code {
+			t.Errorf("%s: got %v, want diagnostic %d", tt.query, err, tt.code)
+		}
+	}
+}
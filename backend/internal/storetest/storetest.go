@@ -0,0 +1,627 @@
+// Package storetest holds the test cases every store has to pass.
+package storetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal"
+	books "github.com/3WDeveloper-GM/library_app/backend/internal/Books"
+	"github.com/3WDeveloper-GM/library_app/backend/internal/audit"
+)
+
+// Repositories are the repositories of the store under test.
+type Repositories struct {
+	Read      books.ReadRepository
+	Create    books.CreateRepository
+	Update    books.UpdateRepository
+	Delete    books.DeleteRepository
+	Authors   books.AuthorRepository
+	Links     books.LinkRepository
+	Revisions books.RevisionRepository
+	Audit     audit.Repository
+}
+
+// Run runs every case on an empty store returned by open.
+func Run(t *testing.T, open func(t *testing.T) Repositories) {
+	cases := []struct {
+		name string
+		test func(t *testing.T, r Repositories)
+	}{
+		{"InsertAndGet", testInsertAndGet},
+		{"Rollback", testRollback},
+		{"Update", testUpdate},
+		{"Trash", testTrash},
+		{"MergeAuthors", testMergeAuthors},
+		{"Links", testLinks},
+		{"List", testList},
+		{"Reads", testReads},
+		{"Search", testSearch},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.test(t, open(t))
+		})
+	}
+}
+
+var byID = internal.Filters{Page: 1, PageSize: 10, Sort: "id", SortSafeList: []string{"id"}}
+
+func insert(t *testing.T, r Repositories, title string, authors ...string) *books.ReadEntry {
+	t.Helper()
+
+	entry := &books.CreateBookEntry{
+		Book: &books.Book{
+			Title:     title,
+			Publisher: "Ace",
+			Year:      1965,
+			PageCount: 412,
+			Genres:    []string{"science fiction"},
+		},
+		Authors: &books.Authors{List: authors},
+	}
+	entry.Normalize()
+
+	read := &books.ReadEntry{}
+	if err := r.Create.Save(context.Background(), entry, read); err != nil {
+		t.Fatal(err)
+	}
+
+	return read
+}
+
+func get(r Repositories, id int64) (*books.ReadEntry, error) {
+	read := &books.ReadEntry{Book: books.ReadBook{ID: id}}
+	return read, r.Read.Get(context.Background(), nil, read)
+}
+
+func verify(t *testing.T, r Repositories) {
+	t.Helper()
+
+	problems, err := r.Read.Verify(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Errorf("verify: %s %s %d: %s", p.Check, p.Entity, p.ID, p.Detail)
+	}
+}
+
+func testInsertAndGet(t *testing.T, r Repositories) {
+
+	created := insert(t, r, "Dune", "Frank Herbert")
+	if created.Book.Version != 1 {
+		t.Fatalf("got version %d, want 1", created.Book.Version)
+	}
+
+	read, err := get(r, created.Book.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Book.Title != "Dune" || len(read.List.Name) != 1 || read.List.Name[0] != "FRANK HERBERT" {
+		t.Errorf("got %+v", read)
+	}
+
+	if _, err := get(r, 42); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got %v, want sql.ErrNoRows", err)
+	}
+
+	entry := &books.CreateBookEntry{Book: &books.Book{Title: "Dune"}, Authors: &books.Authors{List: []string{"someone"}}}
+	entry.Normalize()
+	var dup *books.DuplicateError
+	err = r.Create.Save(context.Background(), entry, &books.ReadEntry{})
+	if !errors.As(err, &dup) || dup.ID != created.Book.ID || dup.Trashed {
+		t.Errorf("inserting a book twice: got %v, want a duplicate of %d", err, created.Book.ID)
+	}
+
+	verify(t, r)
+}
+
+func testRollback(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	tx, err := r.Create.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := &books.CreateBookEntry{Book: &books.Book{Title: "Dune"}, Authors: &books.Authors{List: []string{"Frank Herbert"}}}
+	entry.Normalize()
+
+	read := &books.ReadEntry{}
+	if err := r.Create.Insert(ctx, tx, entry, read); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Read.Get(ctx, tx, &books.ReadEntry{Book: books.ReadBook{ID: read.Book.ID}}); err != nil {
+		t.Errorf("the transaction does not see its own insert: %v", err)
+	}
+	if _, err := get(r, read.Book.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("an uncommitted insert is visible: %v", err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("got %v, want sql.ErrTxDone", err)
+	}
+
+	if _, err := get(r, read.Book.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("a rolled back insert is visible: %v", err)
+	}
+
+	//the write lock has to be free again
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	tx, err = r.Create.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Rollback()
+}
+
+func testUpdate(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	created := insert(t, r, "Dune", "Frank Herbert")
+	insert(t, r, "Hyperion", "Dan Simmons")
+
+	current, err := get(r, created.Book.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := "Chilton Books"
+	authors := []string{"FRANK HERBERT", "BRIAN HERBERT"}
+	update := books.BookPatch{Publisher: &publisher, Authors: &authors}.Apply(current)
+
+	if err := r.Update.Save(ctx, update, current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Book.Version != 2 || current.Book.Publisher != publisher || len(current.List.Name) != 2 {
+		t.Errorf("got %+v", current.Book)
+	}
+
+	stale := books.BookPatch{Publisher: &publisher}.Apply(created)
+	if err := r.Update.Save(ctx, stale, created); !errors.Is(err, books.ErrEditConflict) {
+		t.Errorf("got %v, want ErrEditConflict", err)
+	}
+
+	revisions, err := r.Revisions.List(ctx, created.Book.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[0].Version != 2 || revisions[1].Version != 1 {
+		t.Errorf("got %d revisions, want versions 2 and 1", len(revisions))
+	}
+	if _, err := r.Revisions.Get(ctx, created.Book.ID, 3); !errors.Is(err, books.ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+	if previous, err := r.Revisions.Previous(ctx, created.Book.ID, 9); err != nil || previous.Version != 2 {
+		t.Errorf("got %v, %v before version 9, want version 2", previous, err)
+	}
+	if _, err := r.Revisions.Previous(ctx, created.Book.ID, 1); !errors.Is(err, books.ErrNotFound) {
+		t.Errorf("got %v before version 1, want ErrNotFound", err)
+	}
+
+	verify(t, r)
+}
+
+func testTrash(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	dune := insert(t, r, "Dune", "Frank Herbert")
+	insert(t, r, "Children of Dune", "Frank Herbert")
+
+	run := func(fn func(tx books.Tx) error) error {
+		tx, err := r.Delete.BeginTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	id := &books.DeleteID{ID: dune.Book.ID}
+
+	if err := run(func(tx books.Tx) error { return r.Delete.Purge(ctx, tx, id) }); !errors.Is(err, books.ErrNotFound) {
+		t.Errorf("purging a live book: got %v, want ErrNotFound", err)
+	}
+
+	if err := run(func(tx books.Tx) error { return r.Delete.DeleteBook(ctx, tx, id) }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := get(r, dune.Book.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("a trashed book is visible: %v", err)
+	}
+
+	again := &books.CreateBookEntry{Book: &books.Book{Title: "Dune", Publisher: "Ace"}, Authors: &books.Authors{List: []string{"Frank Herbert"}}}
+	again.Normalize()
+
+	var dup *books.DuplicateError
+	err := r.Create.Save(ctx, again, &books.ReadEntry{})
+	if !errors.As(err, &dup) || dup.ID != dune.Book.ID || !dup.Trashed {
+		t.Errorf("inserting a trashed book again: got %v, want a duplicate in the trash", err)
+	}
+
+	trashed, _, err := r.Delete.ListTrash(ctx, byID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != dune.Book.ID {
+		t.Errorf("got %d trashed books, want Dune", len(trashed))
+	}
+
+	authors, _, err := r.Authors.ListAuthors(ctx, byID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(authors) != 1 || authors[0].BooksAuthored != 1 {
+		t.Errorf("got %+v, want one author with one book", authors)
+	}
+
+	verify(t, r)
+
+	if err := run(func(tx books.Tx) error { return r.Delete.Restore(ctx, tx, id) }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := get(r, dune.Book.ID); err != nil {
+		t.Errorf("a restored book is not visible: %v", err)
+	}
+
+	verify(t, r)
+
+	if err := run(func(tx books.Tx) error { return r.Delete.DeleteBook(ctx, tx, id) }); err != nil {
+		t.Fatal(err)
+	}
+
+	var purged int64
+	err = run(func(tx books.Tx) (err error) {
+		purged, err = r.Delete.PurgeDeletedBefore(ctx, tx, time.Now().Add(time.Hour))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("purged %d books, want 1", purged)
+	}
+
+	existing, err := r.Create.ExistingHashes(ctx, []string{dune.Book.Hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing[dune.Book.Hash] {
+		t.Error("the hash of a purged book is still taken")
+	}
+
+	verify(t, r)
+
+	actions := []string{}
+	entries, _, err := r.Audit.List(ctx, audit.Filter{EntityID: dune.Book.ID}, byID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+
+	want := []string{audit.ActionInsert, audit.ActionDelete, audit.ActionRestore, audit.ActionDelete, audit.ActionPurge}
+	if len(actions) != len(want) {
+		t.Fatalf("got audit actions %v, want %v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Fatalf("got audit actions %v, want %v", actions, want)
+		}
+	}
+}
+
+func testMergeAuthors(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	insert(t, r, "Dune", "Frank Herbert")
+	insert(t, r, "Dune Messiah", "Frank Herbrt")
+	both := insert(t, r, "The Dosadi Experiment", "Frank Herbert", "Frank Herbrt")
+
+	into, from := both.List.ID[0], both.List.ID[1]
+
+	tx, err := r.Authors.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	merged, err := r.Authors.MergeAuthors(ctx, tx, into, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if merged.Books_authored != 3 {
+		t.Errorf("the merged author has %d books, want 3", merged.Books_authored)
+	}
+
+	read, err := get(r, both.Book.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.List.ID) != 1 || read.List.ID[0] != into {
+		t.Errorf("got authors %v, want only %d", read.List.ID, into)
+	}
+
+	err = r.Authors.AuthorGet(ctx, nil, &books.ReadAuthorEntry{Author: books.ReadAuthor{ID: from}})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got %v, want sql.ErrNoRows for the merged author", err)
+	}
+
+	verify(t, r)
+}
+
+// foreignTx is a transaction no store began.
+type foreignTx struct{}
+
+func (foreignTx) Commit() error   { return nil }
+func (foreignTx) Rollback() error { return nil }
+
+func testLinks(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	dune := insert(t, r, "Dune", "Frank Herbert")
+	hyperion := insert(t, r, "Hyperion", "Dan Simmons")
+	herbert, simmons := dune.List.Identifier[0], hyperion.List.Identifier[0]
+
+	linked := func(tx books.Tx) string {
+		t.Helper()
+
+		hashes, err := r.Links.Linked(ctx, tx, dune.Book.Hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Join(hashes, ",")
+	}
+
+	if got := linked(nil); got != herbert {
+		t.Errorf("got %s, want %s", got, herbert)
+	}
+
+	tx, err := r.Links.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Links.Link(ctx, tx, dune.Book.Hash, []string{simmons, herbert}); err != nil {
+		t.Fatal(err)
+	}
+	if got := linked(tx); got != herbert+","+simmons {
+		t.Errorf("after linking: got %s, want %s,%s", got, herbert, simmons)
+	}
+
+	if err := r.Links.Unlink(ctx, tx, dune.Book.Hash, []string{herbert}); err != nil {
+		t.Fatal(err)
+	}
+	if got := linked(tx); got != simmons {
+		t.Errorf("after unlinking: got %s, want %s", got, simmons)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got := linked(nil); got != herbert {
+		t.Errorf("after the rollback: got %s, want %s", got, herbert)
+	}
+
+	tx, err = r.Links.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Links.Link(ctx, tx, dune.Book.Hash, []string{"no such author"}); err == nil {
+		t.Error("linking an unknown author succeeded")
+	}
+	tx.Rollback()
+
+	if err := r.Links.Link(ctx, foreignTx{}, dune.Book.Hash, []string{simmons}); !errors.Is(err, books.ErrForeignTx) {
+		t.Errorf("got %v for a foreign transaction, want ErrForeignTx", err)
+	}
+
+	verify(t, r)
+}
+
+func testList(t *testing.T, r Repositories) {
+
+	for _, title := range []string{"Dune", "Hyperion", "Anathem"} {
+		insert(t, r, title, "Someone")
+	}
+
+	filters := internal.Filters{Page: 1, PageSize: 2, Sort: "-title", SortSafeList: []string{"title", "-title"}}
+
+	entries, metadata, err := r.Read.List(context.Background(), books.ListFilter{}, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Book.Title != "Hyperion" || entries[1].Book.Title != "Dune" {
+		t.Errorf("got %d entries, want Hyperion and Dune", len(entries))
+	}
+	if metadata.TotalRecords != 3 || metadata.LastPage != 2 {
+		t.Errorf("got %+v, want 3 records over 2 pages", metadata)
+	}
+
+	entries, _, err = r.Read.List(context.Background(), books.ListFilter{Query: "hyper"}, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d entries for q=hyper, want 1", len(entries))
+	}
+}
+
+func testReads(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	dune := insert(t, r, "Dune", "Frank Herbert")
+	hyperion := insert(t, r, "Hyperion", "Dan Simmons")
+
+	many, err := r.Read.GetMany(ctx, []int64{hyperion.Book.ID, dune.Book.ID, hyperion.Book.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(many) != 2 || many[0].Book.ID != hyperion.Book.ID || many[1].Book.ID != dune.Book.ID {
+		t.Errorf("got %d books, want Hyperion and Dune in that order", len(many))
+	}
+
+	counts, err := r.Read.GenreCounts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 1 || counts[0].Genre != dune.Book.Genres[0] || counts[0].Books != 2 {
+		t.Errorf("got genre counts %+v, want two books of %q", counts, dune.Book.Genres[0])
+	}
+
+	genres, err := r.Read.Genres(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(genres) != 1 || genres[0] != dune.Book.Genres[0] {
+		t.Errorf("got genres %v", genres)
+	}
+
+	streamed := 0
+	err = r.Read.Stream(ctx, books.ExportFilter{Genre: dune.Book.Genres[0]}, func(entry *books.ReadEntry) error {
+		if len(entry.List.Name) != 1 {
+			t.Errorf("book %d was streamed with %d authors", entry.Book.ID, len(entry.List.Name))
+		}
+		streamed++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streamed != 2 {
+		t.Errorf("streamed %d books, want 2", streamed)
+	}
+
+	byAuthor, err := r.Authors.BooksByAuthors(ctx, []int64{dune.List.ID[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written := byAuthor[dune.List.ID[0]]; len(written) != 1 || written[0].Book.Title != "Dune" {
+		t.Errorf("got %d books by %s, want Dune", len(written), dune.List.Name[0])
+	}
+
+	records, err := r.Read.Harvest(ctx, books.HarvestFilter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Deleted || records[0].Datestamp.IsZero() {
+		t.Errorf("got %d harvest records, want the two live books", len(records))
+	}
+
+	earliest, err := r.Read.EarliestDatestamp(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if earliest.IsZero() {
+		t.Error("the earliest datestamp is zero")
+	}
+}
+
+func testSearch(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	for _, book := range []*books.Book{
+		{Title: "Dune", Publisher: "Chilton", Year: 1965, Genres: []string{"science fiction"}, ISBN: "9780441013593"},
+		{Title: "Hyperion", Publisher: "Doubleday", Year: 1989, Genres: []string{"science fiction", "space opera"}},
+		{Title: "Cien años de soledad", Publisher: "Sudamericana", Year: 1967, Genres: []string{"magic realism"}},
+		{Title: "The 50% Solution", Publisher: "Ace", Year: 2001, Genres: []string{"humour"}},
+	} {
+		authors := map[string]string{"Dune": "Frank Herbert", "Hyperion": "Dan Simmons"}[book.Title]
+		if authors == "" {
+			authors = "Someone"
+		}
+
+		entry := &books.CreateBookEntry{Book: book, Authors: &books.Authors{List: []string{authors}}}
+		entry.Normalize()
+		if err := r.Create.Save(ctx, entry, &books.ReadEntry{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	title := func(p books.Pattern) books.Condition {
+		return books.TextMatch{Field: books.FieldTitle, Pattern: p}
+	}
+	scifi := books.TextMatch{Field: books.FieldSubject, Pattern: "science fiction"}
+
+	tests := []struct {
+		name string
+		cond books.Condition
+		want []string
+	}{
+		{"everything", books.Everything{}, []string{"Dune", "Hyperion", "Cien años de soledad", "The 50% Solution"}},
+		{"contains", title("*UN*"), []string{"Dune"}},
+		{"whole title", title("dune"), []string{"Dune"}},
+		{"no partial whole title", title("dun"), nil},
+		{"literal percent", title("*50%*"), []string{"The 50% Solution"}},
+		{"percent is no wildcard", title("*e%*"), nil},
+		{"single character", title("h?perion"), []string{"Hyperion"}},
+		{"escaped mask", title(`dune\*`), nil},
+		{"accents", title("*años*"), []string{"Cien años de soledad"}},
+		{"creator", books.TextMatch{Field: books.FieldCreator, Pattern: "*simmons"}, []string{"Hyperion"}},
+		{"subject", books.TextMatch{Field: books.FieldSubject, Pattern: "space opera"}, []string{"Hyperion"}},
+		{"not", books.Not{Condition: scifi}, []string{"Cien años de soledad", "The 50% Solution"}},
+		{"and", books.And{Left: scifi, Right: books.YearCompare{Op: books.Less, Year: 1970}}, []string{"Dune"}},
+		{"or", books.Or{Left: title("hyperion"), Right: books.TextMatch{Field: books.FieldPublisher, Pattern: "ace"}}, []string{"Hyperion", "The 50% Solution"}},
+		{"year range", books.And{
+			Left:  books.YearCompare{Op: books.GreaterOrEqual, Year: 1965},
+			Right: books.YearCompare{Op: books.LessOrEqual, Year: 1967},
+		}, []string{"Dune", "Cien años de soledad"}},
+		{"year not equal", books.YearCompare{Op: books.NotEqual, Year: 1965}, []string{"Hyperion", "Cien años de soledad", "The 50% Solution"}},
+		{"ordering", books.TextCompare{Field: books.FieldTitle, Op: books.Less, Value: "E"}, []string{"Dune", "Cien años de soledad"}},
+		{"isbn", books.ISBNIs{ISBN: "9780441013593"}, []string{"Dune"}},
+	}
+
+	for _, tt := range tests {
+		entries, total, err := r.Read.Search(ctx, tt.cond, 10, 0)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		got := []string{}
+		for _, entry := range entries {
+			got = append(got, entry.Book.Title)
+		}
+		if total != len(tt.want) || strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: got %v (%d in total), want %v", tt.name, got, total, tt.want)
+		}
+	}
+
+	entries, total, err := r.Read.Search(ctx, books.Everything{}, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || len(entries) != 2 || entries[0].Book.Title != "Hyperion" || len(entries[0].List.Name) != 1 {
+		t.Errorf("got %d entries of %d, want Hyperion and the next one of 4", len(entries), total)
+	}
+
+	entries, total, err = r.Read.Search(ctx, books.Everything{}, 2, 10)
+	if err != nil || len(entries) != 0 || total != 4 {
+		t.Errorf("past the end: got %d entries of %d, %v", len(entries), total, err)
+	}
+
+	_, _, err = r.Read.Search(ctx, books.TextCompare{Field: books.FieldTitle, Op: "= 1 OR 1 =", Value: "x"}, 10, 0)
+	if !errors.Is(err, books.ErrUnsupported) {
+		t.Errorf("got %v for an unknown comparison, want ErrUnsupported", err)
+	}
+}
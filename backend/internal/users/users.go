@@ -0,0 +1,101 @@
+// Package users keeps the accounts of the cataloguers, tokens are stored hashed.
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/3WDeveloper-GM/library_app/backend/internal/validator"
+)
+
+var (
+	ErrNotFound      = errors.New("users: no user holds the token")
+	ErrDuplicateName = errors.New("users: the name is taken")
+)
+
+// reservedNames are the actors that are not users.
+var reservedNames = []string{"admin", "anonymous", "system"}
+
+// UnverifiedPrefix marks the actors that clients named themselves.
+const UnverifiedPrefix = "unverified:"
+
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func ValidateName(v *validator.Validator, name string) {
+	v.Check(name != "", "name", "must be provided")
+	v.Check(len(name) <= 100, "name", "must not be more than 100 bytes long")
+	v.Check(!v.In(strings.ToLower(name), reservedNames), "name", "is reserved")
+	v.Check(!strings.HasPrefix(name, UnverifiedPrefix), "name", "must not start with "+UnverifiedPrefix)
+}
+
+// NewToken returns a random token and the hash to store for it.
+func NewToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type Repository interface {
+	Create(ctx context.Context, user *User, tokenHash string) error
+	ByTokenHash(ctx context.Context, tokenHash string) (*User, error)
+}
+
+// UserModel runs on CockroachDB and SQLite alike.
+type UserModel struct {
+	DB *sql.DB
+}
+
+func (m *UserModel) Create(ctx context.Context, user *User, tokenHash string) error {
+	query := `
+	INSERT INTO users(name, token_hash)
+	VALUES($1, $2)
+	ON CONFLICT (name) DO NOTHING
+	RETURNING id
+	`
+
+	err := m.DB.QueryRowContext(ctx, query, user.Name, tokenHash).Scan(&user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrDuplicateName
+	}
+
+	return err
+}
+
+func (m *UserModel) ByTokenHash(ctx context.Context, tokenHash string) (*User, error) {
+	query := `
+	SELECT id, name
+	FROM users
+	WHERE token_hash = $1
+	`
+
+	var user User
+	err := m.DB.QueryRowContext(ctx, query, tokenHash).Scan(&user.ID, &user.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+var _ Repository = (*UserModel)(nil)
@@ -0,0 +1,4 @@
+// Package proto holds the protobuf definitions of the gRPC api.
+package proto
+
+//go:generate buf generate
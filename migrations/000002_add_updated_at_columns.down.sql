@@ -0,0 +1,2 @@
+ALTER TABLE authors DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE books DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+   id serial PRIMARY KEY,
+   created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+   actor text NOT NULL,
+   request_id text NOT NULL DEFAULT '',
+   entity text NOT NULL,
+   entity_id bigint NOT NULL,
+   action text NOT NULL,
+   before jsonb,
+   after jsonb
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity, entity_id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+
+-- the log is append only, mistakes are corrected by later entries
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+   RAISE EXCEPTION 'audit_log is append only';
+END;
+$$ LANGUAGE PLpgSQL;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
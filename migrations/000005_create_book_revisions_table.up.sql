@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS book_revisions (
+   book_id integer NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+   version integer NOT NULL,
+   created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+   actor text NOT NULL,
+   snapshot jsonb NOT NULL,
+   PRIMARY KEY (book_id, version)
+);
+
+-- the books already there start their history at their current version
+INSERT INTO book_revisions(book_id, version, created_at, actor, snapshot)
+SELECT
+   b.id,
+   b.version,
+   b.updated_at,
+   'system',
+   jsonb_build_object(
+      'title', b.title,
+      'publisher', b.publisher,
+      'year', b.year,
+      'page_count', b.page_count,
+      'genres', to_jsonb(b.genres),
+      'version', b.version,
+      'authors', (
+         SELECT jsonb_agg(a.name ORDER BY bal.id)
+         FROM book_author_link bal JOIN authors a ON a.author_id = bal.author_id
+         WHERE bal.book_id = b.book_id
+      )
+   )
+FROM books b
+ON CONFLICT (book_id, version) DO NOTHING;
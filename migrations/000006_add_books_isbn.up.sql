@@ -0,0 +1,3 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS isbn text NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS books_isbn_idx ON books (isbn);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS users (
+   id serial PRIMARY KEY,
+   created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+   name text NOT NULL UNIQUE,
+   token_hash text NOT NULL UNIQUE
+);
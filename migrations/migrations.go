@@ -0,0 +1,16 @@
+// Package migrations embeds the SQL migrations of the catalogue database.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLite holds the migrations of the SQLite catalogue.
+var SQLite, _ = fs.Sub(sqliteFS, "sqlite")
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS book_revisions;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS book_author_link;
+DROP TABLE IF EXISTS authors;
+DROP TABLE IF EXISTS books;
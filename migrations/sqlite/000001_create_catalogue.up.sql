@@ -0,0 +1,71 @@
+CREATE TABLE IF NOT EXISTS books (
+   id integer PRIMARY KEY AUTOINCREMENT,
+   book_id text UNIQUE NOT NULL,
+   created_at text NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+   title text NOT NULL,
+   publisher text NOT NULL,
+   year integer NOT NULL,
+   page_count integer NOT NULL,
+   genres text NOT NULL DEFAULT '[]' CHECK (json_type(genres) = 'array'),
+   isbn text NOT NULL DEFAULT '',
+   version integer NOT NULL DEFAULT 1,
+   updated_at text NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+   deleted_at text
+);
+
+CREATE INDEX IF NOT EXISTS books_deleted_at_idx ON books (deleted_at);
+CREATE INDEX IF NOT EXISTS books_isbn_idx ON books (isbn);
+
+CREATE TABLE IF NOT EXISTS authors (
+   id integer PRIMARY KEY AUTOINCREMENT,
+   author_id text UNIQUE NOT NULL,
+   name text NOT NULL,
+   books_authored integer NOT NULL DEFAULT 1,
+   created_at text NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+   updated_at text NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS book_author_link (
+   id integer PRIMARY KEY AUTOINCREMENT,
+   book_id text NOT NULL REFERENCES books(book_id) ON DELETE CASCADE ON UPDATE CASCADE,
+   author_id text NOT NULL REFERENCES authors(author_id) ON DELETE CASCADE ON UPDATE CASCADE,
+   UNIQUE (book_id, author_id)
+);
+
+CREATE INDEX IF NOT EXISTS book_author_link_author_idx ON book_author_link (author_id);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+   id integer PRIMARY KEY AUTOINCREMENT,
+   created_at text NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+   actor text NOT NULL,
+   request_id text NOT NULL DEFAULT '',
+   entity text NOT NULL,
+   entity_id integer NOT NULL,
+   action text NOT NULL,
+   before text,
+   after text
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity, entity_id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+
+-- the log is append only, mistakes are corrected by later entries
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+   SELECT RAISE(ABORT, 'audit_log is append only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+   SELECT RAISE(ABORT, 'audit_log is append only');
+END;
+
+CREATE TABLE IF NOT EXISTS book_revisions (
+   book_id integer NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+   version integer NOT NULL,
+   created_at text NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+   actor text NOT NULL,
+   snapshot text NOT NULL,
+   PRIMARY KEY (book_id, version)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS users (
+   id integer PRIMARY KEY AUTOINCREMENT,
+   created_at text NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+   name text NOT NULL UNIQUE,
+   token_hash text NOT NULL UNIQUE
+);